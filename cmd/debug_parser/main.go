@@ -2,15 +2,40 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
 	"os/exec"
 	"sort"
+	"strconv"
 	"strings"
 
 	"powermetrics-tui/internal/models"
 	"powermetrics-tui/internal/parser"
 )
 
+// watchFlag is a comma-separated list of PIDs to watch closely for false
+// "exited" positives, e.g. "--watch pid:65560,pid:2089,pid:2579". It
+// replaces what used to be a hardcoded watchPIDs slice edited in source
+// for each investigation.
+var watchFlag = flag.String("watch", "", "Comma-separated pid:<n> entries to watch for false exit positives, e.g. pid:65560,pid:2089")
+
+// parseWatchPIDs parses watchFlag's "pid:<n>,pid:<n>,..." syntax into a
+// plain PID list. Unrecognized or malformed entries are skipped rather than
+// failing the whole tool, since this is a debugging aid, not the main TUI.
+func parseWatchPIDs(spec string) []int {
+	var pids []int
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if !strings.HasPrefix(entry, "pid:") {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimPrefix(entry, "pid:")); err == nil {
+			pids = append(pids, n)
+		}
+	}
+	return pids
+}
+
 type ProcessDelta struct {
 	Added   []ProcessInfo
 	Removed []ProcessInfo
@@ -25,14 +50,17 @@ type ProcessInfo struct {
 }
 
 func main() {
+	flag.Parse()
+
 	fmt.Println("=== PowerMetrics Process Delta Analyzer ===")
 	fmt.Println("This tool analyzes process parsing consistency")
 	fmt.Println("It verifies if 'missing' processes are truly dead using ps")
 	fmt.Println("Press Ctrl+C to stop")
 	fmt.Println()
 
-	// PIDs to specifically watch - from user's report of false positives
-	watchPIDs := []int{65560, 2089, 2579}
+	// PIDs to specifically watch, e.g. from a user's report of false
+	// positives; set with --watch pid:65560,pid:2089,pid:2579.
+	watchPIDs := parseWatchPIDs(*watchFlag)
 	if len(watchPIDs) > 0 {
 		fmt.Printf("Watching specific PIDs: %v\n", watchPIDs)
 		fmt.Printf("These are reported as dead but are actually alive\n\n")
@@ -0,0 +1,34 @@
+package alerts
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the top-level shape of the alerts YAML file.
+type Config struct {
+	Rules   []Rule `yaml:"rules"`
+	LogFile string `yaml:"log_file"`
+	Notify  bool   `yaml:"notify"`
+
+	// Webhook, if set, receives a JSON POST of every fired Event, for
+	// forwarding alerts to something like a Slack incoming-webhook or an
+	// internal on-call tool. Best-effort, like Notify/Hook: a failing or
+	// slow endpoint never blocks alert evaluation.
+	Webhook string `yaml:"webhook"`
+}
+
+// LoadConfig reads and parses a YAML rules file from disk.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
@@ -0,0 +1,488 @@
+// Package alerts evaluates threshold rules against a models.MetricsState
+// sample and surfaces fired alerts as a bounded in-memory log, optionally
+// mirrored to a JSON-lines file, desktop notifications, a shell hook, and a
+// webhook POST, any of which can be silenced at runtime with ToggleMute.
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"powermetrics-tui/internal/models"
+)
+
+// Event is a single fired alert, ready for display in the "Alerts" pane.
+type Event struct {
+	Time     time.Time `json:"time"`
+	Rule     string    `json:"rule"`
+	Message  string    `json:"message"`
+	Severity string    `json:"severity"`
+
+	// Entity names the offending coalition/process for a ScopeCoalition/
+	// ScopeProcess rule, empty for ScopeGlobal rules.
+	Entity string `json:"entity,omitempty"`
+}
+
+// Sink receives every fired Event in addition to the engine's built-in ring
+// buffer, log file, and notify/hook handling. Register one with AddSink to
+// forward alerts elsewhere (e.g. a remote log aggregator) without polling
+// Events().
+type Sink interface {
+	LogAlert(Event)
+}
+
+// ringSize bounds how many events the in-TUI log keeps in memory.
+const ringSize = 200
+
+type ruleState struct {
+	armed         bool
+	exceededSince time.Time
+
+	// highestLevel is the highest Rule.Levels entry already fired for this
+	// key; zero means no level has fired yet. Unused by non-leveled rules.
+	highestLevel float64
+}
+
+// Engine holds per-rule arm/disarm state across samples and the ring buffer
+// of fired events.
+type Engine struct {
+	mu      sync.Mutex
+	cfg     *Config
+	states  map[string]*ruleState
+	ring    []Event
+	logFile *os.File
+	sinks   []Sink
+	muted   bool
+
+	// whereCache memoizes each Where rule's compiled regex by rule name, so
+	// it's parsed once rather than on every sample.
+	whereCache map[string]*regexp.Regexp
+}
+
+// NewEngine builds an Engine from cfg. If cfg.LogFile is set, it is opened
+// for appending; failures to open it are non-fatal (events still reach the
+// in-memory ring).
+func NewEngine(cfg *Config) *Engine {
+	e := &Engine{
+		cfg:    cfg,
+		states: make(map[string]*ruleState),
+	}
+	if cfg.LogFile != "" {
+		if f, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644); err == nil {
+			e.logFile = f
+		}
+	}
+	return e
+}
+
+// Evaluate checks every rule against the current sample in state and
+// records newly fired/cleared events. Call this once per finalized sample,
+// e.g. from StateMachine.FinalizeCurrentState. ScopeCoalition/ScopeProcess
+// rules are checked against every tracked coalition/process, each with its
+// own independent arm/disarm (or leveled) state.
+func (e *Engine) Evaluate(state *models.MetricsState) {
+	now := time.Now()
+
+	state.Mu.RLock()
+	numeric, text, temps := snapshot(state)
+	coalitions := append([]models.ProcessCoalition(nil), state.Coalitions...)
+	processes := append([]models.ProcessInfo(nil), state.Processes...)
+	livePIDs := make(map[int]time.Time, len(state.LastSeenPIDs))
+	for pid, seen := range state.LastSeenPIDs {
+		livePIDs[pid] = seen
+	}
+	state.Mu.RUnlock()
+
+	for _, rule := range e.cfg.Rules {
+		switch rule.Scope {
+		case ScopeCoalition:
+			for _, co := range coalitions {
+				if !e.whereMatches(rule, co.Name) {
+					continue
+				}
+				value, ok := coalitionMetric(rule.Metric, co)
+				if !ok {
+					continue
+				}
+				key := fmt.Sprintf("%s/coalition/%d", rule.Name, co.CoalitionID)
+				e.evaluateNumeric(rule, key, co.Name, value, coalitionHistory(rule.Metric, co), now)
+			}
+		case ScopeProcess:
+			for _, p := range processes {
+				if !e.whereMatches(rule, p.Name) {
+					continue
+				}
+				value, ok := processMetric(rule.Metric, p)
+				if !ok {
+					continue
+				}
+				key := fmt.Sprintf("%s/process/%d", rule.Name, p.PID)
+				e.evaluateNumeric(rule, key, p.Name, value, processHistory(rule.Metric, p), now)
+			}
+		default:
+			if rule.Equals != "" {
+				e.evaluateEquals(rule, rule.Name, "", text[rule.Metric], now)
+				continue
+			}
+			metric := rule.Metric
+			if rule.UseSmoothed {
+				metric += "Smoothed"
+			}
+			value := numeric[metric]
+			if sensor, ok := rule.temperatureSensor(); ok {
+				value = temps[sensor]
+			}
+			e.evaluateNumeric(rule, rule.Name, "", value, nil, now)
+		}
+	}
+
+	e.pruneExitedPIDs(livePIDs)
+}
+
+// state returns the ruleState for key, creating it on first use.
+func (e *Engine) state(key string) *ruleState {
+	st := e.states[key]
+	if st == nil {
+		st = &ruleState{}
+		e.states[key] = st
+	}
+	return st
+}
+
+// whereMatches reports whether name satisfies rule.Where, or true if Where
+// is unset/malformed (no filtering).
+func (e *Engine) whereMatches(rule Rule, name string) bool {
+	pattern, ok := rule.whereNamePattern()
+	if !ok {
+		return true
+	}
+	re, cached := e.whereCache[rule.Name]
+	if !cached {
+		re, _ = regexp.Compile(pattern)
+		if e.whereCache == nil {
+			e.whereCache = make(map[string]*regexp.Regexp)
+		}
+		e.whereCache[rule.Name] = re
+	}
+	if re == nil {
+		return true
+	}
+	return re.MatchString(name)
+}
+
+// processStateKeyPID extracts the PID from a ScopeProcess state key
+// ("<rule>/process/<pid>"), returning ok=false for any other key shape.
+func processStateKeyPID(key string) (int, bool) {
+	const marker = "/process/"
+	idx := strings.LastIndex(key, marker)
+	if idx == -1 {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(key[idx+len(marker):])
+	if err != nil {
+		return 0, false
+	}
+	return pid, true
+}
+
+// pruneExitedPIDs drops ScopeProcess rule state for any PID no longer in
+// livePIDs, so a reused PID doesn't inherit a dead process's armed/leveled
+// state and a one-off spike from a short-lived process doesn't linger
+// forever in memory.
+func (e *Engine) pruneExitedPIDs(livePIDs map[int]time.Time) {
+	for key := range e.states {
+		pid, ok := processStateKeyPID(key)
+		if !ok {
+			continue
+		}
+		if _, stillSeen := livePIDs[pid]; !stillSeen {
+			delete(e.states, key)
+		}
+	}
+}
+
+// evaluateNumeric runs rule against value under key/entity, using leveled
+// crossing if rule.Levels is set and arm/disarm hysteresis otherwise. history
+// is the entity's CPUHistory/MemoryHistory tail, consulted only when
+// rule.SustainedSamples is set; it's nil for ScopeGlobal rules, which have
+// no such per-sample history to consult.
+func (e *Engine) evaluateNumeric(rule Rule, key, entity string, value float64, history []float64, now time.Time) {
+	st := e.state(key)
+
+	if len(rule.Levels) > 0 {
+		if level, ok := rule.highestLevelCrossed(value); ok {
+			if level > st.highestLevel {
+				st.highestLevel = level
+				e.fire(rule, value, "", entity, level, now)
+			}
+		} else if rule.belowLowestLevel(value) {
+			st.highestLevel = 0
+		}
+		return
+	}
+
+	holds := rule.matches(value)
+	if rule.SustainedSamples > 0 && rule.Scope != ScopeGlobal {
+		holds = holds && rule.sustainedRun(history)
+	}
+	e.evaluateHysteresis(rule, st, holds, value, "", entity, now)
+}
+
+// evaluateEquals is evaluateNumeric's string-comparison counterpart for
+// rules that test Rule.Equals instead of a numeric Threshold.
+func (e *Engine) evaluateEquals(rule Rule, key, entity, textValue string, now time.Time) {
+	st := e.state(key)
+	e.evaluateHysteresis(rule, st, textValue == rule.Equals, 0, textValue, entity, now)
+}
+
+// evaluateHysteresis holds the arm/disarm bookkeeping shared by numeric and
+// Equals rules: a rule fires once the condition has held for rule.For, and
+// re-arms once it clears back past the threshold (or disarm_threshold).
+func (e *Engine) evaluateHysteresis(rule Rule, st *ruleState, holds bool, value float64, textValue, entity string, now time.Time) {
+	if !st.armed {
+		if holds {
+			if st.exceededSince.IsZero() {
+				st.exceededSince = now
+			}
+			if now.Sub(st.exceededSince) >= rule.For {
+				st.armed = true
+				e.fire(rule, value, textValue, entity, rule.Threshold, now)
+			}
+		} else {
+			st.exceededSince = time.Time{}
+		}
+		return
+	}
+
+	cleared := rule.Equals != "" && textValue != rule.Equals
+	if rule.Equals == "" {
+		cleared = rule.clears(value)
+	}
+	if cleared {
+		st.armed = false
+		st.exceededSince = time.Time{}
+	}
+}
+
+// coalitionMetric looks up the ProcessCoalition field a ScopeCoalition rule
+// names, returning ok=false for a metric coalitions don't expose.
+func coalitionMetric(metric string, co models.ProcessCoalition) (float64, bool) {
+	switch metric {
+	case "CPUPercent":
+		return co.CPUPercent, true
+	case "MemoryMB":
+		return co.MemoryMB, true
+	default:
+		return 0, false
+	}
+}
+
+// processMetric looks up the ProcessInfo field a ScopeProcess rule names,
+// returning ok=false for a metric processes don't expose.
+func processMetric(metric string, p models.ProcessInfo) (float64, bool) {
+	switch metric {
+	case "CPUPercent":
+		return p.CPUPercent, true
+	case "MemoryMB":
+		return p.MemoryMB, true
+	case "Wakeups":
+		return p.Wakeups, true
+	default:
+		return 0, false
+	}
+}
+
+// coalitionHistory returns the sample history backing a ScopeCoalition
+// rule's metric, for SustainedSamples to check the tail of; nil for a
+// metric with no history counterpart (e.g. Wakeups has none for
+// coalitions).
+func coalitionHistory(metric string, co models.ProcessCoalition) []float64 {
+	switch metric {
+	case "CPUPercent":
+		return co.CPUHistory
+	case "MemoryMB":
+		return co.MemoryHistory
+	default:
+		return nil
+	}
+}
+
+// processHistory is coalitionHistory's ScopeProcess counterpart.
+func processHistory(metric string, p models.ProcessInfo) []float64 {
+	switch metric {
+	case "CPUPercent":
+		return p.CPUHistory
+	case "MemoryMB":
+		return p.MemoryHistory
+	default:
+		return nil
+	}
+}
+
+func (e *Engine) fire(rule Rule, value float64, textValue, entity string, threshold float64, when time.Time) {
+	severity := rule.Severity
+	if severity == "" {
+		severity = "warning"
+	}
+
+	msg := fmt.Sprintf("%s %s %.1f (threshold %.1f)", rule.Metric, rule.Comparison, value, threshold)
+	if rule.Equals != "" {
+		msg = fmt.Sprintf("%s == %s", rule.Metric, textValue)
+	}
+	if entity != "" {
+		msg = fmt.Sprintf("%s: %s", entity, msg)
+	}
+
+	event := Event{Time: when, Rule: rule.Name, Message: msg, Severity: severity, Entity: entity}
+
+	e.mu.Lock()
+	e.ring = append(e.ring, event)
+	if len(e.ring) > ringSize {
+		e.ring = e.ring[len(e.ring)-ringSize:]
+	}
+	sinks := append([]Sink(nil), e.sinks...)
+	muted := e.muted
+	e.mu.Unlock()
+
+	if e.logFile != nil {
+		if line, err := json.Marshal(event); err == nil {
+			fmt.Fprintln(e.logFile, string(line))
+		}
+	}
+	// Muting silences the interruptive notifiers (desktop popup, shell
+	// hook, webhook) while a long-running unattended session is known to
+	// be noisy; the event still lands in the ring, log file, and any Sink
+	// so nothing is lost, just not actively pushed.
+	if !muted {
+		if e.cfg.Notify {
+			notify(rule.Name, msg)
+		}
+		if rule.Hook != "" {
+			runHook(rule.Hook, event)
+		}
+		if e.cfg.Webhook != "" {
+			postWebhook(e.cfg.Webhook, event)
+		}
+	}
+	for _, s := range sinks {
+		s.LogAlert(event)
+	}
+}
+
+// ToggleMute flips whether fired alerts push desktop notifications, shell
+// hooks, and webhooks; bound to a key in the Alerts pane.
+func (e *Engine) ToggleMute() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.muted = !e.muted
+}
+
+// Muted reports whether notifiers are currently silenced.
+func (e *Engine) Muted() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.muted
+}
+
+// ActiveCount returns how many rule/entity keys are currently armed, i.e.
+// have fired and not yet cleared. Intended for a compact always-visible
+// indicator (e.g. the footer banner) alongside the scrollable Events() log.
+func (e *Engine) ActiveCount() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	n := 0
+	for _, st := range e.states {
+		if st.armed || st.highestLevel != 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// AddSink registers s to receive every future fired Event.
+func (e *Engine) AddSink(s Sink) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.sinks = append(e.sinks, s)
+}
+
+// runHook executes rule.Hook through the shell with the event passed as
+// ALERT_* environment variables. It's fire-and-forget: a failing or slow
+// hook never blocks alert evaluation.
+func runHook(hook string, event Event) {
+	cmd := exec.Command("sh", "-c", hook)
+	cmd.Env = append(os.Environ(),
+		"ALERT_RULE="+event.Rule,
+		"ALERT_MESSAGE="+event.Message,
+		"ALERT_SEVERITY="+event.Severity,
+		"ALERT_TIME="+event.Time.Format(time.RFC3339),
+	)
+	go func() {
+		_ = cmd.Run()
+	}()
+}
+
+// Events returns a snapshot of the fired-alert ring buffer, oldest first.
+func (e *Engine) Events() []Event {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	out := make([]Event, len(e.ring))
+	copy(out, e.ring)
+	return out
+}
+
+// snapshot extracts the metrics a Rule can reference by name. Callers must
+// hold state.Mu for reading.
+func snapshot(state *models.MetricsState) (numeric map[string]float64, text map[string]string, temps map[string]float64) {
+	memPercent := 0.0
+	if total := state.MemoryUsed + state.MemoryAvailable; total > 0 {
+		memPercent = state.MemoryUsed / total * 100
+	}
+
+	numeric = map[string]float64{
+		"CPUPower":          state.CPUPower,
+		"GPUPower":          state.GPUPower,
+		"ANEPower":          state.ANEPower,
+		"DRAMPower":         state.DRAMPower,
+		"SystemPower":       state.SystemPower,
+		"BatteryCharge":     state.BatteryCharge,
+		"NetworkIn":         state.NetworkIn,
+		"NetworkOut":        state.NetworkOut,
+		"DiskRead":          state.DiskRead,
+		"DiskWrite":         state.DiskWrite,
+		"MemoryUsed":        state.MemoryUsed,
+		"MemoryAvailable":   state.MemoryAvailable,
+		"MemoryUsedPercent": memPercent,
+		"SwapUsed":          state.SwapUsed,
+
+		// EWMA-smoothed counterparts, for rules with UseSmoothed set so a
+		// single noisy sample doesn't trip an otherwise-legitimate threshold.
+		"CPUPowerSmoothed":    state.CPUPowerSmoothed.Value,
+		"GPUPowerSmoothed":    state.GPUPowerSmoothed.Value,
+		"ANEPowerSmoothed":    state.ANEPowerSmoothed.Value,
+		"DRAMPowerSmoothed":   state.DRAMPowerSmoothed.Value,
+		"SystemPowerSmoothed": state.SystemPowerSmoothed.Value,
+		"IPIRateSmoothed":     state.IPIRateSmoothed.Value,
+	}
+	text = map[string]string{
+		"Thermal":         state.ThermalPressure,
+		"ThermalPressure": state.ThermalPressure,
+		"BatteryState":    state.BatteryState,
+	}
+
+	temps = make(map[string]float64, len(state.Temperature))
+	for k, v := range state.Temperature {
+		temps[k] = v
+	}
+
+	return numeric, text, temps
+}
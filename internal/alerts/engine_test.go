@@ -0,0 +1,238 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"powermetrics-tui/internal/models"
+)
+
+func TestEvaluateTemperatureSensor(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "gpu-hot", Metric: "Temperature.GPU", Comparison: GreaterThan, Threshold: 90},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.Temperature["GPU"] = 95
+
+	engine.Evaluate(state)
+
+	events := engine.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 fired event, got %d", len(events))
+	}
+	if events[0].Rule != "gpu-hot" {
+		t.Errorf("expected gpu-hot to fire, got %+v", events[0])
+	}
+}
+
+func TestEvaluateMemoryUsedPercent(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "mem-full", Metric: "MemoryUsedPercent", Comparison: GreaterThan, Threshold: 90},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.MemoryUsed = 95
+	state.MemoryAvailable = 5
+
+	engine.Evaluate(state)
+
+	if len(engine.Events()) != 1 {
+		t.Fatalf("expected mem-full to fire at 95%% used")
+	}
+}
+
+func TestEvaluateCoalitionScopeFiresPerEntity(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "coalition-hot", Metric: "CPUPercent", Comparison: GreaterThan, Threshold: 50, Scope: ScopeCoalition},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.Coalitions = []models.ProcessCoalition{
+		{CoalitionID: 1, Name: "busy", CPUPercent: 90},
+		{CoalitionID: 2, Name: "idle", CPUPercent: 5},
+	}
+
+	engine.Evaluate(state)
+
+	events := engine.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 fired event, got %d: %+v", len(events), events)
+	}
+	if events[0].Entity != "busy" {
+		t.Errorf("expected the busy coalition to fire, got %+v", events[0])
+	}
+}
+
+func TestEvaluateLeveledThresholdsFireOncePerBand(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "mem-bands", Metric: "MemoryUsedPercent", Comparison: GreaterThan, Levels: []float64{50, 80}},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.MemoryUsed = 60
+	state.MemoryAvailable = 40
+
+	engine.Evaluate(state)
+	if len(engine.Events()) != 1 {
+		t.Fatalf("expected 1 event crossing the 50%% band, got %d", len(engine.Events()))
+	}
+
+	engine.Evaluate(state)
+	if len(engine.Events()) != 1 {
+		t.Fatalf("expected no repeat event while still in the same band, got %d", len(engine.Events()))
+	}
+
+	state.MemoryUsed = 85
+	state.MemoryAvailable = 15
+	engine.Evaluate(state)
+	if len(engine.Events()) != 2 {
+		t.Fatalf("expected a second event crossing the 80%% band, got %d", len(engine.Events()))
+	}
+}
+
+func TestEvaluateSustainedForDuration(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "sustained", Metric: "SwapUsed", Comparison: GreaterThan, Threshold: 100, For: time.Hour},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.SwapUsed = 500
+
+	engine.Evaluate(state)
+	if len(engine.Events()) != 0 {
+		t.Fatalf("expected no event before the For duration elapses, got %d", len(engine.Events()))
+	}
+}
+
+func TestEvaluateSustainedSamplesRequiresConsecutiveHistory(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "cpu-hog", Metric: "CPUPercent", Comparison: GreaterThan, Threshold: 80, Scope: ScopeProcess, SustainedSamples: 3},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.Processes = []models.ProcessInfo{
+		{PID: 1, Name: "worker", CPUPercent: 90, CPUHistory: []float64{10, 90}},
+	}
+	engine.Evaluate(state)
+	if len(engine.Events()) != 0 {
+		t.Fatalf("expected no event: history only has 2 samples above threshold, need 3, got %d", len(engine.Events()))
+	}
+
+	state.Processes[0].CPUHistory = []float64{10, 90, 95, 92}
+	engine.Evaluate(state)
+	if len(engine.Events()) != 1 {
+		t.Fatalf("expected 1 event once the last 3 samples are all above threshold, got %d", len(engine.Events()))
+	}
+}
+
+func TestEvaluateWhereFiltersByName(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "chrome-hot", Metric: "CPUPercent", Comparison: GreaterThan, Threshold: 50, Scope: ScopeProcess, Where: "name=~^chrome"},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.Processes = []models.ProcessInfo{
+		{PID: 1, Name: "chrome", CPUPercent: 90},
+		{PID: 2, Name: "firefox", CPUPercent: 90},
+	}
+
+	engine.Evaluate(state)
+
+	events := engine.Events()
+	if len(events) != 1 {
+		t.Fatalf("expected only the matching process to fire, got %d: %+v", len(events), events)
+	}
+	if events[0].Entity != "chrome" {
+		t.Errorf("expected chrome to fire, got %+v", events[0])
+	}
+}
+
+func TestEvaluatePrunesStateForExitedPID(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "proc-hot", Metric: "CPUPercent", Comparison: GreaterThan, Threshold: 50, Scope: ScopeProcess},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.Processes = []models.ProcessInfo{{PID: 42, Name: "worker", CPUPercent: 90}}
+	state.LastSeenPIDs[42] = time.Now()
+	engine.Evaluate(state)
+
+	key := "proc-hot/process/42"
+	if _, ok := engine.states[key]; !ok {
+		t.Fatalf("expected state for pid 42 to exist after it fires")
+	}
+
+	delete(state.LastSeenPIDs, 42)
+	state.Processes = nil
+	engine.Evaluate(state)
+
+	if _, ok := engine.states[key]; ok {
+		t.Errorf("expected state for pid 42 to be pruned once it left LastSeenPIDs")
+	}
+}
+
+func TestEvaluateUseSmoothedIgnoresRawSpike(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "power-high", Metric: "SystemPower", Comparison: GreaterThan, Threshold: 100, UseSmoothed: true},
+	}}
+	engine := NewEngine(cfg)
+
+	state := models.NewMetricsState()
+	state.SystemPower = 5000 // raw spike, far above threshold
+	state.SystemPowerSmoothed.Update(50, models.DefaultSmoothingAlpha, models.DefaultSmoothingWindow)
+
+	engine.Evaluate(state)
+	if len(engine.Events()) != 0 {
+		t.Fatalf("expected no event: smoothed value is below threshold even though the raw sample spiked, got %d", len(engine.Events()))
+	}
+
+	state.SystemPowerSmoothed.Update(5000, models.DefaultSmoothingAlpha, models.DefaultSmoothingWindow)
+	state.SystemPowerSmoothed.Update(5000, models.DefaultSmoothingAlpha, models.DefaultSmoothingWindow)
+	state.SystemPowerSmoothed.Update(5000, models.DefaultSmoothingAlpha, models.DefaultSmoothingWindow)
+	engine.Evaluate(state)
+	if len(engine.Events()) != 1 {
+		t.Fatalf("expected 1 event once the smoothed value itself crosses the threshold, got %d", len(engine.Events()))
+	}
+}
+
+func TestToggleMute(t *testing.T) {
+	engine := NewEngine(&Config{})
+	if engine.Muted() {
+		t.Fatalf("expected a fresh engine to start unmuted")
+	}
+
+	engine.ToggleMute()
+	if !engine.Muted() {
+		t.Fatalf("expected ToggleMute to mute an unmuted engine")
+	}
+
+	engine.ToggleMute()
+	if engine.Muted() {
+		t.Fatalf("expected ToggleMute to unmute a muted engine")
+	}
+}
+
+func TestEvaluateMutedStillRecordsEvents(t *testing.T) {
+	cfg := &Config{Rules: []Rule{
+		{Name: "gpu-hot", Metric: "Temperature.GPU", Comparison: GreaterThan, Threshold: 90},
+	}}
+	engine := NewEngine(cfg)
+	engine.ToggleMute()
+
+	state := models.NewMetricsState()
+	state.Temperature["GPU"] = 95
+	engine.Evaluate(state)
+
+	if len(engine.Events()) != 1 {
+		t.Fatalf("expected muting to still record the event in the ring, got %d", len(engine.Events()))
+	}
+}
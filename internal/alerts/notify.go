@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// notify fires a best-effort desktop notification for a triggered rule.
+// Failures are silently ignored; alerts remain visible in the in-TUI pane
+// and log file regardless.
+func notify(title, message string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := `display notification "` + escapeAppleScriptString(message) + `" with title "` + escapeAppleScriptString(title) + `"`
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		cmd = exec.Command("notify-send", title, message)
+	default:
+		return
+	}
+
+	_ = cmd.Run()
+}
+
+// escapeAppleScriptString escapes backslashes and double quotes so s can be
+// safely embedded inside an AppleScript string literal. title/message in
+// notify come from rule names and live process/coalition names, neither of
+// which this tool controls, so without this a name containing a `"` could
+// break out of the literal and run arbitrary AppleScript (e.g. via
+// `do shell script`).
+func escapeAppleScriptString(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return s
+}
+
+// webhookClient is shared across calls rather than using http.DefaultClient
+// directly, so a hung endpoint can't pile up goroutines indefinitely.
+var webhookClient = &http.Client{Timeout: 5 * time.Second}
+
+// postWebhook POSTs event as a JSON body to url. Best-effort and
+// fire-and-forget like runHook: a failing or slow endpoint never
+// blocks alert evaluation or the sampling goroutine it runs on.
+func postWebhook(url string, event Event) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	go func() {
+		resp, err := webhookClient.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return
+		}
+		resp.Body.Close()
+	}()
+}
@@ -0,0 +1,200 @@
+package alerts
+
+import (
+	"strings"
+	"time"
+)
+
+// Comparison is the operator a Rule uses to test a metric value.
+type Comparison string
+
+const (
+	GreaterThan Comparison = ">"
+	LessThan    Comparison = "<"
+	Equals      Comparison = "=="
+)
+
+// Scope selects what a Rule's Metric is evaluated against. The zero value,
+// ScopeGlobal, reads a single MetricsState field or Temperature entry, as
+// rules always have. ScopeCoalition and ScopeProcess instead evaluate the
+// rule against every tracked coalition/process, keyed by coalition ID or
+// PID, so "any coalition's CPU% > 50" fires independently per offender
+// instead of needing one rule per name.
+type Scope string
+
+const (
+	ScopeGlobal    Scope = ""
+	ScopeCoalition Scope = "coalition"
+	ScopeProcess   Scope = "process"
+)
+
+// Rule describes a single threshold to watch, e.g. "CPUPower > 15000 for
+// 10s". Metric names match models.MetricsState field names (CPUPower,
+// GPUPower, ANEPower, SystemPower, MemoryUsed, MemoryUsedPercent, SwapUsed,
+// ThermalPressure, BatteryCharge, NetworkIn, NetworkOut, DiskRead,
+// DiskWrite). A metric of the form "Temperature.GPU" indexes into
+// MetricsState.Temperature["GPU"] instead of a flat field. When Scope is
+// ScopeCoalition or ScopeProcess, Metric instead names a ProcessCoalition/
+// ProcessInfo field ("CPUPercent" or "MemoryMB").
+type Rule struct {
+	Name string `yaml:"name"`
+	Metric string `yaml:"metric"`
+	Comparison Comparison `yaml:"comparison"`
+	Scope Scope `yaml:"scope"`
+
+	// Hook, if set, is run via "sh -c" whenever this rule fires, with the
+	// rule name, message, severity, and fire time passed as ALERT_* env vars.
+	// Failures are logged nowhere in particular; the in-TUI/log-file event
+	// still records the firing regardless.
+	Hook string `yaml:"hook"`
+
+	// Threshold is compared against numeric metrics.
+	Threshold float64 `yaml:"threshold"`
+	// Equals is compared against string metrics (e.g. ThermalPressure == "Heavy").
+	Equals string `yaml:"equals"`
+
+	// DisarmThreshold provides hysteresis: once armed, the rule only
+	// disarms when the metric crosses back past this value. Defaults to
+	// Threshold (no hysteresis) when zero. Ignored when Levels is set.
+	DisarmThreshold float64 `yaml:"disarm_threshold"`
+
+	// Levels, modeled on crunchstat's MemThresholds, is an optional sorted
+	// ascending list of thresholds sharing one Metric/Comparison/Scope,
+	// e.g. "CPU% > [50, 80, 95]" for warning/critical/emergency bands. The
+	// engine fires only when the value crosses into a higher band than the
+	// highest one already fired for that key, so a metric oscillating
+	// inside one band doesn't spam the log; it re-arms once the value
+	// drops back below the lowest level. When set, Threshold/
+	// DisarmThreshold/For are ignored.
+	Levels []float64 `yaml:"levels"`
+
+	// For requires the condition to hold continuously for this long before
+	// the rule fires, so brief spikes don't spam the log.
+	For time.Duration `yaml:"for"`
+
+	// SustainedSamples, an alternative to For for ScopeProcess/ScopeCoalition
+	// rules, requires the last N entries of the entity's CPUHistory/
+	// MemoryHistory to all satisfy the threshold, rather than requiring the
+	// condition to hold for a wall-clock duration. This catches a process
+	// whose CPU%/memory only updates once per (possibly irregular) sample,
+	// where "for 10s" doesn't map cleanly onto "N consecutive samples".
+	// Ignored for ScopeGlobal rules and for Levels/Equals rules.
+	SustainedSamples int `yaml:"sustained_samples"`
+
+	// UseSmoothed evaluates this rule against the EWMA-smoothed value of
+	// Metric (see models.EWMAState) instead of the raw per-sample value, so
+	// a single noisy spike at a 1-2s sample interval doesn't false-positive
+	// a global (non-scoped) rule. Ignored for ScopeCoalition/ScopeProcess
+	// and for Equals rules, which have no smoothed counterpart.
+	UseSmoothed bool `yaml:"use_smoothed"`
+
+	// Where restricts a ScopeCoalition/ScopeProcess rule to entities whose
+	// name matches, in the form "name=~<regex>" (e.g. "name=~^chrome").
+	// Ignored for ScopeGlobal rules. A malformed or absent Where matches
+	// everything.
+	Where string `yaml:"where"`
+
+	Severity string `yaml:"severity"` // "info", "warning", "critical"
+}
+
+// whereNamePattern returns the regex named by Where's "name=~" clause, and
+// false if Where is empty or not in that form.
+func (r Rule) whereNamePattern() (string, bool) {
+	const prefix = "name=~"
+	if !strings.HasPrefix(r.Where, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(r.Where, prefix), true
+}
+
+// temperatureSensor returns the sensor name and true if Metric indexes into
+// the Temperature map (e.g. "Temperature.GPU" -> "GPU", true).
+func (r Rule) temperatureSensor() (string, bool) {
+	const prefix = "Temperature."
+	if !strings.HasPrefix(r.Metric, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(r.Metric, prefix), true
+}
+
+func (r Rule) disarmThreshold() float64 {
+	if r.DisarmThreshold != 0 {
+		return r.DisarmThreshold
+	}
+	return r.Threshold
+}
+
+// matches reports whether the rule's numeric condition holds for value.
+func (r Rule) matches(value float64) bool {
+	switch r.Comparison {
+	case LessThan:
+		return value < r.Threshold
+	default:
+		return value > r.Threshold
+	}
+}
+
+// clears reports whether value has crossed back past the disarm threshold.
+func (r Rule) clears(value float64) bool {
+	switch r.Comparison {
+	case LessThan:
+		return value >= r.disarmThreshold()
+	default:
+		return value <= r.disarmThreshold()
+	}
+}
+
+// highestLevelCrossed returns the highest entry in r.Levels that value has
+// crossed, and true if any level was crossed at all. Levels must be sorted
+// ascending; for GreaterThan, crossing means value exceeds the level, for
+// LessThan it means value is below it.
+func (r Rule) highestLevelCrossed(value float64) (float64, bool) {
+	crossed, ok := 0.0, false
+	for _, level := range r.Levels {
+		switch r.Comparison {
+		case LessThan:
+			if value < level {
+				crossed, ok = level, true
+			}
+		default:
+			if value > level {
+				crossed, ok = level, true
+			}
+		}
+	}
+	return crossed, ok
+}
+
+// sustainedRun reports whether the last r.SustainedSamples entries of
+// history all satisfy the rule's comparison, so a rule with
+// SustainedSamples set only fires once the entity has stayed past the
+// threshold for that many consecutive samples rather than just the latest
+// one. Returns false (not yet sustained) if history is shorter than
+// SustainedSamples.
+func (r Rule) sustainedRun(history []float64) bool {
+	n := r.SustainedSamples
+	if n <= 0 || len(history) < n {
+		return false
+	}
+	for _, v := range history[len(history)-n:] {
+		if !r.matches(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// belowLowestLevel reports whether value has dropped back beneath every
+// level, so a leveled rule re-arms and can fire again on the next climb.
+func (r Rule) belowLowestLevel(value float64) bool {
+	if len(r.Levels) == 0 {
+		return true
+	}
+	lowest := r.Levels[0]
+	switch r.Comparison {
+	case LessThan:
+		return value >= lowest
+	default:
+		return value <= lowest
+	}
+}
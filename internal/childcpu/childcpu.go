@@ -0,0 +1,23 @@
+// Package childcpu reads a process' cumulative reaped-child CPU time, the
+// macOS equivalent of wait4's RUSAGE_CHILDREN: unlike scraping `ps` for
+// still-alive descendants, this keeps accumulating a coalition leader's
+// cutime/cstime-style totals even after the children that earned them have
+// already exited and been reaped, so a burst of short-lived forks between
+// two powermetrics samples isn't simply invisible.
+package childcpu
+
+// ChildCPU is a PID's cumulative descendant CPU time, in milliseconds, as
+// of the last Reader.Sample call - including children that have already
+// exited and been reaped.
+type ChildCPU struct {
+	UserMs, SystemMs float64
+}
+
+// Total is UserMs+SystemMs, the figure callers diff sample-to-sample to get
+// a delta.
+func (c ChildCPU) Total() float64 { return c.UserMs + c.SystemMs }
+
+// Reader samples a PID's cumulative reaped-child CPU time.
+type Reader interface {
+	Sample(pid int) (ChildCPU, error)
+}
@@ -0,0 +1,42 @@
+//go:build darwin && cgo
+
+package childcpu
+
+/*
+#include <sys/resource.h>
+#include <libproc.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrUnsupported is never returned on darwin+cgo; it exists so callers can
+// compare against the same sentinel on every build.
+var ErrUnsupported = errors.New("childcpu: reaped-child CPU accounting requires darwin with cgo enabled")
+
+// machReader is backed by proc_pid_rusage(RUSAGE_INFO_V2), whose
+// ri_child_user_time/ri_child_system_time fields are the kernel's running
+// total of CPU time spent by pid's descendants, reaped or not.
+type machReader struct{}
+
+// NewReader returns a Reader backed by proc_pid_rusage.
+func NewReader() Reader {
+	return &machReader{}
+}
+
+func (r *machReader) Sample(pid int) (ChildCPU, error) {
+	var info C.struct_rusage_info_v2
+	ret := C.proc_pid_rusage(C.int(pid), C.RUSAGE_INFO_V2, (*C.rusage_info_t)(unsafe.Pointer(&info)))
+	if ret != 0 {
+		return ChildCPU{}, errors.New("childcpu: proc_pid_rusage failed")
+	}
+
+	const nsPerMs = 1e6
+	return ChildCPU{
+		UserMs:   float64(info.ri_child_user_time) / nsPerMs,
+		SystemMs: float64(info.ri_child_system_time) / nsPerMs,
+	}, nil
+}
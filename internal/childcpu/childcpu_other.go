@@ -0,0 +1,23 @@
+//go:build !(darwin && cgo)
+
+package childcpu
+
+import "errors"
+
+// ErrUnsupported is returned by stubReader.Sample on any build without
+// proc_pid_rusage support (anything but darwin with cgo enabled).
+var ErrUnsupported = errors.New("childcpu: reaped-child CPU accounting requires darwin with cgo enabled")
+
+// stubReader is a no-op stand-in for the darwin+cgo Reader, so callers
+// don't need their own build-tag switch.
+type stubReader struct{}
+
+// NewReader returns a Reader whose Sample always fails with ErrUnsupported
+// on this build.
+func NewReader() Reader {
+	return &stubReader{}
+}
+
+func (r *stubReader) Sample(pid int) (ChildCPU, error) {
+	return ChildCPU{}, ErrUnsupported
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package collector
+
+import (
+	"bufio"
+	"os"
+	"strings"
+)
+
+// cgroupPath returns a pid's unified (cgroup v2) cgroup path, e.g.
+// "/user.slice/user-1000.slice/session-3.scope", read from the "0::" line
+// of /proc/[pid]/cgroup. It returns "" if the pid is gone, unreadable, or
+// the host isn't on a pure cgroup v2 hierarchy (cgroup v1/hybrid hosts have
+// no single "0::" line); callers fall back to flat grouping in that case.
+func cgroupPath(pid int) string {
+	f, err := os.Open(procPath(pid, "cgroup"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "0::") {
+			return strings.TrimPrefix(line, "0::")
+		}
+	}
+	return ""
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package collector
+
+// cgroupPath is only meaningful on Linux; elsewhere buildCoalitions falls
+// back to flat, single-level grouping by process name.
+func cgroupPath(pid int) string {
+	return ""
+}
@@ -0,0 +1,45 @@
+// Package collector abstracts the source of system metrics so the UI layer
+// depends on a single interface instead of the macOS-only powermetrics
+// parser. Two implementations are provided: PowermetricsCollector, which
+// wraps the existing parser.StateMachine pipeline, and GopsutilCollector,
+// which uses github.com/shirou/gopsutil/v4 to run on Linux and Windows.
+package collector
+
+import (
+	"runtime"
+
+	"powermetrics-tui/internal/models"
+)
+
+// MetricsCollector is implemented by anything that can keep a
+// models.MetricsState up to date on a timer. The UI only ever talks to this
+// interface, never to the parser or gopsutil packages directly.
+type MetricsCollector interface {
+	// Start begins sampling into state every intervalMs milliseconds. It
+	// returns without waiting for a sample; all samples, including the
+	// first, are collected on a background goroutine.
+	Start(state *models.MetricsState, intervalMs int) error
+
+	// Stop halts sampling and releases any subprocess/handles.
+	Stop()
+
+	// Name identifies the active backend for display/debugging, e.g.
+	// "powermetrics" or "gopsutil".
+	Name() string
+}
+
+// New returns the MetricsCollector to use for the current run. backend may
+// be "powermetrics", "gopsutil", or "" to auto-detect from runtime.GOOS.
+func New(backend string) MetricsCollector {
+	switch backend {
+	case "powermetrics":
+		return NewPowermetricsCollector()
+	case "gopsutil":
+		return NewGopsutilCollector()
+	}
+
+	if runtime.GOOS == "darwin" {
+		return NewPowermetricsCollector()
+	}
+	return NewGopsutilCollector()
+}
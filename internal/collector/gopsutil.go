@@ -0,0 +1,290 @@
+package collector
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/load"
+	"github.com/shirou/gopsutil/v4/mem"
+	"github.com/shirou/gopsutil/v4/net"
+	"github.com/shirou/gopsutil/v4/process"
+
+	"powermetrics-tui/internal/models"
+)
+
+// GopsutilCollector samples CPU, memory, disk, network, load, and process
+// metrics via gopsutil. It runs on Linux and Windows where powermetrics
+// doesn't exist. Fields that only powermetrics can populate (ANE power,
+// P/E-core residency, thermal pressure) are left at their zero value and
+// render as "n/a" in the UI.
+type GopsutilCollector struct {
+	stopCh chan struct{}
+
+	lastNet        net.IOCountersStat
+	lastDisk       disk.IOCountersStat
+	lastInterrupts map[string]float64
+	lastTime       time.Time
+
+	// lastCoalitions is the previous sample's coalitions by ID, consulted
+	// by buildCoalitions to fold exited children into their parent.
+	lastCoalitions map[int]models.ProcessCoalition
+}
+
+// NewGopsutilCollector creates a GopsutilCollector ready to Start.
+func NewGopsutilCollector() *GopsutilCollector {
+	return &GopsutilCollector{
+		stopCh: make(chan struct{}),
+	}
+}
+
+func (c *GopsutilCollector) Name() string {
+	return "gopsutil"
+}
+
+func (c *GopsutilCollector) Start(state *models.MetricsState, intervalMs int) error {
+	go c.run(state, intervalMs)
+	return nil
+}
+
+func (c *GopsutilCollector) Stop() {
+	close(c.stopCh)
+}
+
+func (c *GopsutilCollector) run(state *models.MetricsState, intervalMs int) {
+	interval := time.Duration(intervalMs) * time.Millisecond
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.sample(state)
+		}
+	}
+}
+
+func (c *GopsutilCollector) sample(state *models.MetricsState) {
+	now := time.Now()
+
+	vm, err := mem.VirtualMemory()
+	if err != nil {
+		state.Mu.Lock()
+		state.UpdateErrors++
+		state.Mu.Unlock()
+		return
+	}
+	swap, _ := mem.SwapMemory()
+
+	loadAvg, _ := load.Avg()
+	_ = loadAvg // exposed via Processes/system view today only as CPU%, kept for future use
+
+	netStats, _ := net.IOCounters(false)
+	diskStats, _ := disk.IOCounters()
+	interrupts, _ := readPerCPUInterrupts()
+	cpuPcts, _ := cpu.Percent(0, true)
+
+	var totalDisk disk.IOCountersStat
+	for _, d := range diskStats {
+		totalDisk.ReadBytes += d.ReadBytes
+		totalDisk.WriteBytes += d.WriteBytes
+	}
+
+	procs, _ := process.Processes()
+
+	state.Mu.Lock()
+	defer state.Mu.Unlock()
+
+	state.MemoryUsed = float64(vm.Used) / 1024 / 1024
+	state.MemoryAvailable = float64(vm.Available) / 1024 / 1024
+	state.SwapUsed = float64(swap.Used) / 1024 / 1024
+
+	if len(netStats) > 0 && !c.lastTime.IsZero() {
+		elapsed := now.Sub(c.lastTime).Seconds()
+		if elapsed > 0 {
+			state.NetworkIn = float64(netStats[0].BytesRecv-c.lastNet.BytesRecv) / elapsed / 1024 / 1024
+			state.NetworkOut = float64(netStats[0].BytesSent-c.lastNet.BytesSent) / elapsed / 1024 / 1024
+			state.DiskRead = float64(totalDisk.ReadBytes-c.lastDisk.ReadBytes) / elapsed / 1024 / 1024
+			state.DiskWrite = float64(totalDisk.WriteBytes-c.lastDisk.WriteBytes) / elapsed / 1024 / 1024
+		}
+	}
+	if len(netStats) > 0 {
+		c.lastNet = netStats[0]
+	}
+	c.lastDisk = totalDisk
+
+	if interrupts != nil && !c.lastTime.IsZero() {
+		elapsed := now.Sub(c.lastTime).Seconds()
+		if elapsed > 0 {
+			if state.AllSeenCPUs == nil {
+				state.AllSeenCPUs = make(map[string]bool)
+			}
+			for cpuID, total := range interrupts {
+				rate := (total - c.lastInterrupts[cpuID]) / elapsed
+				state.PerCPUInterrupts[cpuID] = rate
+				state.AllSeenCPUs[cpuID] = true
+			}
+		}
+	}
+	c.lastInterrupts = interrupts
+	c.lastTime = now
+
+	// cpu.Percent(0, true) returns each core's busy% since the previous
+	// call using gopsutil's own internal tick-delta tracking (interval 0
+	// means "since last call" rather than blocking to sample twice), so
+	// there's no need to keep our own previous-ticks state here the way
+	// lastInterrupts/lastTime do for interrupt rates.
+	for i, pct := range cpuPcts {
+		state.PerCPUUtilization[fmt.Sprintf("CPU%d", i)] = pct
+	}
+
+	// Powermetrics-only concepts: no ANE, no P/E-core split, no thermal
+	// pressure reading available through gopsutil. Leave them zeroed so the
+	// UI's existing "n/a" fallbacks kick in.
+	state.ANEPower = 0
+	state.ECoreFreq = nil
+	state.PCoreFreq = nil
+	if state.ThermalPressure == "" {
+		state.ThermalPressure = "n/a"
+	}
+
+	state.Processes = state.Processes[:0]
+	for _, p := range procs {
+		name, _ := p.Name()
+		cpuPct, _ := p.CPUPercent()
+		memInfo, _ := p.MemoryInfo()
+		memMB := 0.0
+		if memInfo != nil {
+			memMB = float64(memInfo.RSS) / 1024 / 1024
+		}
+		state.Processes = append(state.Processes, models.ProcessInfo{
+			PID:           int(p.Pid),
+			Name:          name,
+			CoalitionName: name,
+			CPUPercent:    cpuPct,
+			MemoryMB:      memMB,
+			MemoryInfoEx:  readProcessMemoryEx(int(p.Pid)),
+		})
+	}
+
+	c.buildCoalitions(state)
+
+	state.LastUpdate = now
+}
+
+// buildCoalitions groups state.Processes into a ProcessCoalition tree. On
+// Linux, where /proc/[pid]/cgroup exposes the cgroup v2 path, a coalition is
+// created for every path segment and linked to its parent via ParentID,
+// mirroring the cgroup hierarchy (a container's cgroup shows up as a child
+// of its slice, which is a child of the root). Elsewhere - or for any pid
+// whose cgroup can't be read - a process falls back to the old flat,
+// single-level grouping by name. Coalition IDs are a stable hash of the
+// grouping key rather than a kernel-assigned ID, so history maps and the
+// Top pane have something consistent to key on across samples.
+func (c *GopsutilCollector) buildCoalitions(state *models.MetricsState) {
+	byID := make(map[int]*models.ProcessCoalition)
+	order := make([]int, 0)
+
+	ensureNode := func(id int, name string) *models.ProcessCoalition {
+		if n, ok := byID[id]; ok {
+			return n
+		}
+		n := &models.ProcessCoalition{CoalitionID: id, Name: name}
+		byID[id] = n
+		order = append(order, id)
+		return n
+	}
+
+	ensureCgroupChain := func(path string) *models.ProcessCoalition {
+		parentID := models.RootCoalitionID
+		var leaf *models.ProcessCoalition
+		cur := ""
+		for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+			if seg == "" {
+				continue
+			}
+			cur += "/" + seg
+			node := ensureNode(coalitionID(cur), seg)
+			node.ParentID = parentID
+			parentID = node.CoalitionID
+			leaf = node
+		}
+		if leaf == nil {
+			leaf = ensureNode(coalitionID("/"), "/")
+			leaf.ParentID = models.RootCoalitionID
+		}
+		return leaf
+	}
+
+	for _, p := range state.Processes {
+		var co *models.ProcessCoalition
+		if path := cgroupPath(p.PID); path != "" {
+			co = ensureCgroupChain(path)
+		} else {
+			co = ensureNode(coalitionID(p.Name), p.Name)
+			co.ParentID = models.RootCoalitionID
+		}
+		co.CPUPercent += p.CPUPercent
+		co.MemoryMB += p.MemoryMB
+		co.Subprocesses = append(co.Subprocesses, p)
+	}
+
+	// Fold any coalition that vanished since last sample into its parent's
+	// ExitedChildren counters, the same way crunchstat accounts for reaped
+	// cgroup children, so a coalition spawning many short-lived
+	// subprocesses shows accurate cumulative use instead of flickering to
+	// zero between samples.
+	for id, node := range byID {
+		if prev, ok := c.lastCoalitions[id]; ok {
+			node.ExitedChildrenCPU = prev.ExitedChildrenCPU
+			node.ExitedChildrenMemoryMB = prev.ExitedChildrenMemoryMB
+		}
+	}
+	for id, prev := range c.lastCoalitions {
+		if _, stillPresent := byID[id]; stillPresent {
+			continue
+		}
+		if parent, ok := byID[prev.ParentID]; ok {
+			parent.ExitedChildrenCPU += prev.CPUPercent + prev.ExitedChildrenCPU
+			parent.ExitedChildrenMemoryMB += prev.MemoryMB + prev.ExitedChildrenMemoryMB
+		}
+	}
+
+	coalitions := make([]models.ProcessCoalition, 0, len(order))
+	nextLast := make(map[int]models.ProcessCoalition, len(order))
+	for _, id := range order {
+		co := byID[id]
+
+		if state.CoalitionCPUHistory[co.CoalitionID] == nil {
+			state.CoalitionCPUHistory[co.CoalitionID] = make([]float64, 0, 10)
+		}
+		state.CoalitionCPUHistory[co.CoalitionID] = models.AddToHistory(state.CoalitionCPUHistory[co.CoalitionID], co.CPUPercent, 10)
+
+		if state.CoalitionMemHistory[co.CoalitionID] == nil {
+			state.CoalitionMemHistory[co.CoalitionID] = make([]float64, 0, 10)
+		}
+		state.CoalitionMemHistory[co.CoalitionID] = models.AddToHistory(state.CoalitionMemHistory[co.CoalitionID], co.MemoryMB, 10)
+
+		co.CPUHistory = state.CoalitionCPUHistory[co.CoalitionID]
+		co.MemoryHistory = state.CoalitionMemHistory[co.CoalitionID]
+		coalitions = append(coalitions, *co)
+		nextLast[id] = *co
+	}
+
+	state.Coalitions = coalitions
+	c.lastCoalitions = nextLast
+}
+
+// coalitionID derives a stable positive int key from a grouping key (a
+// process name or a cgroup path) so it
+// can live in the same int-keyed history maps powermetrics coalitions use.
+func coalitionID(name string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return int(h.Sum32() & 0x7fffffff)
+}
@@ -0,0 +1,56 @@
+//go:build linux
+
+package collector
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readPerCPUInterrupts sums every interrupt line in /proc/interrupts into a
+// cumulative count per CPU column (e.g. "CPU0" -> total interrupts serviced
+// by that core since boot). The caller is responsible for turning the
+// cumulative counts into a rate by diffing against a previous sample.
+func readPerCPUInterrupts() (map[string]float64, error) {
+	f, err := os.Open("/proc/interrupts")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	if !scanner.Scan() {
+		return nil, scanner.Err()
+	}
+	columns := strings.Fields(scanner.Text())
+
+	totals := make(map[string]float64, len(columns))
+	for _, col := range columns {
+		totals[col] = 0
+	}
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		// Skip the "IRQ:" label; remaining fields line up with columns
+		// until we hit the first non-numeric field (the interrupt
+		// description/source).
+		for i, col := range columns {
+			idx := i + 1
+			if idx >= len(fields) {
+				break
+			}
+			count, err := strconv.ParseFloat(fields[idx], 64)
+			if err != nil {
+				break
+			}
+			totals[col] += count
+		}
+	}
+
+	return totals, scanner.Err()
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package collector
+
+// readPerCPUInterrupts is only meaningful on Linux, which exposes
+// /proc/interrupts; elsewhere PerCPUInterrupts is left empty and the UI's
+// existing zero-value handling applies.
+func readPerCPUInterrupts() (map[string]float64, error) {
+	return nil, nil
+}
@@ -0,0 +1,75 @@
+//go:build linux
+
+package collector
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+
+	"powermetrics-tui/internal/models"
+)
+
+// pageSizeBytes is the Linux page size statm's fields are counted in.
+// os.Getpagesize() would need a syscall per call; it's a build-time
+// constant on every Linux architecture this tool targets.
+const pageSizeBytes = 4096
+
+// readProcessMemoryEx fills in MemoryInfoEx for pid from /proc/[pid]/statm
+// (size, resident, shared, text, lib, data, dt in pages) and, where
+// present, /proc/[pid]/smaps_rollup (for Private_Dirty/Shared_Dirty/Swap, which
+// statm doesn't break out). smaps_rollup requires CONFIG_PROC_PAGE_MONITOR
+// and read permission on the target pid, so its absence is not an error:
+// the statm-derived fields are still returned.
+func readProcessMemoryEx(pid int) models.MemoryInfoEx {
+	var ex models.MemoryInfoEx
+
+	if statm, err := os.ReadFile(procPath(pid, "statm")); err == nil {
+		fields := strings.Fields(string(statm))
+		if len(fields) >= 6 {
+			size, _ := strconv.ParseFloat(fields[0], 64)
+			resident, _ := strconv.ParseFloat(fields[1], 64)
+			shared, _ := strconv.ParseFloat(fields[2], 64)
+			text, _ := strconv.ParseFloat(fields[3], 64)
+			data, _ := strconv.ParseFloat(fields[5], 64)
+
+			ex.VMS = size * pageSizeBytes / 1024 / 1024
+			ex.RSS = resident * pageSizeBytes / 1024 / 1024
+			ex.Shared = shared * pageSizeBytes / 1024 / 1024
+			ex.Text = text * pageSizeBytes / 1024 / 1024
+			ex.Data = data * pageSizeBytes / 1024 / 1024
+		}
+	}
+
+	if f, err := os.Open(procPath(pid, "smaps_rollup")); err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "Private_Dirty:"), strings.HasPrefix(line, "Shared_Dirty:"):
+				ex.Dirty += smapsKB(line) / 1024
+			case strings.HasPrefix(line, "Swap:"):
+				ex.Swap += smapsKB(line) / 1024
+			}
+		}
+	}
+
+	return ex
+}
+
+// smapsKB parses the numeric kB value out of an smaps_rollup line like
+// "Private_Dirty:      1234 kB".
+func smapsKB(line string) float64 {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return 0
+	}
+	kb, _ := strconv.ParseFloat(fields[1], 64)
+	return kb
+}
+
+func procPath(pid int, file string) string {
+	return "/proc/" + strconv.Itoa(pid) + "/" + file
+}
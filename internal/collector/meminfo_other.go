@@ -0,0 +1,12 @@
+//go:build !linux
+
+package collector
+
+import "powermetrics-tui/internal/models"
+
+// readProcessMemoryEx is only meaningful on Linux, which exposes
+// /proc/[pid]/statm and smaps_rollup; elsewhere MemoryInfoEx is left at its
+// zero value and the UI's existing "n/a" fallbacks apply.
+func readProcessMemoryEx(pid int) models.MemoryInfoEx {
+	return models.MemoryInfoEx{}
+}
@@ -0,0 +1,157 @@
+package collector
+
+import (
+	"fmt"
+	"os/exec"
+	"time"
+
+	"powermetrics-tui/internal/mach"
+	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/parser"
+	"powermetrics-tui/internal/record"
+)
+
+// Format selects which output mode PowermetricsCollector asks powermetrics
+// for, and which parser it feeds the result through.
+type Format int
+
+const (
+	// FormatText runs powermetrics with its default human-readable output
+	// and parses it with parser.ParsePowerMetricsOutput. It is Format's
+	// zero value, but NewPowermetricsCollector starts from FormatPlist;
+	// main switches to FormatText on request. Kept working since it's also
+	// what internal/record's recorded samples were captured in.
+	FormatText Format = iota
+	// FormatPlist runs powermetrics with `-f plist -o /dev/stdout` and
+	// parses it with parser.ParsePowerMetricsPlist, trading the text
+	// format's regex churn for powermetrics' own stable field names.
+	FormatPlist
+)
+
+// PowermetricsCollector drives the macOS `powermetrics` binary and feeds its
+// output through either parser.ParsePowerMetricsOutput or
+// parser.ParsePowerMetricsPlist, depending on Format. This is the original
+// collection path, now behind the MetricsCollector interface.
+type PowermetricsCollector struct {
+	Samplers string
+	Debug    bool
+	Format   Format
+
+	// Recorder, if set, tees every raw sample to disk for later replay.
+	Recorder *record.Recorder
+
+	// machSampler fills in state.PerCPUUsage with classical %user/%system/
+	// %idle/%nice figures powermetrics doesn't report itself. It's a no-op
+	// that always errors outside darwin+cgo builds (see internal/mach), so
+	// PerCPUUsage is simply left empty there.
+	machSampler *mach.Sampler
+
+	stopCh chan struct{}
+}
+
+// NewPowermetricsCollector creates a PowermetricsCollector with default
+// samplers and FormatPlist, preferring powermetrics' structured plist output
+// over its regex-fragile text output wherever it's available; callers
+// typically set Samplers/Debug/Format before calling Start.
+func NewPowermetricsCollector() *PowermetricsCollector {
+	return &PowermetricsCollector{
+		Samplers:    "default",
+		Format:      FormatPlist,
+		machSampler: mach.NewSampler(),
+		stopCh:      make(chan struct{}),
+	}
+}
+
+func (c *PowermetricsCollector) Name() string {
+	return "powermetrics"
+}
+
+func (c *PowermetricsCollector) Start(state *models.MetricsState, intervalMs int) error {
+	go c.run(state, intervalMs)
+	return nil
+}
+
+func (c *PowermetricsCollector) Stop() {
+	close(c.stopCh)
+}
+
+func (c *PowermetricsCollector) run(state *models.MetricsState, intervalMs int) {
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		default:
+		}
+
+		args := []string{
+			"powermetrics",
+			"--samplers", c.Samplers,
+			"-i", fmt.Sprintf("%d", intervalMs),
+			"-n", "1",
+		}
+		if c.Format == FormatPlist {
+			args = append(args, "-f", "plist", "-o", "/dev/stdout")
+		}
+
+		cmd := exec.Command("sudo", args...)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			state.Mu.Lock()
+			state.UpdateErrors++
+			state.Mu.Unlock()
+			time.Sleep(time.Duration(intervalMs) * time.Millisecond)
+			continue
+		}
+
+		if c.Recorder != nil {
+			c.Recorder.Write(string(output))
+		}
+
+		if c.Format == FormatPlist {
+			if err := parser.ParsePowerMetricsPlist(output, state); err != nil {
+				state.Mu.Lock()
+				state.UpdateErrors++
+				state.Mu.Unlock()
+			}
+		} else {
+			parser.ParsePowerMetricsOutput(string(output), state)
+			state.Mu.Lock()
+			state.LastUpdate = time.Now()
+			state.Mu.Unlock()
+		}
+
+		c.sampleCPUUsage(state)
+
+		time.Sleep(time.Duration(intervalMs) * time.Millisecond)
+	}
+}
+
+// cpuUsageHistoryCap matches ECoreFreqHistory/PCoreFreqHistory's per-core
+// history length (see parser.organizeCPUFrequencies).
+const cpuUsageHistoryCap = 30
+
+// sampleCPUUsage folds a mach.Sampler reading into state.PerCPUUsage and
+// PerCPUUsageHistory. Errors (including mach.ErrUnsupported on non-darwin or
+// cgo-disabled builds) are silently ignored: this is a supplementary source
+// powermetrics doesn't depend on, not something worth counting against
+// UpdateErrors.
+func (c *PowermetricsCollector) sampleCPUUsage(state *models.MetricsState) {
+	usage, err := c.machSampler.Sample()
+	if err != nil || len(usage) == 0 {
+		return
+	}
+
+	state.Mu.Lock()
+	defer state.Mu.Unlock()
+
+	for cpu, load := range usage {
+		cpuUsage := models.CPUUsage{User: load.User, System: load.System, Idle: load.Idle, Nice: load.Nice}
+		state.PerCPUUsage[cpu] = cpuUsage
+
+		history := append(state.PerCPUUsageHistory[cpu], cpuUsage)
+		if len(history) > cpuUsageHistoryCap {
+			history = history[1:]
+		}
+		state.PerCPUUsageHistory[cpu] = history
+	}
+}
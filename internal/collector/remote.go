@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"crypto/tls"
+
+	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/remote"
+)
+
+// RemoteCollector implements MetricsCollector by dialing a Server started
+// elsewhere with --serve and filling state from the wire instead of
+// running powermetrics locally, so --connect works the same way regardless
+// of which OS (or backend) the server is actually sampling on.
+type RemoteCollector struct {
+	Addr      string
+	AuthToken string
+	TLSConfig *tls.Config
+
+	client *remote.Client
+}
+
+// NewRemoteCollector creates a RemoteCollector that will dial addr once
+// Start is called.
+func NewRemoteCollector(addr, authToken string, tlsConfig *tls.Config) *RemoteCollector {
+	return &RemoteCollector{Addr: addr, AuthToken: authToken, TLSConfig: tlsConfig}
+}
+
+func (c *RemoteCollector) Name() string {
+	return "remote"
+}
+
+// Start begins the connect/stream/reconnect loop. intervalMs is ignored;
+// the server dictates sampling cadence, not the client.
+func (c *RemoteCollector) Start(state *models.MetricsState, intervalMs int) error {
+	c.client = remote.NewClient(c.Addr, c.AuthToken, c.TLSConfig)
+	c.client.Start(state)
+	return nil
+}
+
+func (c *RemoteCollector) Stop() {
+	if c.client != nil {
+		c.client.Stop()
+	}
+}
+
+// Status exposes connection health (latency, dropped frames, reconnect
+// backoff) for the TUI's header indicator.
+func (c *RemoteCollector) Status() remote.Status {
+	if c.client == nil {
+		return remote.Status{}
+	}
+	return c.client.Status()
+}
@@ -0,0 +1,112 @@
+// Package colorschemes holds the named color palettes the TUI can draw
+// with, modeled after gotop's scheme registry: a Colorscheme bundles every
+// color a draw function would otherwise hardcode (bar thresholds, sparkline,
+// header, battery/thermal state colors) so switching schemes at runtime
+// doesn't require touching the views themselves.
+package colorschemes
+
+import "github.com/gdamore/tcell/v2"
+
+// Colorscheme bundles the colors internal/ui's draw functions use instead of
+// referencing tcell colors directly. BarLow/Med/High are the three bands
+// GetColorForValue cycles through as a value crosses its low/high
+// thresholds (e.g. CPU%, memory%, temperature).
+type Colorscheme struct {
+	Name string
+
+	BarLow  tcell.Color
+	BarMed  tcell.Color
+	BarHigh tcell.Color
+
+	SparklineColor tcell.Color
+	HeaderColor    tcell.Color
+
+	BatteryCharging    tcell.Color
+	BatteryDischarging tcell.Color
+
+	ThermalNormal tcell.Color
+	ThermalWarm   tcell.Color
+	ThermalHot    tcell.Color
+}
+
+// Default is the scheme matching this tree's pre-existing hardcoded colors,
+// so picking no scheme at all changes nothing about how the TUI looks.
+var Default = Colorscheme{
+	Name:               "default",
+	BarLow:             tcell.ColorGreen,
+	BarMed:             tcell.ColorYellow,
+	BarHigh:            tcell.ColorRed,
+	SparklineColor:     tcell.ColorYellow,
+	HeaderColor:        tcell.ColorTeal,
+	BatteryCharging:    tcell.ColorGreen,
+	BatteryDischarging: tcell.ColorYellow,
+	ThermalNormal:      tcell.ColorGreen,
+	ThermalWarm:        tcell.ColorYellow,
+	ThermalHot:         tcell.ColorRed,
+}
+
+// Monokai approximates the Monokai editor theme's palette.
+var Monokai = Colorscheme{
+	Name:               "monokai",
+	BarLow:             tcell.NewRGBColor(0xA6, 0xE2, 0x2E), // green
+	BarMed:             tcell.NewRGBColor(0xE6, 0xDB, 0x74), // yellow
+	BarHigh:            tcell.NewRGBColor(0xF9, 0x26, 0x72), // pink/red
+	SparklineColor:     tcell.NewRGBColor(0x66, 0xD9, 0xEF), // cyan
+	HeaderColor:        tcell.NewRGBColor(0xAE, 0x81, 0xFF), // purple
+	BatteryCharging:    tcell.NewRGBColor(0xA6, 0xE2, 0x2E),
+	BatteryDischarging: tcell.NewRGBColor(0xE6, 0xDB, 0x74),
+	ThermalNormal:      tcell.NewRGBColor(0xA6, 0xE2, 0x2E),
+	ThermalWarm:        tcell.NewRGBColor(0xE6, 0xDB, 0x74),
+	ThermalHot:         tcell.NewRGBColor(0xF9, 0x26, 0x72),
+}
+
+// Nord approximates the Nord theme's palette.
+var Nord = Colorscheme{
+	Name:               "nord",
+	BarLow:             tcell.NewRGBColor(0xA3, 0xBE, 0x8C), // nord14 green
+	BarMed:             tcell.NewRGBColor(0xEB, 0xCB, 0x8B), // nord13 yellow
+	BarHigh:            tcell.NewRGBColor(0xBF, 0x61, 0x6A), // nord11 red
+	SparklineColor:     tcell.NewRGBColor(0x88, 0xC0, 0xD0), // nord8 frost
+	HeaderColor:        tcell.NewRGBColor(0x81, 0xA1, 0xC1), // nord9 frost
+	BatteryCharging:    tcell.NewRGBColor(0xA3, 0xBE, 0x8C),
+	BatteryDischarging: tcell.NewRGBColor(0xEB, 0xCB, 0x8B),
+	ThermalNormal:      tcell.NewRGBColor(0xA3, 0xBE, 0x8C),
+	ThermalWarm:        tcell.NewRGBColor(0xEB, 0xCB, 0x8B),
+	ThermalHot:         tcell.NewRGBColor(0xBF, 0x61, 0x6A),
+}
+
+// Solarized approximates Solarized Dark's accent palette.
+var Solarized = Colorscheme{
+	Name:               "solarized",
+	BarLow:             tcell.NewRGBColor(0x85, 0x99, 0x00), // green
+	BarMed:             tcell.NewRGBColor(0xB5, 0x89, 0x00), // yellow
+	BarHigh:            tcell.NewRGBColor(0xDC, 0x32, 0x2F), // red
+	SparklineColor:     tcell.NewRGBColor(0x2A, 0xA1, 0x98), // cyan
+	HeaderColor:        tcell.NewRGBColor(0x26, 0x8B, 0xD2), // blue
+	BatteryCharging:    tcell.NewRGBColor(0x85, 0x99, 0x00),
+	BatteryDischarging: tcell.NewRGBColor(0xB5, 0x89, 0x00),
+	ThermalNormal:      tcell.NewRGBColor(0x85, 0x99, 0x00),
+	ThermalWarm:        tcell.NewRGBColor(0xB5, 0x89, 0x00),
+	ThermalHot:         tcell.NewRGBColor(0xDC, 0x32, 0x2F),
+}
+
+// registry holds every built-in scheme, keyed by Colorscheme.Name.
+var registry = map[string]Colorscheme{
+	Default.Name:   Default,
+	Monokai.Name:   Monokai,
+	Nord.Name:      Nord,
+	Solarized.Name: Solarized,
+}
+
+// Get returns the named built-in scheme, or ok=false if name isn't
+// registered (including names only available via LoadFromFile).
+func Get(name string) (Colorscheme, bool) {
+	cs, ok := registry[name]
+	return cs, ok
+}
+
+// Names returns every built-in scheme name, in registration order, for
+// cycling through with a keybinding.
+func Names() []string {
+	return []string{Default.Name, Monokai.Name, Nord.Name, Solarized.Name}
+}
@@ -0,0 +1,48 @@
+package colorschemes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetBuiltins(t *testing.T) {
+	for _, name := range Names() {
+		if _, ok := Get(name); !ok {
+			t.Errorf("Get(%q) not found despite being listed in Names()", name)
+		}
+	}
+	if _, ok := Get("not-a-scheme"); ok {
+		t.Errorf("Get(%q) unexpectedly found", "not-a-scheme")
+	}
+}
+
+func TestLoadFromFilePartialOverride(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mine.json")
+	if err := os.WriteFile(path, []byte(`{"bar_high": "#ff0000"}`), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	cs, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("LoadFromFile: %v", err)
+	}
+	if cs.Name != "mine" {
+		t.Errorf("Name = %q, want %q", cs.Name, "mine")
+	}
+	if cs.BarLow != Default.BarLow {
+		t.Errorf("BarLow = %v, want Default.BarLow %v (unset field should fall through)", cs.BarLow, Default.BarLow)
+	}
+}
+
+func TestLoadFromFileInvalidHex(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(path, []byte(`{"bar_low": "not-a-color"}`), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	if _, err := LoadFromFile(path); err == nil {
+		t.Error("LoadFromFile with an invalid hex color: want error, got nil")
+	}
+}
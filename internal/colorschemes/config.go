@@ -0,0 +1,101 @@
+package colorschemes
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// jsonScheme mirrors Colorscheme with hex-string colors ("#rrggbb"), the
+// shape a user-authored $XDG_CONFIG_HOME/powermetrics-tui/colorschemes/
+// NAME.json file takes. Any field left out keeps Default's color.
+type jsonScheme struct {
+	BarLow             string `json:"bar_low"`
+	BarMed             string `json:"bar_med"`
+	BarHigh            string `json:"bar_high"`
+	SparklineColor     string `json:"sparkline_color"`
+	HeaderColor        string `json:"header_color"`
+	BatteryCharging    string `json:"battery_charging"`
+	BatteryDischarging string `json:"battery_discharging"`
+	ThermalNormal      string `json:"thermal_normal"`
+	ThermalWarm        string `json:"thermal_warm"`
+	ThermalHot         string `json:"thermal_hot"`
+}
+
+// DefaultDir returns $XDG_CONFIG_HOME/powermetrics-tui/colorschemes, falling
+// back to ~/.config/powermetrics-tui/colorschemes when XDG_CONFIG_HOME is
+// unset, mirroring internal/layout.DefaultPath's resolution.
+func DefaultDir() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "powermetrics-tui", "colorschemes")
+}
+
+// LoadFromFile reads a NAME.json scheme file, starting from Default and
+// overriding only the fields present, so a file that only sets bar_high
+// still produces a complete, usable Colorscheme.
+func LoadFromFile(path string) (Colorscheme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Colorscheme{}, err
+	}
+
+	var js jsonScheme
+	if err := json.Unmarshal(data, &js); err != nil {
+		return Colorscheme{}, err
+	}
+
+	cs := Default
+	cs.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	fields := []struct {
+		hex string
+		dst *tcell.Color
+	}{
+		{js.BarLow, &cs.BarLow},
+		{js.BarMed, &cs.BarMed},
+		{js.BarHigh, &cs.BarHigh},
+		{js.SparklineColor, &cs.SparklineColor},
+		{js.HeaderColor, &cs.HeaderColor},
+		{js.BatteryCharging, &cs.BatteryCharging},
+		{js.BatteryDischarging, &cs.BatteryDischarging},
+		{js.ThermalNormal, &cs.ThermalNormal},
+		{js.ThermalWarm, &cs.ThermalWarm},
+		{js.ThermalHot, &cs.ThermalHot},
+	}
+	for _, f := range fields {
+		if f.hex == "" {
+			continue
+		}
+		c, err := parseHexColor(f.hex)
+		if err != nil {
+			return Colorscheme{}, fmt.Errorf("%s: %w", path, err)
+		}
+		*f.dst = c
+	}
+	return cs, nil
+}
+
+// parseHexColor parses a "#rrggbb" (or "rrggbb") string into a tcell.Color.
+func parseHexColor(hex string) (tcell.Color, error) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) != 6 {
+		return 0, fmt.Errorf("invalid color %q, want \"#rrggbb\"", hex)
+	}
+	v, err := strconv.ParseInt(hex, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid color %q: %w", hex, err)
+	}
+	return tcell.NewRGBColor(int32(v>>16&0xff), int32(v>>8&0xff), int32(v&0xff)), nil
+}
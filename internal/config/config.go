@@ -0,0 +1,79 @@
+// Package config loads the optional --config file that lets users disable
+// whole metric subsystems and tune history retention without recompiling,
+// modeled on cc-metric-collector's ExcludeMetrics list.
+package config
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Subsystem names accepted in Config.Disable.
+const (
+	SubsystemNetwork          = "network"
+	SubsystemDisk             = "disk"
+	SubsystemANE              = "ane"
+	SubsystemGPU              = "gpu"
+	SubsystemCoalitions       = "coalitions"
+	SubsystemPerCPUInterrupts = "per_cpu_interrupts"
+)
+
+// CPUFilter toggles which CPU core types organizeCPUFrequencies keeps, so
+// Intel Macs (P-cores only) or Apple Silicon Ultra chips can hide the
+// cluster pane they don't care about. Both default to true (shown).
+type CPUFilter struct {
+	ECores bool `yaml:"ecores"`
+	PCores bool `yaml:"pcores"`
+}
+
+// Config is the top-level shape of the --config file. It's parsed as YAML,
+// which also accepts plain JSON since JSON is a valid YAML subset.
+type Config struct {
+	Disable    []string  `yaml:"disable"`
+	HistoryLen int       `yaml:"history_len"`
+	CPUFilter  CPUFilter `yaml:"cpu_filter"`
+
+	// BatteryHistoryLen overrides MaxBatterySampleHistory (300 by default),
+	// the {timestamp, charge, state, backlight} ring buffer
+	// parser.BatteryHandler appends to each sample, separately from
+	// HistoryLen since it's a much richer entry and battery trends matter
+	// over a longer window than the other scalar histories HistoryLen caps.
+	BatteryHistoryLen int `yaml:"battery_history_len"`
+
+	// ExcludeMetrics lists individual metrics to skip at parse time, finer
+	// grained than Disable's whole-subsystem toggles (e.g. "ane_power" to
+	// drop just that one field while keeping the rest of the gpu/ane
+	// subsystem). See parser.CompileMetricFilter for the accepted names.
+	ExcludeMetrics []string `yaml:"exclude_metrics"`
+
+	disabled map[string]bool
+}
+
+// LoadConfig reads and parses a --config file from disk.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := Config{CPUFilter: CPUFilter{ECores: true, PCores: true}}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+
+	cfg.disabled = make(map[string]bool, len(cfg.Disable))
+	for _, name := range cfg.Disable {
+		cfg.disabled[name] = true
+	}
+	return &cfg, nil
+}
+
+// Disabled reports whether subsystem was named in Disable. A nil Config
+// (--config not given) never disables anything.
+func (c *Config) Disabled(subsystem string) bool {
+	if c == nil {
+		return false
+	}
+	return c.disabled[subsystem]
+}
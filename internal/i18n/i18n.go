@@ -0,0 +1,119 @@
+// Package i18n loads the TOML translation catalogs embedded under
+// translations/ and resolves dotted keys like "ui.system.memory" against
+// them, so internal/ui's draw functions can look up a label instead of
+// hardcoding English text. DetectLocale and --lang (see main.go) pick which
+// catalog backs the process; a key absent from that catalog falls back to
+// en_US, and a key absent from en_US too falls back to the key itself so a
+// typo never blanks out a label.
+package i18n
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+//go:embed translations/*.toml
+var embedded embed.FS
+
+// DefaultLocale is the catalog DetectLocale falls back to, and the one
+// Value falls back to for keys missing from the active locale.
+const DefaultLocale = "en_US"
+
+// Catalog is a loaded translation table, flattened to dotted keys (e.g.
+// "ui.system.memory") for direct lookup.
+type Catalog struct {
+	Locale string
+	values map[string]string
+}
+
+// Load reads translations/<locale>.toml and returns a Catalog that falls
+// back to DefaultLocale for any key it doesn't define. Loading DefaultLocale
+// itself returns a Catalog with no fallback beneath it.
+func Load(locale string) (Catalog, error) {
+	values, err := loadFlattened(locale)
+	if err != nil {
+		return Catalog{}, err
+	}
+
+	if locale != DefaultLocale {
+		fallback, err := loadFlattened(DefaultLocale)
+		if err != nil {
+			return Catalog{}, err
+		}
+		for k, v := range fallback {
+			if _, ok := values[k]; !ok {
+				values[k] = v
+			}
+		}
+	}
+	return Catalog{Locale: locale, values: values}, nil
+}
+
+func loadFlattened(locale string) (map[string]string, error) {
+	data, err := embedded.ReadFile("translations/" + locale + ".toml")
+	if err != nil {
+		return nil, fmt.Errorf("i18n: unknown locale %q: %w", locale, err)
+	}
+
+	var raw map[string]interface{}
+	if _, err := toml.Decode(string(data), &raw); err != nil {
+		return nil, fmt.Errorf("translations/%s.toml: %w", locale, err)
+	}
+
+	values := make(map[string]string)
+	flatten("", raw, values)
+	return values, nil
+}
+
+func flatten(prefix string, table map[string]interface{}, out map[string]string) {
+	for k, v := range table {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		switch v := v.(type) {
+		case string:
+			out[key] = v
+		case map[string]interface{}:
+			flatten(key, v, out)
+		}
+	}
+}
+
+// Value looks up key, falling back to DefaultLocale's value (folded in at
+// Load time) and then, if no catalog defines it either, the key itself.
+func (c Catalog) Value(key string) string {
+	if v, ok := c.values[key]; ok {
+		return v
+	}
+	return key
+}
+
+// DetectLocale derives a locale name ("de_DE") from LC_ALL/LANG (e.g.
+// "de_DE.UTF-8"), preferring LC_ALL as glibc does. It returns DefaultLocale
+// if neither is set, is "C"/"POSIX", or names a locale with no catalog.
+func DetectLocale() string {
+	env := os.Getenv("LC_ALL")
+	if env == "" {
+		env = os.Getenv("LANG")
+	}
+
+	locale, _, _ := strings.Cut(env, ".")
+	if locale == "" || locale == "C" || locale == "POSIX" {
+		return DefaultLocale
+	}
+	if !Available(locale) {
+		return DefaultLocale
+	}
+	return locale
+}
+
+// Available reports whether locale has an embedded catalog.
+func Available(locale string) bool {
+	_, err := embedded.ReadFile("translations/" + locale + ".toml")
+	return err == nil
+}
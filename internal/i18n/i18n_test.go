@@ -0,0 +1,48 @@
+package i18n
+
+import "testing"
+
+func TestLoadFallsBackToEnglishForMissingKeys(t *testing.T) {
+	cat, err := Load("de_DE")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got, want := cat.Value("ui.system.memory"), "Speicher"; got != want {
+		t.Errorf("Value(ui.system.memory) = %q, want %q", got, want)
+	}
+	if got := cat.Value("not.a.real.key"); got != "not.a.real.key" {
+		t.Errorf("Value of an unknown key = %q, want the key itself", got)
+	}
+}
+
+func TestLoadUnknownLocale(t *testing.T) {
+	if _, err := Load("xx_XX"); err == nil {
+		t.Error("Load(xx_XX): want error for a locale with no catalog, got nil")
+	}
+}
+
+func TestDetectLocalePrefersLCAll(t *testing.T) {
+	t.Setenv("LC_ALL", "de_DE.UTF-8")
+	t.Setenv("LANG", "ja_JP.UTF-8")
+	if got := DetectLocale(); got != "de_DE" {
+		t.Errorf("DetectLocale() = %q, want %q", got, "de_DE")
+	}
+}
+
+func TestDetectLocaleFallsBackToDefault(t *testing.T) {
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	if got := DetectLocale(); got != DefaultLocale {
+		t.Errorf("DetectLocale() with an uncataloged LANG = %q, want %q", got, DefaultLocale)
+	}
+}
+
+func TestFormatFloatUsesLocaleDecimalPoint(t *testing.T) {
+	cat, err := Load("de_DE")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got, want := cat.FormatFloat(12.3, 1), "12,3"; got != want {
+		t.Errorf("FormatFloat(12.3, 1) = %q, want %q", got, want)
+	}
+}
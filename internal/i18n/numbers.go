@@ -0,0 +1,27 @@
+package i18n
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatFloat renders f with the given decimal precision using the
+// catalog's "unit.decimal_point" (e.g. "," for de_DE instead of the Go
+// default "."), so a number embedded in a translated label reads the way a
+// reader of that locale expects.
+func (c Catalog) FormatFloat(f float64, prec int) string {
+	s := strconv.FormatFloat(f, 'f', prec, 64)
+	point := c.Value("unit.decimal_point")
+	if point == "." || point == "" {
+		return s
+	}
+	return strings.Replace(s, ".", point, 1)
+}
+
+// FormatRate renders a MB/s-style throughput value with FormatFloat's
+// decimal formatting and the catalog's "unit.rate" label, e.g. "12,3 MB/s"
+// for de_DE vs "12.3 MB/s" for en_US.
+func (c Catalog) FormatRate(mbPerSec float64, prec int) string {
+	return fmt.Sprintf("%s %s", c.FormatFloat(mbPerSec, prec), c.Value("unit.rate"))
+}
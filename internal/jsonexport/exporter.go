@@ -0,0 +1,103 @@
+// Package jsonexport streams models.MetricsState snapshots out as
+// newline-delimited JSON, so the tool can feed log shippers, jq pipelines,
+// or a remote collector instead of (or alongside) the TUI and the
+// Prometheus exporter in internal/promexport.
+package jsonexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"powermetrics-tui/internal/models"
+)
+
+// Sink is anywhere a rendered NDJSON line can go: a file, stdout, or an
+// HTTP collector.
+type Sink interface {
+	Write(line []byte) error
+}
+
+// Exporter renders state as one JSON object per call to Publish and hands
+// it to a Sink. Unlike promexport.Exporter it doesn't cache anything
+// between calls; every Publish is a fresh write.
+type Exporter struct {
+	sink Sink
+
+	// TopN, if non-zero, trims the processes/coalitions arrays of each
+	// published snapshot to the N highest by EnergyEstimate/energy impact,
+	// so a busy machine's NDJSON line doesn't grow with every process ever
+	// observed. Zero means publish every process and coalition.
+	TopN int
+}
+
+// NewExporter creates an Exporter that writes every published snapshot to
+// sink.
+func NewExporter(sink Sink) *Exporter {
+	return &Exporter{sink: sink}
+}
+
+// trimmedSnapshot wraps a *models.MetricsState to override just its
+// Processes/Coalitions fields for marshaling, without copying the embedded
+// sync.RWMutex the way copying a models.MetricsState by value would.
+type trimmedSnapshot struct {
+	*models.MetricsState
+	Processes  []models.ProcessInfo      `json:"processes"`
+	Coalitions []models.ProcessCoalition `json:"coalitions"`
+}
+
+// Publish renders state as a single line of JSON and writes it to the
+// sink. Call this once per finalized sample, e.g. from
+// StateMachine.FinalizeCurrentState.
+func (e *Exporter) Publish(state *models.MetricsState) error {
+	state.Mu.RLock()
+	var line []byte
+	var err error
+	if e.TopN > 0 {
+		line, err = json.Marshal(&trimmedSnapshot{
+			MetricsState: state,
+			Processes:    topProcessesByEnergy(state.Processes, e.TopN),
+			Coalitions:   topCoalitionsByEnergy(state.Coalitions, e.TopN),
+		})
+	} else {
+		line, err = json.Marshal(state)
+	}
+	state.Mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("jsonexport: marshal snapshot: %w", err)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(line)
+	buf.WriteByte('\n')
+	return e.sink.Write(buf.Bytes())
+}
+
+// topProcessesByEnergy returns a copy of processes, sorted by EnergyEstimate
+// descending and truncated to n.
+func topProcessesByEnergy(processes []models.ProcessInfo, n int) []models.ProcessInfo {
+	sorted := make([]models.ProcessInfo, len(processes))
+	copy(sorted, processes)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].EnergyEstimate > sorted[j].EnergyEstimate
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// topCoalitionsByEnergy is topProcessesByEnergy's coalition counterpart,
+// ranking by each coalition's own subprocess energy-impact sum.
+func topCoalitionsByEnergy(coalitions []models.ProcessCoalition, n int) []models.ProcessCoalition {
+	sorted := make([]models.ProcessCoalition, len(coalitions))
+	copy(sorted, coalitions)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].SubprocessPowerSum > sorted[j].SubprocessPowerSum
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
@@ -0,0 +1,114 @@
+package jsonexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"powermetrics-tui/internal/models"
+)
+
+func TestPublishWritesOneLinePerSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.ndjson")
+
+	sink, err := NewSinkForTarget(path)
+	if err != nil {
+		t.Fatalf("NewSinkForTarget failed: %v", err)
+	}
+	exp := NewExporter(sink)
+
+	state := models.NewMetricsState()
+	state.CPUPower = 4200
+	if err := exp.Publish(state); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	state.CPUPower = 5100
+	if err := exp.Publish(state); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	var lines []map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var m map[string]interface{}
+		if err := dec.Decode(&m); err != nil {
+			t.Fatalf("decode line: %v", err)
+		}
+		lines = append(lines, m)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 NDJSON lines, got %d", len(lines))
+	}
+	if lines[0]["cpu_power_mw"] != 4200.0 {
+		t.Errorf("expected first line cpu_power_mw=4200, got %v", lines[0]["cpu_power_mw"])
+	}
+	if lines[1]["cpu_power_mw"] != 5100.0 {
+		t.Errorf("expected second line cpu_power_mw=5100, got %v", lines[1]["cpu_power_mw"])
+	}
+}
+
+func TestPublishTrimsToTopNByEnergy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.ndjson")
+
+	sink, err := NewSinkForTarget(path)
+	if err != nil {
+		t.Fatalf("NewSinkForTarget failed: %v", err)
+	}
+	exp := NewExporter(sink)
+	exp.TopN = 1
+
+	state := models.NewMetricsState()
+	state.Processes = []models.ProcessInfo{
+		{PID: 1, Name: "quiet", EnergyEstimate: 1},
+		{PID: 2, Name: "loud", EnergyEstimate: 99},
+	}
+	if err := exp.Publish(state); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+
+	var line map[string]interface{}
+	if err := json.Unmarshal(data, &line); err != nil {
+		t.Fatalf("decode line: %v", err)
+	}
+
+	procs, ok := line["processes"].([]interface{})
+	if !ok || len(procs) != 1 {
+		t.Fatalf("expected 1 process after trimming, got %v", line["processes"])
+	}
+	got := procs[0].(map[string]interface{})
+	if got["name"] != "loud" {
+		t.Errorf("expected the higher-energy process to survive trimming, got %v", got["name"])
+	}
+}
+
+func TestNewSinkForTargetSelectsSinkKind(t *testing.T) {
+	hs, err := NewSinkForTarget("http://localhost:9200/ingest")
+	if err != nil {
+		t.Fatalf("NewSinkForTarget(http) failed: %v", err)
+	}
+	if _, ok := hs.(*httpSink); !ok {
+		t.Errorf("expected *httpSink for an http:// target, got %T", hs)
+	}
+
+	path := filepath.Join(t.TempDir(), "out.ndjson")
+	fs, err := NewSinkForTarget(path)
+	if err != nil {
+		t.Fatalf("NewSinkForTarget(path) failed: %v", err)
+	}
+	if _, ok := fs.(*fileSink); !ok {
+		t.Errorf("expected *fileSink for a plain path, got %T", fs)
+	}
+}
@@ -0,0 +1,105 @@
+package jsonexport
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+)
+
+// fileSink appends NDJSON lines to a file on disk, buffered and flushed
+// after every write so a tailing `jq` sees each sample promptly.
+type fileSink struct {
+	mu sync.Mutex
+	f  *os.File
+	w  *bufio.Writer
+}
+
+// newFileSink opens path for appending (creating it if needed).
+func newFileSink(path string) (*fileSink, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &fileSink{f: f, w: bufio.NewWriter(f)}, nil
+}
+
+func (s *fileSink) Write(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(line); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// Close flushes and closes the underlying file.
+func (s *fileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.w.Flush(); err != nil {
+		s.f.Close()
+		return err
+	}
+	return s.f.Close()
+}
+
+// stdoutSink writes NDJSON lines to os.Stdout, for piping into `jq` or a
+// log shipper's stdin.
+type stdoutSink struct {
+	mu sync.Mutex
+	w  *bufio.Writer
+}
+
+func newStdoutSink() *stdoutSink {
+	return &stdoutSink{w: bufio.NewWriter(os.Stdout)}
+}
+
+func (s *stdoutSink) Write(line []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write(line); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// httpSink POSTs each snapshot individually to a collector URL. Errors are
+// returned to the caller (unlike the alerts Hook, a failed POST isn't
+// fire-and-forget) so the caller can decide whether to log and continue.
+type httpSink struct {
+	url    string
+	client *http.Client
+}
+
+func newHTTPSink(target string) *httpSink {
+	return &httpSink{url: target, client: &http.Client{}}
+}
+
+func (s *httpSink) Write(line []byte) error {
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(line))
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// NewSinkForTarget builds the right Sink for target: "-" means stdout, an
+// http:// or https:// URL means one POST per snapshot, and anything else
+// is treated as a file path to append to.
+func NewSinkForTarget(target string) (Sink, error) {
+	if target == "-" {
+		return newStdoutSink(), nil
+	}
+	if u, err := url.Parse(target); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		return newHTTPSink(target), nil
+	}
+	return newFileSink(strings.TrimSpace(target))
+}
@@ -0,0 +1,30 @@
+package layout
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultPath returns the layout spec path main looks for when --layout
+// isn't given: $XDG_CONFIG_HOME/powermetrics-tui/layout, falling back to
+// ~/.config/powermetrics-tui/layout when XDG_CONFIG_HOME is unset.
+func DefaultPath() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "powermetrics-tui", "layout")
+}
+
+// LoadSpec reads and parses the layout spec at path.
+func LoadSpec(path string) ([]Row, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data))
+}
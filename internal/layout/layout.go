@@ -0,0 +1,105 @@
+// Package layout parses a small gotop-inspired text DSL for arranging named
+// widgets into a grid of rows and weighted columns, and renders that grid by
+// dispatching each cell to a registered draw function. It lets a user
+// compose a custom dashboard (e.g. "cpu\ndisk/1 mem/2\nbattery network")
+// instead of being limited to the TUI's hardcoded per-metric views.
+package layout
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Widget is one leaf of the grid: a named panel plus its weight, which
+// determines how much of its column's height it gets when stacked on top of
+// other widgets (see Column.Widgets). A Widget with no stacking siblings
+// simply fills its whole column.
+type Widget struct {
+	Name   string
+	Weight int
+}
+
+// Column is one cell of a Row: one or more Widgets stacked vertically
+// (separated by ':' in the spec), plus the Weight that determines this
+// column's share of the row's width relative to its sibling columns.
+type Column struct {
+	Widgets []Widget
+	Weight  int
+}
+
+// Row is one line of the spec: one or more Columns laid out left to right,
+// sharing the grid's rows evenly (the DSL has no per-row weight).
+type Row struct {
+	Columns []Column
+}
+
+// Parse reads a layout spec and returns its rows, or an error if the spec is
+// empty or malformed. The grammar, one row per line:
+//
+//	row     := column (WS column)*
+//	column  := widget (':' widget)*
+//	widget  := name ['/' weight]
+//
+// weight is a positive integer defaulting to 1. A column's Weight is taken
+// from its first widget's weight and sizes that column's share of its row's
+// width; each stacked widget's own weight sizes its share of the column's
+// height. Blank lines are ignored. Widget names are not validated here -
+// that's Renderer's job at draw time, so a spec referencing a widget this
+// build doesn't register still parses and shows a placeholder instead of
+// failing to start.
+func Parse(spec string) ([]Row, error) {
+	var rows []Row
+	for lineNum, line := range strings.Split(spec, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		var row Row
+		for _, colTok := range strings.Fields(line) {
+			col, err := parseColumn(colTok)
+			if err != nil {
+				return nil, fmt.Errorf("layout line %d: %w", lineNum+1, err)
+			}
+			row.Columns = append(row.Columns, col)
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("layout spec has no rows")
+	}
+	return rows, nil
+}
+
+func parseColumn(tok string) (Column, error) {
+	var col Column
+	for _, widgetTok := range strings.Split(tok, ":") {
+		w, err := parseWidget(widgetTok)
+		if err != nil {
+			return Column{}, err
+		}
+		col.Widgets = append(col.Widgets, w)
+	}
+	col.Weight = col.Widgets[0].Weight
+	return col, nil
+}
+
+func parseWidget(tok string) (Widget, error) {
+	name, weightStr, hasWeight := strings.Cut(tok, "/")
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return Widget{}, fmt.Errorf("empty widget name in %q", tok)
+	}
+
+	weight := 1
+	if hasWeight {
+		n, err := strconv.Atoi(strings.TrimSpace(weightStr))
+		if err != nil || n <= 0 {
+			return Widget{}, fmt.Errorf("invalid weight in %q", tok)
+		}
+		weight = n
+	}
+	return Widget{Name: name, Weight: weight}, nil
+}
@@ -0,0 +1,69 @@
+package layout
+
+import "testing"
+
+func TestParseSingleWidgetRow(t *testing.T) {
+	rows, err := Parse("cpu")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 1 || len(rows[0].Columns) != 1 || len(rows[0].Columns[0].Widgets) != 1 {
+		t.Fatalf("expected one row/column/widget, got %+v", rows)
+	}
+	if got := rows[0].Columns[0].Widgets[0]; got.Name != "cpu" || got.Weight != 1 {
+		t.Errorf("expected {cpu 1}, got %+v", got)
+	}
+}
+
+func TestParseColumnWeights(t *testing.T) {
+	rows, err := Parse("disk/1 mem/2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cols := rows[0].Columns
+	if len(cols) != 2 {
+		t.Fatalf("expected 2 columns, got %d", len(cols))
+	}
+	if cols[0].Weight != 1 || cols[1].Weight != 2 {
+		t.Errorf("expected column weights 1 and 2, got %d and %d", cols[0].Weight, cols[1].Weight)
+	}
+}
+
+func TestParseStackedColumn(t *testing.T) {
+	rows, err := Parse("battery:network/2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	widgets := rows[0].Columns[0].Widgets
+	if len(widgets) != 2 {
+		t.Fatalf("expected 2 stacked widgets, got %d", len(widgets))
+	}
+	if widgets[0].Name != "battery" || widgets[0].Weight != 1 {
+		t.Errorf("expected {battery 1}, got %+v", widgets[0])
+	}
+	if widgets[1].Name != "network" || widgets[1].Weight != 2 {
+		t.Errorf("expected {network 2}, got %+v", widgets[1])
+	}
+}
+
+func TestParseMultipleRows(t *testing.T) {
+	rows, err := Parse("cpu\ndisk/1 mem/2\nbattery network")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(rows))
+	}
+}
+
+func TestParseEmptySpec(t *testing.T) {
+	if _, err := Parse("\n\n  \n"); err == nil {
+		t.Fatalf("expected an error for a spec with no rows")
+	}
+}
+
+func TestParseInvalidWeight(t *testing.T) {
+	if _, err := Parse("cpu/nope"); err == nil {
+		t.Fatalf("expected an error for a non-numeric weight")
+	}
+}
@@ -0,0 +1,120 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/ui"
+)
+
+// DrawFunc renders one widget into the rectangle (x, y, w, h), the shape
+// Renderer dispatches every grid cell to.
+type DrawFunc func(screen tcell.Screen, state *models.MetricsState, x, y, w, h int)
+
+// Renderer maps widget names from a parsed layout to DrawFuncs and lays out
+// a grid of Rows into a screen rectangle.
+type Renderer struct {
+	widgets map[string]DrawFunc
+}
+
+// NewRenderer builds a Renderer with the built-in widget set registered:
+// "cpu", "gpu", "battery", "system", "network", "disk", "memory", and
+// "combined" (a thin wrapper around ui.DrawCombinedViewWithStartY - since
+// that view hardcodes its own column positions from x=0, it renders best as
+// a full-width row rather than stacked alongside other widgets).
+func NewRenderer() *Renderer {
+	r := &Renderer{widgets: make(map[string]DrawFunc)}
+	r.Register("cpu", drawCPUWidget)
+	r.Register("gpu", drawGPUWidget)
+	r.Register("battery", drawBatteryWidget)
+	r.Register("system", drawSystemWidget)
+	r.Register("network", drawNetworkWidget)
+	r.Register("disk", drawDiskWidget)
+	r.Register("memory", drawMemoryWidget)
+	r.Register("combined", drawCombinedWidget)
+	return r
+}
+
+// Register adds or replaces the DrawFunc for name.
+func (r *Renderer) Register(name string, fn DrawFunc) {
+	r.widgets[name] = fn
+}
+
+// Render draws rows into the rectangle (x, y, w, h), splitting height evenly
+// across rows, width across each row's columns by Column.Weight, and a
+// column's height across its stacked Widgets by Widget.Weight. Each leaf
+// gets a bordered, titled box with its DrawFunc's output inside.
+func (r *Renderer) Render(screen tcell.Screen, state *models.MetricsState, x, y, w, h int, rows []Row) {
+	if len(rows) == 0 || h <= 0 || w <= 0 {
+		return
+	}
+
+	rowH := h / len(rows)
+	rowY := y
+	for i, row := range rows {
+		thisRowH := rowH
+		if i == len(rows)-1 {
+			thisRowH = h - (rowY - y) // last row absorbs any remainder
+		}
+		r.renderRow(screen, state, x, rowY, w, thisRowH, row)
+		rowY += thisRowH
+	}
+}
+
+func (r *Renderer) renderRow(screen tcell.Screen, state *models.MetricsState, x, y, w, h int, row Row) {
+	totalWeight := 0
+	for _, col := range row.Columns {
+		totalWeight += col.Weight
+	}
+	if totalWeight <= 0 {
+		return
+	}
+
+	colX := x
+	for i, col := range row.Columns {
+		colW := w * col.Weight / totalWeight
+		if i == len(row.Columns)-1 {
+			colW = w - (colX - x) // last column absorbs any remainder
+		}
+		r.renderColumn(screen, state, colX, y, colW, h, col)
+		colX += colW
+	}
+}
+
+func (r *Renderer) renderColumn(screen tcell.Screen, state *models.MetricsState, x, y, w, h int, col Column) {
+	totalWeight := 0
+	for _, widget := range col.Widgets {
+		totalWeight += widget.Weight
+	}
+	if totalWeight <= 0 {
+		return
+	}
+
+	widgetY := y
+	for i, widget := range col.Widgets {
+		widgetH := h * widget.Weight / totalWeight
+		if i == len(col.Widgets)-1 {
+			widgetH = h - (widgetY - y) // last widget absorbs any remainder
+		}
+		r.renderWidget(screen, state, x, widgetY, w, widgetH, widget)
+		widgetY += widgetH
+	}
+}
+
+func (r *Renderer) renderWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int, widget Widget) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	ui.DrawBox(screen, x, y, w, h, tcell.StyleDefault.Foreground(tcell.ColorGray))
+
+	fn, ok := r.widgets[widget.Name]
+	if !ok {
+		ui.DrawText(screen, x+2, y+1, fmt.Sprintf("unknown widget %q", widget.Name), tcell.StyleDefault.Foreground(tcell.ColorRed))
+		return
+	}
+	if w <= 2 || h <= 2 {
+		return // too small for anything but the border itself
+	}
+	fn(screen, state, x+1, y+1, w-2, h-2)
+}
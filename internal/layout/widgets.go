@@ -0,0 +1,100 @@
+package layout
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/ui"
+)
+
+// drawCPUWidget renders a compact CPU power summary, the condensed
+// counterpart of ui.DrawPowerViewWithSmoothing's CPU row.
+func drawCPUWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	ui.DrawText(screen, x, y, "CPU", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorGreen))
+	ui.DrawText(screen, x, y+1, fmt.Sprintf("%.0f mW", state.CPUPower), tcell.StyleDefault)
+	if h > 2 {
+		ui.DrawBar(screen, x, y+2, w, state.CPUPower, 10000, tcell.ColorGreen)
+	}
+}
+
+func drawGPUWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	ui.DrawText(screen, x, y, "GPU", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorBlue))
+	ui.DrawText(screen, x, y+1, fmt.Sprintf("%.0f mW", state.GPUPower), tcell.StyleDefault)
+	if h > 2 {
+		ui.DrawBar(screen, x, y+2, w, state.GPUPower, 5000, tcell.ColorBlue)
+	}
+}
+
+func drawSystemWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	ui.DrawText(screen, x, y, "SYSTEM", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorYellow))
+	ui.DrawText(screen, x, y+1, fmt.Sprintf("%.0f mW total", state.SystemPower), tcell.StyleDefault)
+	if h > 2 {
+		ui.DrawBar(screen, x, y+2, w, state.SystemPower, 20000, tcell.ColorYellow)
+	}
+}
+
+func drawBatteryWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	ui.DrawText(screen, x, y, "BATTERY", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorGreen))
+	ui.DrawText(screen, x, y+1, fmt.Sprintf("%.0f%% %s", state.BatteryCharge, state.BatteryState), tcell.StyleDefault)
+	if h > 2 {
+		ui.DrawBar(screen, x, y+2, w, state.BatteryCharge, 100, ui.GetColorForValue(100-state.BatteryCharge, 30, 80))
+	}
+}
+
+func drawNetworkWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	ui.DrawText(screen, x, y, "NETWORK", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorGreen))
+	ui.DrawText(screen, x, y+1, fmt.Sprintf("in %.1f / out %.1f MB/s", state.NetworkIn, state.NetworkOut), tcell.StyleDefault)
+	if h > 2 && len(state.History.NetworkInHistory) > 0 {
+		ui.DrawSparkline(screen, x, y+2, w, state.History.NetworkInHistory, tcell.ColorGreen)
+	}
+}
+
+func drawDiskWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	ui.DrawText(screen, x, y, "DISK", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorPurple))
+	ui.DrawText(screen, x, y+1, fmt.Sprintf("read %.1f / write %.1f MB/s", state.DiskRead, state.DiskWrite), tcell.StyleDefault)
+	if h > 2 && len(state.History.DiskReadHistory) > 0 {
+		ui.DrawSparkline(screen, x, y+2, w, state.History.DiskReadHistory, tcell.ColorPurple)
+	}
+}
+
+func drawMemoryWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	percent := 0.0
+	if total := state.MemoryUsed + state.MemoryAvailable; total > 0 {
+		percent = state.MemoryUsed / total * 100
+	}
+
+	ui.DrawText(screen, x, y, "MEMORY", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorTeal))
+	ui.DrawText(screen, x, y+1, fmt.Sprintf("%.0f%% (%.0f MB used)", percent, state.MemoryUsed), tcell.StyleDefault)
+	if h > 2 {
+		ui.DrawBar(screen, x, y+2, w, percent, 100, ui.GetColorForValue(percent, 60, 85))
+	}
+}
+
+// drawCombinedWidget delegates to the full combined view rather than a
+// condensed summary, since it's already a compact multi-metric overview; see
+// NewRenderer's doc comment on the x-offset limitation this implies.
+func drawCombinedWidget(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+	ui.DrawCombinedViewWithStartY(screen, state, w, h, y)
+}
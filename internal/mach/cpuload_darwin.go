@@ -0,0 +1,110 @@
+//go:build darwin && cgo
+
+// Package mach provides a small cgo-backed sampler for classical per-core
+// user/system/idle/nice CPU utilization via host_processor_info, which
+// `powermetrics` itself doesn't report (it gives frequency and idle
+// residency, not time-in-state). Modeled on the Darwin code path
+// gopsutil/telegraf use: host_processor_info(PROCESSOR_CPU_LOAD_INFO)
+// returns a cumulative tick count per core, which must be delta'd against
+// the previous sample; each state's share of the total tick delta is its
+// percentage, the same normalization gopsutil's Linux /proc/stat path uses.
+package mach
+
+/*
+#include <mach/mach.h>
+#include <mach/mach_host.h>
+#include <mach/processor_info.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"sync"
+	"unsafe"
+)
+
+// CPULoad is one CPU's user/system/idle/nice percentage of the interval
+// since the previous Sample call.
+type CPULoad struct {
+	User, System, Idle, Nice float64
+}
+
+// ticks is one core's four cumulative Mach processor-load counters.
+type ticks struct {
+	user, system, idle, nice uint64
+}
+
+// Sampler keeps the previous call's ticks so successive Sample calls report
+// a delta-based percentage instead of a meaningless cumulative count since
+// boot. The zero value is ready to use.
+type Sampler struct {
+	mu   sync.Mutex
+	prev map[int]ticks
+}
+
+// NewSampler returns a Sampler ready for repeated calls to Sample.
+func NewSampler() *Sampler {
+	return &Sampler{prev: make(map[int]ticks)}
+}
+
+// Sample reads the current cumulative per-core tick counts via
+// host_processor_info and returns each core's percentage of the ticks
+// elapsed since the previous call. The first call after NewSampler has
+// nothing to delta against, so it returns an empty map.
+func (s *Sampler) Sample() (map[int]CPULoad, error) {
+	var numCPUs C.natural_t
+	var cpuLoad *C.processor_cpu_load_info_data_t
+	var infoCnt C.mach_msg_type_number_t
+
+	kr := C.host_processor_info(
+		C.host_t(C.mach_host_self()),
+		C.PROCESSOR_CPU_LOAD_INFO,
+		&numCPUs,
+		(*C.processor_info_array_t)(unsafe.Pointer(&cpuLoad)),
+		&infoCnt,
+	)
+	if kr != C.KERN_SUCCESS {
+		return nil, errors.New("mach: host_processor_info failed")
+	}
+	defer C.vm_deallocate(C.mach_task_self_, C.vm_address_t(uintptr(unsafe.Pointer(cpuLoad))), C.vm_size_t(infoCnt)*C.vm_size_t(unsafe.Sizeof(C.integer_t(0))))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.prev == nil {
+		s.prev = make(map[int]ticks)
+	}
+
+	result := make(map[int]CPULoad, int(numCPUs))
+	for i := 0; i < int(numCPUs); i++ {
+		core := (*C.processor_cpu_load_info_data_t)(unsafe.Pointer(
+			uintptr(unsafe.Pointer(cpuLoad)) + uintptr(i)*C.sizeof_processor_cpu_load_info_data_t,
+		))
+
+		cur := ticks{
+			user:   uint64(core.cpu_ticks[C.CPU_STATE_USER]),
+			system: uint64(core.cpu_ticks[C.CPU_STATE_SYSTEM]),
+			idle:   uint64(core.cpu_ticks[C.CPU_STATE_IDLE]),
+			nice:   uint64(core.cpu_ticks[C.CPU_STATE_NICE]),
+		}
+
+		prev, ok := s.prev[i]
+		s.prev[i] = cur
+		if !ok {
+			continue
+		}
+
+		total := float64((cur.user - prev.user) + (cur.system - prev.system) + (cur.idle - prev.idle) + (cur.nice - prev.nice))
+		if total <= 0 {
+			continue
+		}
+
+		result[i] = CPULoad{
+			User:   float64(cur.user-prev.user) / total * 100,
+			System: float64(cur.system-prev.system) / total * 100,
+			Idle:   float64(cur.idle-prev.idle) / total * 100,
+			Nice:   float64(cur.nice-prev.nice) / total * 100,
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,34 @@
+//go:build !(darwin && cgo)
+
+// Package mach provides a small cgo-backed sampler for classical per-core
+// user/system/idle/nice CPU utilization via host_processor_info. Outside
+// darwin+cgo builds there's no such syscall, so Sample always reports
+// ErrUnsupported and callers fall back to whatever powermetrics/gopsutil
+// already give them.
+package mach
+
+import "errors"
+
+// ErrUnsupported is returned by Sample on any build without host_processor_info
+// support (anything but darwin with cgo enabled).
+var ErrUnsupported = errors.New("mach: per-CPU usage sampling requires darwin with cgo enabled")
+
+// CPULoad is one CPU's user/system/idle/nice percentage of the interval
+// since the previous Sample call.
+type CPULoad struct {
+	User, System, Idle, Nice float64
+}
+
+// Sampler is a no-op stand-in for the darwin+cgo Sampler, so callers don't
+// need their own build-tag switch.
+type Sampler struct{}
+
+// NewSampler returns a Sampler whose Sample always fails with ErrUnsupported.
+func NewSampler() *Sampler {
+	return &Sampler{}
+}
+
+// Sample always returns ErrUnsupported on this build.
+func (s *Sampler) Sample() (map[int]CPULoad, error) {
+	return nil, ErrUnsupported
+}
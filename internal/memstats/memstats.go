@@ -0,0 +1,50 @@
+// Package memstats computes PSS (proportional set size) and USS (unique
+// set size) memory accounting per PID, the same approach tools like vmmap
+// use to avoid double-counting shared library pages that powermetrics'
+// coarse per-coalition userPercent figure doesn't distinguish from memory a
+// process owns outright.
+package memstats
+
+// ProcessMemory is one PID's private/shared page accounting for a single
+// sample.
+type ProcessMemory struct {
+	// PrivateKB is memory resident only in this process - not mapped by
+	// any other task, and USS's whole value.
+	PrivateKB float64
+
+	// SharedKB is memory resident in this process' address space but also
+	// mapped (via a shared library, mmap, or copy-on-write fork) by at
+	// least one other task.
+	SharedKB float64
+
+	// PSSKB is PrivateKB plus SharedKB divided proportionally across every
+	// task that maps it, so summing PSS across all processes on a machine
+	// approximates total resident memory without shared pages being
+	// counted once per mapper.
+	PSSKB float64
+}
+
+// USSKB is PrivateKB under the more common name for "unique set size".
+func (m ProcessMemory) USSKB() float64 { return m.PrivateKB }
+
+// Accountant computes ProcessMemory for a PID. NewAccountant's result is
+// ready to use; accounting unsupported on this build always returns
+// ErrUnsupported.
+type Accountant interface {
+	ProcessMemory(pid int) (ProcessMemory, error)
+}
+
+// Rollup sums ProcessMemory across pids into a coalition-level PSS/USS
+// total in MB, skipping any PID a's ProcessMemory can't resolve (already
+// exited, or permission denied) rather than failing the whole rollup.
+func Rollup(a Accountant, pids []int) (pssMB, ussMB float64) {
+	for _, pid := range pids {
+		pm, err := a.ProcessMemory(pid)
+		if err != nil {
+			continue
+		}
+		pssMB += pm.PSSKB / 1024
+		ussMB += pm.USSKB() / 1024
+	}
+	return pssMB, ussMB
+}
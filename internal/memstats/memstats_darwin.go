@@ -0,0 +1,97 @@
+//go:build darwin && cgo
+
+package memstats
+
+/*
+#include <mach/mach.h>
+#include <mach/mach_vm.h>
+#include <sys/sysctl.h>
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+)
+
+// ErrUnsupported is never returned on darwin+cgo; it exists so callers can
+// compare against the same sentinel on every build.
+var ErrUnsupported = errors.New("memstats: per-PID page accounting requires darwin with cgo enabled")
+
+// machAccountant walks each region of a task's address space via
+// vm_region_recurse_64, the same call vmmap uses, classifying pages by
+// share_mode to split resident memory into private and shared, and
+// dividing shared pages by ref_count to get each task's proportional
+// share - the standard PSS definition.
+type machAccountant struct{}
+
+// NewAccountant returns an Accountant backed by task/vm region introspection.
+// Requires the caller to hold (or be running as) an account with
+// task_for_pid entitlement; a PID this process can't introspect just fails
+// that one ProcessMemory call.
+func NewAccountant() Accountant {
+	return &machAccountant{}
+}
+
+func (a *machAccountant) ProcessMemory(pid int) (ProcessMemory, error) {
+	var task C.task_t
+	kr := C.task_for_pid(C.mach_task_self_, C.int(pid), &task)
+	if kr != C.KERN_SUCCESS {
+		return ProcessMemory{}, errors.New("memstats: task_for_pid failed")
+	}
+	defer C.mach_port_deallocate(C.mach_task_self_, C.mach_port_name_t(task))
+
+	var privatePages, sharedPages uint64
+	var pssPages float64
+
+	var address C.mach_vm_address_t
+	for {
+		var size C.mach_vm_size_t
+		var depth C.natural_t
+		var info C.vm_region_submap_info_data_64_t
+		infoCnt := C.mach_msg_type_number_t(C.VM_REGION_SUBMAP_INFO_COUNT_64)
+
+		kr = C.mach_vm_region_recurse(task, &address, &size, &depth,
+			C.vm_region_recurse_info_t(unsafe.Pointer(&info)), &infoCnt)
+		if kr != C.KERN_SUCCESS {
+			// KERN_INVALID_ADDRESS once address runs off the end of the
+			// address space; any other failure just stops the walk early
+			// with whatever was accumulated so far.
+			break
+		}
+
+		resident := uint64(info.pages_resident)
+		switch info.share_mode {
+		case C.SM_PRIVATE:
+			privatePages += resident
+			pssPages += float64(resident)
+		case C.SM_COW:
+			copied := uint64(info.pages_shared_now_private)
+			priv := copied
+			if priv > resident {
+				priv = resident
+			}
+			shared := resident - priv
+			privatePages += priv
+			sharedPages += shared
+			pssPages += float64(priv)
+			if info.ref_count > 0 {
+				pssPages += float64(shared) / float64(info.ref_count)
+			}
+		case C.SM_SHARED, C.SM_TRUESHARED:
+			sharedPages += resident
+			if info.ref_count > 0 {
+				pssPages += float64(resident) / float64(info.ref_count)
+			}
+		}
+
+		address += C.mach_vm_address_t(size)
+	}
+
+	pageSizeKB := float64(C.vm_page_size) / 1024
+	return ProcessMemory{
+		PrivateKB: float64(privatePages) * pageSizeKB,
+		SharedKB:  float64(sharedPages) * pageSizeKB,
+		PSSKB:     pssPages * pageSizeKB,
+	}, nil
+}
@@ -0,0 +1,24 @@
+//go:build !(darwin && cgo)
+
+package memstats
+
+import "errors"
+
+// ErrUnsupported is returned by stubAccountant.ProcessMemory on any build
+// without task/vm region introspection (anything but darwin with cgo
+// enabled).
+var ErrUnsupported = errors.New("memstats: per-PID page accounting requires darwin with cgo enabled")
+
+// stubAccountant is a no-op stand-in for the darwin+cgo Accountant, so
+// callers don't need their own build-tag switch.
+type stubAccountant struct{}
+
+// NewAccountant returns an Accountant whose ProcessMemory always fails with
+// ErrUnsupported on this build.
+func NewAccountant() Accountant {
+	return &stubAccountant{}
+}
+
+func (a *stubAccountant) ProcessMemory(pid int) (ProcessMemory, error) {
+	return ProcessMemory{}, ErrUnsupported
+}
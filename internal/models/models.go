@@ -1,161 +1,613 @@
 package models
 
 import (
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
 
 // MetricsState holds all system metrics
 type MetricsState struct {
-	Mu sync.RWMutex
+	Mu sync.RWMutex `json:"-"`
 
 	// CPU interrupts
-	IPICount        int
-	TimerCount      int
-	TotalInterrupts int
+	IPICount        int `json:"ipi_count"`
+	TimerCount      int `json:"timer_count"`
+	TotalInterrupts int `json:"total_interrupts"`
 
 	// Per-CPU interrupt breakdown
-	PerCPUInterrupts map[string]float64 // CPU identifier -> interrupt rate
-	PerCPUIPIs       map[string]float64 // CPU identifier -> IPI rate
-	PerCPUTimers     map[string]float64 // CPU identifier -> Timer rate
-	AllSeenCPUs      map[string]bool    // Track all CPUs ever seen for consistent display
+	PerCPUInterrupts map[string]float64 `json:"per_cpu_interrupts"` // CPU identifier -> interrupt rate
+	PerCPUIPIs       map[string]float64 `json:"per_cpu_ipis"`       // CPU identifier -> IPI rate
+	PerCPUTimers     map[string]float64 `json:"per_cpu_timers"`     // CPU identifier -> Timer rate
+	AllSeenCPUs      map[string]bool    `json:"-"`                  // Track all CPUs ever seen for consistent display
+
+	// PerCPUInterruptsLastSeen records the last time each CPU actually
+	// reported an interrupts section, so a sample where it's briefly
+	// missing (CPU offlined mid-burst, or a powermetrics hiccup) can be
+	// told apart from the CPU being genuinely idle/offline for a while.
+	// See RunningTasksHandler's counterpart PID tracking for the same
+	// "missing vs. actually gone" distinction applied to processes.
+	PerCPUInterruptsLastSeen map[string]time.Time `json:"-"`
 
 	// Per-CPU interrupt history for sparklines
-	PerCPUInterruptHistory map[string][]float64 // CPU identifier -> interrupt history
+	PerCPUInterruptHistory map[string][]float64 `json:"-"` // CPU identifier -> interrupt history
+
+	// PerCPUUtilization is each core's busy% (0-100), derived from the
+	// user/system/idle tick deltas gopsutil reads from /proc/stat - the
+	// same delta-over-elapsed-time shape PerCPUInterrupts uses for
+	// interrupt rates, just with cpu.Percent's ticks standing in for
+	// interrupt counts. powermetrics' own "Interrupts" section never
+	// carries idle/user/system ticks, so the macOS backend can't fill
+	// this in the same way; PerCPUUsage (see handler_processor_usage.go)
+	// is the Mach-based equivalent there.
+	PerCPUUtilization map[string]float64 `json:"per_cpu_utilization"` // CPU identifier -> busy percent
 
 	// Power metrics
-	CPUPower    float64
-	GPUPower    float64
-	ANEPower    float64
-	DRAMPower   float64
-	SystemPower float64
+	CPUPower    float64 `json:"cpu_power_mw"`
+	GPUPower    float64 `json:"gpu_power_mw"`
+	ANEPower    float64 `json:"ane_power_mw"`
+	DRAMPower   float64 `json:"dram_power_mw"`
+	SystemPower float64 `json:"system_power_mw"`
+
+	// Smoothed (EWMA) counterparts of the power scalars and the IPI rate,
+	// updated once per finalized sample in WaitingForSampleHandler.Exit
+	// (see EWMAState.Update). Reduces the sample-to-sample jitter that
+	// makes the TUI noisy and threshold alerts flappy at 1-2s sample
+	// intervals. SmoothingAlpha/SmoothingWindow default to 0.3/30 (see
+	// NewMetricsState) and are overridable via --smoothing-alpha /
+	// --smoothing-window.
+	CPUPowerSmoothed    EWMAState `json:"cpu_power_smoothed"`
+	GPUPowerSmoothed    EWMAState `json:"gpu_power_smoothed"`
+	ANEPowerSmoothed    EWMAState `json:"ane_power_smoothed"`
+	DRAMPowerSmoothed   EWMAState `json:"dram_power_smoothed"`
+	SystemPowerSmoothed EWMAState `json:"system_power_smoothed"`
+	IPIRateSmoothed     EWMAState `json:"ipi_rate_smoothed"`
+	SmoothingAlpha      float64   `json:"-"`
+	SmoothingWindow     int       `json:"-"`
+
+	// ProcessCPUEWMA/CoalitionCPUEWMA persist each PID/coalition-ID's
+	// smoothed CPU% across samples, mirrored into ProcessInfo.CPUPercentEWMA
+	// / ProcessCoalition.CPUPercentEWMA. Keyed the same way as
+	// ProcessCPUMsTotal/CoalitionCPUMsTotal.
+	ProcessCPUEWMA   map[int]EWMAState `json:"-"`
+	CoalitionCPUEWMA map[int]EWMAState `json:"-"`
 
 	// CPU frequency
-	ECoreFreq  []int
-	PCoreFreq  []int
-	GPUFreq    int
-	AllCpuFreq map[int]int // Temporary storage for all CPU frequencies
-	MaxECores  int         // Maximum number of E-cores ever seen
-	MaxPCores  int         // Maximum number of P-cores ever seen
+	ECoreFreq  []int       `json:"e_core_freq_mhz"`
+	PCoreFreq  []int       `json:"p_core_freq_mhz"`
+	GPUFreq    int         `json:"gpu_freq_mhz"`
+	GPUActive  float64     `json:"gpu_active_pct"`
+	AllCpuFreq map[int]int `json:"-"` // Temporary storage for all CPU frequencies
+	MaxECores  int         `json:"-"` // Maximum number of E-cores ever seen
+	MaxPCores  int         `json:"-"` // Maximum number of P-cores ever seen
 
 	// CPU frequency history (per core)
-	ECoreFreqHistory map[int][]float64 // Core index -> frequency history
-	PCoreFreqHistory map[int][]float64 // Core index -> frequency history
+	ECoreFreqHistory map[int][]float64 `json:"-"` // Core index -> frequency history
+	PCoreFreqHistory map[int][]float64 `json:"-"` // Core index -> frequency history
+
+	// PerCoreActiveResidency is each absolute CPU index's most recent
+	// "active residency" percent (time spent executing rather than in an
+	// idle/down power state), parsed from powermetrics' per-CPU residency
+	// lines the same way AllCpuFreq collects per-CPU frequency.
+	// PerCoreResidencyHistory is its sparkline history, parallel to
+	// ECoreFreqHistory/PCoreFreqHistory.
+	PerCoreActiveResidency  map[int]float64   `json:"per_core_active_residency"`
+	PerCoreResidencyHistory map[int][]float64 `json:"-"`
+
+	// ClusterFreqHistogram buckets each cluster's per-CPU frequency samples
+	// into ClusterFreqHistogramBins roughly-equal-width bins (like
+	// intel_pstate_tracer's frequency histograms), keyed by cluster
+	// type ("E-Cluster", "P-Cluster"; multiple P clusters share the
+	// "P-Cluster" entry). Rebuilt wholesale each sample by
+	// ParsePowerMetricsOutput/parsePlistProcessor rather than accumulated,
+	// so it always reflects only the most recent sample's distribution.
+	ClusterFreqHistogram map[string][]int `json:"cluster_freq_histogram"`
+
+	// ClusterDownResidency is each cluster's most recent "down residency"
+	// percent (the cluster's shared power-gated idle state, distinct from
+	// an individual core's own idle residency).
+	ClusterDownResidency map[string]float64 `json:"cluster_down_residency"`
+
+	// CoreOccupancyHistory is a rolling per-core history of which coalition
+	// dominated that core's busy time each sample, keyed by absolute CPU
+	// index like PerCoreActiveResidency. Powermetrics doesn't report
+	// per-core process attribution directly; each sample is built by
+	// ParsePowerMetricsOutput/buildCoreOccupancy bin-packing coalitions
+	// (largest CPU-ms first) onto cores (highest active-residency first),
+	// so it's a plausible approximation of scheduler behavior rather than
+	// ground truth. Drives ui.ViewCoreMap.
+	CoreOccupancyHistory map[int][]CoreOccupancySample `json:"-"`
+
+	// PerCPUUsage holds the classical user/system/idle/nice percentages
+	// powermetrics itself doesn't report, keyed by absolute CPU index. It's
+	// populated by internal/mach's host_processor_info sampler on darwin
+	// builds with cgo enabled (a no-op elsewhere), independently of the
+	// powermetrics parser.
+	PerCPUUsage        map[int]CPUUsage   `json:"per_cpu_usage"`
+	PerCPUUsageHistory map[int][]CPUUsage `json:"-"` // CPU index -> usage history
 
 	// Network
-	NetworkIn  float64
-	NetworkOut float64
+	NetworkIn  float64 `json:"network_in_bytes"`
+	NetworkOut float64 `json:"network_out_bytes"`
 
 	// Disk I/O
-	DiskRead  float64
-	DiskWrite float64
+	DiskRead  float64 `json:"disk_read_bytes"`
+	DiskWrite float64 `json:"disk_write_bytes"`
+
+	// Cumulative counters and per-sample deltas, modeled on crunchstat's
+	// move from point-in-time "tx N rx N" stats to "tx N +dN rx N +dN":
+	// *Total accumulates every sample's NetworkIn/Out and DiskRead/Write
+	// since the parser started, *Delta is what the most recently finalized
+	// sample added to it. Computed in WaitingForSampleHandler.Exit.
+	NetworkInTotal  float64 `json:"network_in_total_mb"`
+	NetworkOutTotal float64 `json:"network_out_total_mb"`
+	DiskReadTotal   float64 `json:"disk_read_total_mb"`
+	DiskWriteTotal  float64 `json:"disk_write_total_mb"`
+	NetworkInDelta  float64 `json:"network_in_delta_mb"`
+	NetworkOutDelta float64 `json:"network_out_delta_mb"`
+	DiskReadDelta   float64 `json:"disk_read_delta_mb"`
+	DiskWriteDelta  float64 `json:"disk_write_delta_mb"`
+
+	// SampleIntervalSeconds is the duration the most recently finalized
+	// sample actually covered, preferring the "(N ms elapsed)" value
+	// powermetrics prints in its sample header over a wall-clock diff
+	// between samples. *TotalBytes integrates NetworkIn/Out and
+	// DiskRead/Write (MB/s rates) against this instead of assuming a flat
+	// 1s interval, so totals stay accurate at any --sample-rate.
+	SampleIntervalSeconds float64 `json:"sample_interval_seconds"`
+
+	NetworkInTotalBytes  float64 `json:"network_in_total_bytes"`
+	NetworkOutTotalBytes float64 `json:"network_out_total_bytes"`
+	DiskReadTotalBytes   float64 `json:"disk_read_total_bytes"`
+	DiskWriteTotalBytes  float64 `json:"disk_write_total_bytes"`
+
+	// ProcessCPUMsTotal/CoalitionCPUMsTotal accumulate CPU milliseconds per
+	// PID/coalition-ID since the parser started, mirrored into each
+	// ProcessInfo/ProcessCoalition's CPUMsTotal field. A PID's total resets
+	// to zero when it's reused by a new process, detected via
+	// RecentlyExited (see RunningTasksHandler.updateProcessTracking).
+	ProcessCPUMsTotal   map[int]float64 `json:"-"`
+	CoalitionCPUMsTotal map[int]float64 `json:"-"`
+
+	// CoalitionChildCPUMsTotal is the last-seen cumulative reaped-child CPU
+	// time (childcpu.ChildCPU.Total) per coalition ID, under
+	// --include-dead-children. RunningTasksHandler.updateDeadChildrenCPU
+	// diffs each sample's reading against this to get the delta added to
+	// CoalitionCPUHistory.
+	CoalitionChildCPUMsTotal map[int]float64 `json:"-"`
 
 	// Battery
-	BatteryCharge float64
-	BatteryState  string
+	BatteryCharge  float64 `json:"battery_charge_percent"`
+	BatteryState   string  `json:"battery_state"`
+	BacklightLevel int     `json:"backlight_level"`
+
+	// BatterySampleHistory is a ring buffer of {timestamp, charge, state,
+	// backlight} tuples, appended once per sample in
+	// parser.BatteryHandler.Exit, capped at MaxBatterySampleHistory (300 by
+	// default - about 5 minutes at the usual 1Hz sampling interval).
+	// Unlike History.BatteryHistory (a bare []float64 of charge alone,
+	// used for the existing sparkline), this keeps enough per-sample
+	// context for EstimateDischargeRate to fit a trend line over it.
+	BatterySampleHistory    []BatteryHistoryEntry `json:"battery_sample_history,omitempty"`
+	MaxBatterySampleHistory int                   `json:"-"`
 
 	// Thermal
-	ThermalPressure string
-	Temperature     map[string]float64
+	ThermalPressure string             `json:"thermal_pressure"`
+	Temperature     map[string]float64 `json:"temperature"`
 
 	// System memory
-	MemoryUsed      float64
-	MemoryAvailable float64
-	SwapUsed        float64
+	MemoryUsed      float64 `json:"memory_used_mb"`
+	MemoryAvailable float64 `json:"memory_available_mb"`
+	SwapUsed        float64 `json:"swap_used_mb"`
 
 	// Process tracking
-	Processes []ProcessInfo
-	Coalitions []ProcessCoalition
-	ProcessCPUHistory map[int][]float64 // PID -> CPU history
-	ProcessMemHistory map[int][]float64 // PID -> Memory history
-	CoalitionCPUHistory map[int][]float64 // Coalition ID -> CPU history
-	CoalitionMemHistory map[int][]float64 // Coalition ID -> Memory history
+	Processes           []ProcessInfo      `json:"processes"`
+	Coalitions          []ProcessCoalition `json:"coalitions"`
+	ProcessCPUHistory   map[int][]float64  `json:"-"` // PID -> CPU history
+	ProcessMemHistory   map[int][]float64  `json:"-"` // PID -> Memory history
+	CoalitionCPUHistory map[int][]float64  `json:"-"` // Coalition ID -> CPU history
+	CoalitionMemHistory map[int][]float64  `json:"-"` // Coalition ID -> Memory history
 
 	// Recently exited processes tracking
-	RecentlyExited []ExitedProcessInfo
-	LastSeenPIDs   map[int]time.Time  // Track when each PID was last seen
-	ProcessNames   map[int]string     // Track process names by PID (NOT coalition IDs)
-	CoalitionNames map[int]string     // Track coalition names by Coalition ID (separate from PIDs)
+	RecentlyExited []ExitedProcessInfo `json:"recently_exited"`
+	LastSeenPIDs   map[int]time.Time   `json:"-"` // Track when each PID was last seen
+	ProcessNames   map[int]string      `json:"-"` // Track process names by PID (NOT coalition IDs)
+	CoalitionNames map[int]string      `json:"-"` // Track coalition names by Coalition ID (separate from PIDs)
+
+	// PinnedPIDs holds the PIDs currently matched by a watchlist entry (see
+	// internal/watchlist), refreshed once per sample. Consulted by
+	// HistorySamples so pinned processes keep a longer history buffer.
+	PinnedPIDs map[int]bool `json:"-"`
 
 	// Historical data (circular buffers, 120 samples)
-	History      *HistoricalData
-	LastUpdate   time.Time
-	UpdateErrors int
+	History      *HistoricalData `json:"-"`
+	LastUpdate   time.Time       `json:"last_update"`
+	UpdateErrors int             `json:"update_errors"`
+
+	// DisabledSubsystems, PerCPUHistoryLen, CPUFilterECores/CPUFilterPCores
+	// come from the optional --config file (see internal/config) and are
+	// set once at startup. DisabledSubsystems skips the corresponding
+	// parser branch and history growth entirely; PerCPUHistoryLen overrides
+	// the per-CPU/per-core history caps that aren't part of History.
+	// CPUFilterECores/CPUFilterPCores default to true and let
+	// organizeCPUFrequencies hide a core type's pane on chips that don't
+	// have it, or that the user just doesn't care about.
+	DisabledSubsystems map[string]bool `json:"-"`
+	PerCPUHistoryLen   int             `json:"-"`
+	CPUFilterECores    bool            `json:"-"`
+	CPUFilterPCores    bool            `json:"-"`
+
+	// ExcludedMetrics holds the --config exclude_metrics names, finer
+	// grained than DisabledSubsystems: "ane_power" drops just that one
+	// field rather than the whole ANE subsystem. See parser.go's
+	// ParsePowerMetricsOutput for where this is consulted before each
+	// regex match.
+	ExcludedMetrics map[string]bool `json:"-"`
+}
+
+// SubsystemDisabled reports whether the named subsystem (see the
+// config.Subsystem* constants) was excluded via --config.
+func (s *MetricsState) SubsystemDisabled(name string) bool {
+	return s.DisabledSubsystems[name]
 }
 
-// ProcessCoalition represents a process coalition (parent process group)
+// MetricExcluded reports whether the named metric (e.g. "ane_power",
+// "per_cpu_timers") was listed in --config's exclude_metrics.
+func (s *MetricsState) MetricExcluded(name string) bool {
+	return s.ExcludedMetrics[name]
+}
+
+// PerCPUHistoryCap returns the configured per-CPU/per-core history length,
+// falling back to builtinDefault when --config didn't set history_len.
+func (s *MetricsState) PerCPUHistoryCap(builtinDefault int) int {
+	if s.PerCPUHistoryLen > 0 {
+		return s.PerCPUHistoryLen
+	}
+	return builtinDefault
+}
+
+// ProcessCoalition represents a process coalition (parent process group).
+// On Linux, coalitions mirror the cgroup v2 hierarchy (ParentID chains up
+// to a root slice); on macOS, where powermetrics reports coalitions flat,
+// every coalition's ParentID is RootCoalitionID.
 type ProcessCoalition struct {
-	CoalitionID   int
-	Name          string
-	CPUPercent    float64
-	MemoryMB      float64
-	DiskMB        float64
-	NetworkMB     float64
-	Subprocesses  []ProcessInfo
-	CPUHistory    []float64 // Last 10 samples for sparkline
-	MemoryHistory []float64 // Last 10 samples for sparkline
+	CoalitionID   int           `json:"coalition_id"`
+	ParentID      int           `json:"parent_id"`
+	Name          string        `json:"name"`
+	CPUPercent    float64       `json:"cpu_percent"`
+	MemoryMB      float64       `json:"memory_mb"`
+	DiskMB        float64       `json:"disk_mb"`
+	NetworkMB     float64       `json:"network_mb"`
+	Subprocesses  []ProcessInfo `json:"subprocesses"`
+	CPUHistory    []float64     `json:"-"` // Last 10 samples for sparkline
+	MemoryHistory []float64     `json:"-"` // Last 10 samples for sparkline
+
+	// ExitedChildrenCPU/MemoryMB accumulate the last-observed CPU/memory of
+	// child coalitions that have since disappeared (a short-lived
+	// container or launchd job that spawned and exited between samples),
+	// similar to how crunchstat folds reaped children into their parent's
+	// cgroup accounting. Without this, a coalition that spawns many
+	// short-lived children flickers to ~0 between samples instead of
+	// showing accurate cumulative resource use.
+	ExitedChildrenCPU      float64 `json:"exited_children_cpu_percent"`
+	ExitedChildrenMemoryMB float64 `json:"exited_children_memory_mb"`
+
+	// CPUMsTotal accumulates this coalition's CPU milliseconds since the
+	// parser started, mirrored from MetricsState.CoalitionCPUMsTotal by
+	// WaitingForSampleHandler.Exit.
+	CPUMsTotal float64 `json:"cpu_ms_total"`
+
+	// CPUPercentEWMA smooths CPUPercent across samples, mirrored from
+	// MetricsState.CoalitionCPUEWMA. See EWMAState for the smoothing math.
+	CPUPercentEWMA float64 `json:"cpu_percent_ewma"`
+
+	// SubprocessCPUMsSum/PowerSum/WakeupsSum are the sums of this
+	// coalition's direct Subprocesses' CPU milliseconds, estimated power
+	// (EnergyEstimate), and wakeups for the current sample, computed
+	// alongside CPUMsTotal. They let the tree view show each subprocess's
+	// share of its parent's total cost.
+	SubprocessCPUMsSum   float64 `json:"subprocess_cpu_ms_sum"`
+	SubprocessPowerSum   float64 `json:"subprocess_power_sum"`
+	SubprocessWakeupsSum float64 `json:"subprocess_wakeups_sum"`
+
+	// MemoryPSSMB/MemoryUSSMB are this coalition's proportional/unique set
+	// size, summed from memstats.Rollup across Subprocesses' PIDs. Unlike
+	// MemoryMB (powermetrics' userPercent, which double-counts shared
+	// library pages once per subprocess), these divide shared pages
+	// proportionally across their mappers. Zero when memstats accounting
+	// is unsupported on this build or hasn't run yet.
+	MemoryPSSMB float64 `json:"memory_pss_mb"`
+	MemoryUSSMB float64 `json:"memory_uss_mb"`
+}
+
+// CoalitionSortBy selects the metric ProcessCoalition.SortSubprocessesBy
+// ranks subprocesses by.
+type CoalitionSortBy int
+
+const (
+	SortByCPU CoalitionSortBy = iota
+	SortByPower
+	SortByWakeups
+)
+
+// SortSubprocessesBy sorts c.Subprocesses in place, descending by by, so the
+// tree view can show the subprocess driving the parent's cost first.
+func (c *ProcessCoalition) SortSubprocessesBy(by CoalitionSortBy) {
+	sort.Slice(c.Subprocesses, func(i, j int) bool {
+		switch by {
+		case SortByPower:
+			return c.Subprocesses[i].EnergyEstimate > c.Subprocesses[j].EnergyEstimate
+		case SortByWakeups:
+			return c.Subprocesses[i].Wakeups > c.Subprocesses[j].Wakeups
+		default:
+			return c.Subprocesses[i].CPUPercent > c.Subprocesses[j].CPUPercent
+		}
+	})
+}
+
+// SubprocessRow is one rendered row of a coalition's subprocess list: the
+// process itself, the box-drawing connector to print before its name
+// (SubprocessConnector picks "├─ " vs "└─ "), and its share of the parent
+// coalition's CPU, for a percentage-of-parent bar.
+type SubprocessRow struct {
+	Process         ProcessInfo
+	Last            bool
+	PercentOfParent float64
+}
+
+// SubprocessRows returns c.Subprocesses sorted by by and ready to render,
+// skipping 0%-CPU subprocesses unless includeZeroCPU is true.
+func (c *ProcessCoalition) SubprocessRows(by CoalitionSortBy, includeZeroCPU bool) []SubprocessRow {
+	c.SortSubprocessesBy(by)
+
+	rows := make([]SubprocessRow, 0, len(c.Subprocesses))
+	for _, p := range c.Subprocesses {
+		if !includeZeroCPU && p.CPUPercent <= 0 {
+			continue
+		}
+		var pct float64
+		if c.CPUPercent > 0 {
+			pct = (p.CPUPercent / c.CPUPercent) * 100
+		}
+		rows = append(rows, SubprocessRow{Process: p, PercentOfParent: pct})
+	}
+	if len(rows) > 0 {
+		rows[len(rows)-1].Last = true
+	}
+	return rows
+}
+
+// SubprocessConnector returns the box-drawing prefix for a subprocess row:
+// "└─ " for the last row under a coalition, "├─ " for every other.
+func (r SubprocessRow) SubprocessConnector() string {
+	if r.Last {
+		return "└─ "
+	}
+	return "├─ "
+}
+
+// RootCoalitionID is the ParentID of a coalition with no parent of its own
+// (a top-level cgroup slice on Linux, or any coalition on macOS).
+const RootCoalitionID = 0
+
+// CoreOccupancySample is one core's entry in CoreOccupancyHistory for a
+// single sample: which coalition's work was (heuristically) packed onto
+// this core, how much of the core's busy time that coalition accounted
+// for, and the core's frequency at the time.
+type CoreOccupancySample struct {
+	CoalitionID   int     `json:"coalition_id"`
+	CoalitionName string  `json:"coalition_name"`
+	Ms            float64 `json:"ms"`
+	TotalMs       float64 `json:"total_ms"`
+	FreqMHz       int     `json:"freq_mhz"`
+}
+
+// RollupCPU returns coalitionID's own CPUPercent plus the recursive sum of
+// every descendant coalition's CPUPercent and ExitedChildrenCPU, mirroring
+// how a cgroup's usage figure includes its whole subtree.
+func (s *MetricsState) RollupCPU(coalitionID int) float64 {
+	return s.rollup(coalitionID, func(c *ProcessCoalition) float64 { return c.CPUPercent + c.ExitedChildrenCPU })
+}
+
+// RollupMemory is RollupCPU's memory counterpart.
+func (s *MetricsState) RollupMemory(coalitionID int) float64 {
+	return s.rollup(coalitionID, func(c *ProcessCoalition) float64 { return c.MemoryMB + c.ExitedChildrenMemoryMB })
+}
+
+// rollup sums value(c) over coalitionID and every coalition descending from
+// it via ParentID, however deep the tree goes.
+func (s *MetricsState) rollup(coalitionID int, value func(*ProcessCoalition) float64) float64 {
+	childrenOf := make(map[int][]*ProcessCoalition, len(s.Coalitions))
+	byID := make(map[int]*ProcessCoalition, len(s.Coalitions))
+	for i := range s.Coalitions {
+		c := &s.Coalitions[i]
+		byID[c.CoalitionID] = c
+		childrenOf[c.ParentID] = append(childrenOf[c.ParentID], c)
+	}
+
+	root, ok := byID[coalitionID]
+	if !ok {
+		return 0
+	}
+
+	var total float64
+	var walk func(c *ProcessCoalition)
+	walk = func(c *ProcessCoalition) {
+		total += value(c)
+		for _, child := range childrenOf[c.CoalitionID] {
+			walk(child)
+		}
+	}
+	walk(root)
+	return total
 }
 
 // ProcessInfo represents a single process (subprocess within a coalition)
 type ProcessInfo struct {
-	PID           int
-	Name          string
-	CoalitionName string    // Name of parent coalition
-	CPUPercent    float64
-	MemoryMB      float64
-	DiskMB        float64
-	NetworkMB     float64
-	CPUHistory    []float64 // Last 10 samples for sparkline
-	MemoryHistory []float64 // Last 10 samples for sparkline
+	PID           int       `json:"pid"`
+	Name          string    `json:"name"`
+	CoalitionName string    `json:"coalition_name"` // Name of parent coalition
+	CPUPercent    float64   `json:"cpu_percent"`
+	MemoryMB      float64   `json:"memory_mb"`
+	DiskMB        float64   `json:"disk_mb"`
+	NetworkMB     float64   `json:"network_mb"`
+	CPUHistory    []float64 `json:"-"` // Last 10 samples for sparkline (600 if Pinned, see HistorySamples)
+	MemoryHistory []float64 `json:"-"` // Last 10 samples for sparkline (600 if Pinned, see HistorySamples)
+
+	// Populated from powermetrics task fields by the plist backend
+	// (Wakeups, GPUMS, EnergyEstimate); the text backend does not parse
+	// them and nothing fills in ANEMS, so those stay zero. Kept here so internal/proctrack has a
+	// stable per-process shape to roll up regardless of backend.
+	Wakeups        float64 `json:"wakeups"`
+	GPUMS          float64 `json:"gpu_ms"`
+	ANEMS          float64 `json:"ane_ms"`
+	EnergyEstimate float64 `json:"energy_estimate"`
+
+	// CPUMsTotal accumulates this process' CPU milliseconds since the
+	// parser started, mirrored from MetricsState.ProcessCPUMsTotal by
+	// WaitingForSampleHandler.Exit. Resets to zero if PID is reused by a
+	// different process (see RunningTasksHandler.updateProcessTracking).
+	CPUMsTotal float64 `json:"cpu_ms_total"`
+
+	// CPUPercentEWMA smooths CPUPercent across samples, mirrored from
+	// MetricsState.ProcessCPUEWMA. See EWMAState for the smoothing math.
+	CPUPercentEWMA float64 `json:"cpu_percent_ewma"`
+
+	// MemoryInfoEx breaks MemoryMB down further. Populated from
+	// /proc/[pid]/statm+smaps_rollup on Linux and by shelling to ps/vm_stat
+	// on macOS, since powermetrics' task section doesn't report it.
+	MemoryInfoEx
+
+	// Pinned is true when this process matched a watchlist entry (see
+	// internal/watchlist). Pinned processes are kept in the Top pane
+	// regardless of CPU rank and get a much longer history buffer.
+	Pinned bool `json:"pinned"`
+
+	// PPID, UID, Command, and State come from a batched `ps` enrichment
+	// pass (see internal/parser.ProcessTree) rather than powermetrics
+	// itself, which reports no parent/owner/command-line for a task. Zero/
+	// empty until that enrichment has run for this PID.
+	PPID    int    `json:"ppid,omitempty"`
+	UID     int    `json:"uid,omitempty"`
+	Command string `json:"command,omitempty"`
+	State   string `json:"state,omitempty"`
+
+	// NumThreads, NumFDs, Cmdline, User, and StartTime come from
+	// internal/procenrich's batched `ps`/libproc enrichment pass, the same
+	// one that fills in MemoryMB/MemoryInfoEx.VMS above. Zero/empty until
+	// that pass has run for this PID, or always zero/empty under
+	// --no-enrich.
+	NumThreads int       `json:"num_threads,omitempty"`
+	NumFDs     int       `json:"num_fds,omitempty"`
+	Cmdline    string    `json:"cmdline,omitempty"`
+	User       string    `json:"user,omitempty"`
+	StartTime  time.Time `json:"start_time,omitempty"`
+}
+
+// MemoryInfoEx is the per-process memory breakdown beyond the single
+// MemoryMB figure powermetrics/gopsutil give by default, all in MB.
+type MemoryInfoEx struct {
+	RSS    float64 `json:"rss_mb"`    // resident set size
+	VMS    float64 `json:"vms_mb"`    // total virtual address space
+	Shared float64 `json:"shared_mb"` // pages shared with other processes
+	Text   float64 `json:"text_mb"`   // executable code
+	Data   float64 `json:"data_mb"`   // data + stack
+	Dirty  float64 `json:"dirty_mb"`  // dirty pages, from smaps_rollup
+	Swap   float64 `json:"swap_mb"`   // swapped-out pages, from smaps_rollup
+}
+
+// History sample-buffer sizes for ProcessInfo.CPUHistory/MemoryHistory.
+// Watchlisted (Pinned) processes get a much longer buffer so a
+// memory-leak curve can be inspected retroactively instead of only over
+// the last 10 samples.
+const (
+	DefaultHistorySamples   = 10
+	WatchlistHistorySamples = 600
+)
+
+// ClusterFreqHistogramBins is the bucket count for ClusterFreqHistogram.
+const ClusterFreqHistogramBins = 8
+
+// HistorySamples returns the history buffer length to use for pid, taking
+// into account the watchlist pinning tracked on the state.
+func (s *MetricsState) HistorySamples(pid int) int {
+	if s.PinnedPIDs != nil && s.PinnedPIDs[pid] {
+		return WatchlistHistorySamples
+	}
+	return DefaultHistorySamples
 }
 
 // ExitedProcessInfo represents a process that recently exited
 type ExitedProcessInfo struct {
-	Name         string
-	PIDs         []int     // List of all PIDs that exited for this process name
-	Occurrences  int       // Number of times this process has appeared and exited
-	LastExitTime time.Time // When the process last exited
-	FirstSeenTime time.Time // When we first saw this process name
+	Name          string    `json:"name"`
+	PIDs          []int     `json:"pids"`            // List of all PIDs that exited for this process name
+	Occurrences   int       `json:"occurrences"`     // Number of times this process has appeared and exited
+	LastExitTime  time.Time `json:"last_exit_time"`  // When the process last exited
+	FirstSeenTime time.Time `json:"first_seen_time"` // When we first saw this process name
 }
 
 // HistoricalData stores time series data
 type HistoricalData struct {
-	IPIHistory        []int
-	TimerHistory      []int
-	TotalHistory      []int
-	CPUPowerHistory   []float64
-	GPUPowerHistory   []float64
-	SystemHistory     []float64
-	NetworkInHistory  []float64
-	NetworkOutHistory []float64
-	DiskReadHistory   []float64
-	DiskWriteHistory  []float64
-	BatteryHistory    []float64
-	TempHistory       []float64
-	MemoryHistory     []float64
-	MaxHistory        int
+	IPIHistory        []int     `json:"ipi_history"`
+	TimerHistory      []int     `json:"timer_history"`
+	TotalHistory      []int     `json:"total_history"`
+	CPUPowerHistory   []float64 `json:"cpu_power_history"`
+	GPUPowerHistory   []float64 `json:"gpu_power_history"`
+	SystemHistory     []float64 `json:"system_history"`
+	NetworkInHistory  []float64 `json:"network_in_history"`
+	NetworkOutHistory []float64 `json:"network_out_history"`
+	DiskReadHistory   []float64 `json:"disk_read_history"`
+	DiskWriteHistory  []float64 `json:"disk_write_history"`
+	BatteryHistory    []float64 `json:"battery_history"`
+	TempHistory       []float64 `json:"temp_history"`
+	MemoryHistory     []float64 `json:"memory_history"`
+	MaxHistory        int       `json:"-"`
 }
 
 // NewMetricsState creates a new MetricsState with initialized history
 func NewMetricsState() *MetricsState {
 	return &MetricsState{
-		Temperature: make(map[string]float64),
-		ProcessCPUHistory: make(map[int][]float64),
-		ProcessMemHistory: make(map[int][]float64),
-		Coalitions: make([]ProcessCoalition, 0),
-		CoalitionCPUHistory: make(map[int][]float64),
-		CoalitionMemHistory: make(map[int][]float64),
-		ECoreFreqHistory: make(map[int][]float64),
-		PCoreFreqHistory: make(map[int][]float64),
-		PerCPUInterrupts: make(map[string]float64),
-		PerCPUIPIs: make(map[string]float64),
-		PerCPUTimers: make(map[string]float64),
-		AllSeenCPUs: make(map[string]bool),
-		PerCPUInterruptHistory: make(map[string][]float64),
-		RecentlyExited: make([]ExitedProcessInfo, 0),
-		LastSeenPIDs: make(map[int]time.Time),
-		ProcessNames: make(map[int]string),
-		CoalitionNames: make(map[int]string),
+		Temperature:              make(map[string]float64),
+		ProcessCPUHistory:        make(map[int][]float64),
+		ProcessMemHistory:        make(map[int][]float64),
+		Coalitions:               make([]ProcessCoalition, 0),
+		CoalitionCPUHistory:      make(map[int][]float64),
+		CoalitionMemHistory:      make(map[int][]float64),
+		ECoreFreqHistory:         make(map[int][]float64),
+		PCoreFreqHistory:         make(map[int][]float64),
+		PerCPUUsage:              make(map[int]CPUUsage),
+		PerCPUUsageHistory:       make(map[int][]CPUUsage),
+		PerCPUInterrupts:         make(map[string]float64),
+		PerCPUIPIs:               make(map[string]float64),
+		PerCPUTimers:             make(map[string]float64),
+		AllSeenCPUs:              make(map[string]bool),
+		PerCPUInterruptHistory:   make(map[string][]float64),
+		PerCPUInterruptsLastSeen: make(map[string]time.Time),
+		PerCPUUtilization:        make(map[string]float64),
+		ExcludedMetrics:          make(map[string]bool),
+		PerCoreActiveResidency:   make(map[int]float64),
+		PerCoreResidencyHistory:  make(map[int][]float64),
+		ClusterFreqHistogram:     make(map[string][]int),
+		ClusterDownResidency:     make(map[string]float64),
+		CoreOccupancyHistory:     make(map[int][]CoreOccupancySample),
+		RecentlyExited:           make([]ExitedProcessInfo, 0),
+		LastSeenPIDs:             make(map[int]time.Time),
+		ProcessNames:             make(map[int]string),
+		CoalitionNames:           make(map[int]string),
+		PinnedPIDs:               make(map[int]bool),
+		ProcessCPUMsTotal:        make(map[int]float64),
+		CoalitionCPUMsTotal:      make(map[int]float64),
+		CoalitionChildCPUMsTotal: make(map[int]float64),
+		ProcessCPUEWMA:           make(map[int]EWMAState),
+		CoalitionCPUEWMA:         make(map[int]EWMAState),
+		SmoothingAlpha:           DefaultSmoothingAlpha,
+		SmoothingWindow:          DefaultSmoothingWindow,
+		DisabledSubsystems:       make(map[string]bool),
+		CPUFilterECores:          true,
+		CPUFilterPCores:          true,
+		BatterySampleHistory:     make([]BatteryHistoryEntry, 0, DefaultBatteryHistoryLen),
+		MaxBatterySampleHistory:  DefaultBatteryHistoryLen,
 		History: &HistoricalData{
 			IPIHistory:        make([]int, 0, 120),
 			TimerHistory:      make([]int, 0, 120),
@@ -175,6 +627,100 @@ func NewMetricsState() *MetricsState {
 	}
 }
 
+// DefaultBatteryHistoryLen is MaxBatterySampleHistory's default: about 5
+// minutes of samples at the usual 1Hz --interval.
+const DefaultBatteryHistoryLen = 300
+
+// BatteryHistoryEntry is one BatterySampleHistory tuple: everything
+// BatteryHandler parses from a sample's Battery/backlight section, plus
+// the time it was taken, so EstimateDischargeRate has both axes of the
+// trend line it fits.
+type BatteryHistoryEntry struct {
+	Timestamp time.Time
+	Charge    float64
+	State     string
+	Backlight int
+}
+
+// AddBatteryHistory appends entry to history, trimming the oldest entry
+// once it exceeds max - BatterySampleHistory's equivalent of AddToHistory.
+func AddBatteryHistory(history []BatteryHistoryEntry, entry BatteryHistoryEntry, max int) []BatteryHistoryEntry {
+	history = append(history, entry)
+	if len(history) > max {
+		history = history[1:]
+	}
+	return history
+}
+
+// GetBatteryHistory returns a copy of s.BatterySampleHistory, for the
+// Battery view's sparkline/readout and the Combined view alike.
+func (s *MetricsState) GetBatteryHistory() []BatteryHistoryEntry {
+	s.Mu.RLock()
+	defer s.Mu.RUnlock()
+	history := make([]BatteryHistoryEntry, len(s.BatterySampleHistory))
+	copy(history, s.BatterySampleHistory)
+	return history
+}
+
+// EstimateDischargeRate fits a least-squares line through history's
+// (Timestamp, Charge) pairs and returns its slope in percent per hour -
+// negative while discharging, positive while charging - plus false if
+// history has fewer than two entries (nothing to fit a slope through) or
+// spans no measurable time.
+func EstimateDischargeRate(history []BatteryHistoryEntry) (ratePerHour float64, ok bool) {
+	if len(history) < 2 {
+		return 0, false
+	}
+
+	t0 := history[0].Timestamp
+	var sumX, sumY, sumXY, sumXX float64
+	n := float64(len(history))
+	for _, e := range history {
+		x := e.Timestamp.Sub(t0).Hours()
+		y := e.Charge
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denom := n*sumXX - sumX*sumX
+	if denom == 0 {
+		return 0, false
+	}
+	return (n*sumXY - sumX*sumY) / denom, true
+}
+
+// BatteryETA turns a discharge-rate estimate and the current charge into
+// a human-readable time-to-empty (rate < 0) or time-to-full (rate > 0)
+// string, or "" when the rate is ~0 (plugged in and topped off, or not
+// enough history yet).
+func BatteryETA(currentCharge, ratePerHour float64) string {
+	const epsilon = 0.05 // %/hour; anything slower looks like "no change" at minute resolution
+	switch {
+	case ratePerHour < -epsilon:
+		hours := currentCharge / -ratePerHour
+		return fmt.Sprintf("%s until empty", formatHours(hours))
+	case ratePerHour > epsilon:
+		hours := (100 - currentCharge) / ratePerHour
+		return fmt.Sprintf("%s until full", formatHours(hours))
+	default:
+		return ""
+	}
+}
+
+// formatHours renders a fractional hour count as "XhYm", matching the
+// compact duration strings elsewhere in the TUI (e.g. windowLabel's "5m").
+func formatHours(hours float64) string {
+	total := time.Duration(hours * float64(time.Hour))
+	h := total / time.Hour
+	m := (total % time.Hour) / time.Minute
+	if h > 0 {
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+	return fmt.Sprintf("%dm", m)
+}
+
 // AddToHistory adds a value to a historical data slice
 func AddToHistory(history []float64, value float64, max int) []float64 {
 	history = append(history, value)
@@ -191,4 +737,92 @@ func AddToIntHistory(history []int, value int, max int) []int {
 		history = history[1:]
 	}
 	return history
-}
\ No newline at end of file
+}
+
+// CPUUsage is one absolute CPU index's classical utilization breakdown, as
+// reported by internal/mach's host_processor_info sampler: the percentage
+// of the interval spent in each of the four Mach processor-load states.
+type CPUUsage struct {
+	User   float64 `json:"user"`
+	System float64 `json:"system"`
+	Idle   float64 `json:"idle"`
+	Nice   float64 `json:"nice"`
+}
+
+// DefaultSmoothingAlpha/DefaultSmoothingWindow are EWMAState.Update's
+// defaults: alpha ~0.3 gives roughly a 2-sample half-life, and a 30-sample
+// window covers the last ~30-60s of samples for windowed min/max/avg/p95.
+const (
+	DefaultSmoothingAlpha  = 0.3
+	DefaultSmoothingWindow = 30
+)
+
+// EWMAState is an exponentially-weighted moving average for one scalar
+// metric, plus the fixed-size window of raw samples it was computed from
+// (for windowed Min/Max/Avg/P95). Zero value is a valid, unset EWMAState.
+type EWMAState struct {
+	Value       float64   `json:"value"`
+	Initialized bool      `json:"-"`
+	Window      []float64 `json:"-"`
+}
+
+// Update folds sample into e's EWMA (ewma = alpha*sample + (1-alpha)*ewma,
+// seeded with the first sample) and appends it to e's windowed history,
+// capped at windowSize.
+func (e *EWMAState) Update(sample, alpha float64, windowSize int) {
+	if !e.Initialized {
+		e.Value = sample
+		e.Initialized = true
+	} else {
+		e.Value = alpha*sample + (1-alpha)*e.Value
+	}
+	e.Window = AddToHistory(e.Window, sample, windowSize)
+}
+
+// Min returns the smallest raw sample in e's window, or 0 if empty.
+func (e EWMAState) Min() float64 {
+	return windowStat(e.Window, func(a, b float64) bool { return a < b })
+}
+
+// Max returns the largest raw sample in e's window, or 0 if empty.
+func (e EWMAState) Max() float64 {
+	return windowStat(e.Window, func(a, b float64) bool { return a > b })
+}
+
+// Avg returns the mean of e's window, or 0 if empty.
+func (e EWMAState) Avg() float64 {
+	if len(e.Window) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range e.Window {
+		sum += v
+	}
+	return sum / float64(len(e.Window))
+}
+
+// P95 returns the 95th-percentile sample in e's window, or 0 if empty.
+func (e EWMAState) P95() float64 {
+	if len(e.Window) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), e.Window...)
+	sort.Float64s(sorted)
+	idx := int(float64(len(sorted)-1) * 0.95)
+	return sorted[idx]
+}
+
+// windowStat returns the element of window that "wins" every pairwise
+// comparison with better, or 0 if window is empty.
+func windowStat(window []float64, better func(a, b float64) bool) float64 {
+	if len(window) == 0 {
+		return 0
+	}
+	best := window[0]
+	for _, v := range window[1:] {
+		if better(v, best) {
+			best = v
+		}
+	}
+	return best
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateDischargeRateNeedsTwoEntries(t *testing.T) {
+	if _, ok := EstimateDischargeRate(nil); ok {
+		t.Error("expected no history to report ok=false")
+	}
+	if _, ok := EstimateDischargeRate([]BatteryHistoryEntry{{Timestamp: time.Unix(0, 0), Charge: 80}}); ok {
+		t.Error("expected a single entry to report ok=false")
+	}
+}
+
+func TestEstimateDischargeRateZeroDuration(t *testing.T) {
+	now := time.Unix(1000, 0)
+	history := []BatteryHistoryEntry{
+		{Timestamp: now, Charge: 80},
+		{Timestamp: now, Charge: 79},
+	}
+	if _, ok := EstimateDischargeRate(history); ok {
+		t.Error("expected samples with no elapsed time to report ok=false")
+	}
+}
+
+func TestEstimateDischargeRateCleanDischarge(t *testing.T) {
+	start := time.Unix(0, 0)
+	history := []BatteryHistoryEntry{
+		{Timestamp: start, Charge: 100},
+		{Timestamp: start.Add(time.Hour), Charge: 90},
+		{Timestamp: start.Add(2 * time.Hour), Charge: 80},
+	}
+	rate, ok := EstimateDischargeRate(history)
+	if !ok {
+		t.Fatal("expected ok=true for a clean discharge slope")
+	}
+	if rate != -10 {
+		t.Errorf("expected a -10%%/hour slope, got %v", rate)
+	}
+}
+
+func TestEstimateDischargeRateCharging(t *testing.T) {
+	start := time.Unix(0, 0)
+	history := []BatteryHistoryEntry{
+		{Timestamp: start, Charge: 50},
+		{Timestamp: start.Add(time.Hour), Charge: 70},
+	}
+	rate, ok := EstimateDischargeRate(history)
+	if !ok {
+		t.Fatal("expected ok=true for a charging slope")
+	}
+	if rate != 20 {
+		t.Errorf("expected a 20%%/hour slope, got %v", rate)
+	}
+}
+
+func TestBatteryETADischargingAndCharging(t *testing.T) {
+	if got, want := BatteryETA(50, -25), "2h0m until empty"; got != want {
+		t.Errorf("BatteryETA(50, -25) = %q, want %q", got, want)
+	}
+	if got, want := BatteryETA(50, 25), "2h0m until full"; got != want {
+		t.Errorf("BatteryETA(50, 25) = %q, want %q", got, want)
+	}
+}
+
+func TestBatteryETAFlatWithinEpsilon(t *testing.T) {
+	if got := BatteryETA(80, 0); got != "" {
+		t.Errorf("expected no ETA at rate 0, got %q", got)
+	}
+	if got := BatteryETA(80, 0.04); got != "" {
+		t.Errorf("expected a rate inside epsilon to report no trend, got %q", got)
+	}
+}
+
+func TestFormatHoursRounding(t *testing.T) {
+	if got, want := formatHours(0.5), "30m"; got != want {
+		t.Errorf("formatHours(0.5) = %q, want %q", got, want)
+	}
+	if got, want := formatHours(1.5), "1h30m"; got != want {
+		t.Errorf("formatHours(1.5) = %q, want %q", got, want)
+	}
+	if got, want := formatHours(0), "0m"; got != want {
+		t.Errorf("formatHours(0) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"sync"
+
+	"powermetrics-tui/internal/childcpu"
+)
+
+// activeChildCPUReader is the childcpu.Reader
+// RunningTasksHandler.updateDeadChildrenCPU consults for each coalition
+// leader's reaped-child CPU time. Defaults to childcpu.NewReader()'s
+// platform-appropriate implementation; guarded like activePIDFinder for the
+// same reason (see pidfinder.go).
+var (
+	childCPUReaderMu     sync.RWMutex
+	activeChildCPUReader = childcpu.NewReader()
+)
+
+// SetChildCPUReader makes r the active childcpu.Reader.
+func SetChildCPUReader(r childcpu.Reader) {
+	childCPUReaderMu.Lock()
+	defer childCPUReaderMu.Unlock()
+	activeChildCPUReader = r
+}
+
+func childCPUReader() childcpu.Reader {
+	childCPUReaderMu.RLock()
+	defer childCPUReaderMu.RUnlock()
+	return activeChildCPUReader
+}
@@ -0,0 +1,37 @@
+package parser
+
+import (
+	"sync"
+	"time"
+
+	"powermetrics-tui/internal/procenrich"
+)
+
+// enrichTTL bounds how long a cached procenrich.Info is reused before the
+// next sample re-queries `ps` for that PID - long enough that a steady
+// stream of samples only forks `ps` for a given PID a few times a minute,
+// short enough that RSS/thread count don't go stale for long.
+const enrichTTL = 5 * time.Second
+
+// activeEnricher is the procenrich.Enricher updateProcessEnrichment
+// consults to fill in each process' MemoryMB/VMS/NumThreads/NumFDs/
+// Cmdline/User/StartTime. Defaults to a TTL-cached PSEnricher; guarded
+// like activePIDFinder for the same reason (see pidfinder.go).
+var (
+	enricherMu     sync.RWMutex
+	activeEnricher procenrich.Enricher = procenrich.NewCachingEnricher(procenrich.NewPSEnricher(), enrichTTL)
+)
+
+// SetEnricher makes e the active procenrich.Enricher; pass
+// procenrich.NoopEnricher{} for --no-enrich.
+func SetEnricher(e procenrich.Enricher) {
+	enricherMu.Lock()
+	defer enricherMu.Unlock()
+	activeEnricher = e
+}
+
+func enricher() procenrich.Enricher {
+	enricherMu.RLock()
+	defer enricherMu.RUnlock()
+	return activeEnricher
+}
@@ -1,5 +1,11 @@
 package parser
 
+import (
+	"time"
+
+	"powermetrics-tui/internal/models"
+)
+
 // BatteryHandler handles the Battery and backlight usage section
 type BatteryHandler struct{}
 
@@ -39,5 +45,18 @@ func (h *BatteryHandler) ProcessLine(ctx *ParserContext, line string) ParserStat
 }
 
 func (h *BatteryHandler) Exit(ctx *ParserContext) {
-	// Nothing special needed
-}
\ No newline at end of file
+	max := ctx.MetricsState.MaxBatterySampleHistory
+	if max <= 0 {
+		max = models.DefaultBatteryHistoryLen
+	}
+	ctx.MetricsState.BatterySampleHistory = models.AddBatteryHistory(
+		ctx.MetricsState.BatterySampleHistory,
+		models.BatteryHistoryEntry{
+			Timestamp: time.Now(),
+			Charge:    ctx.MetricsState.BatteryCharge,
+			State:     ctx.MetricsState.BatteryState,
+			Backlight: ctx.MetricsState.BacklightLevel,
+		},
+		max,
+	)
+}
@@ -1,5 +1,7 @@
 package parser
 
+import "time"
+
 // CPUInterruptsHandler handles CPU interrupt parsing
 type CPUInterruptsHandler struct{}
 
@@ -24,36 +26,47 @@ func (h *CPUInterruptsHandler) ProcessLine(ctx *ParserContext, line string) Pars
 			ctx.MetricsState.AllSeenCPUs = make(map[string]bool)
 		}
 		ctx.MetricsState.AllSeenCPUs[ctx.CurrentCPU] = true
+		if ctx.MetricsState.PerCPUInterruptsLastSeen == nil {
+			ctx.MetricsState.PerCPUInterruptsLastSeen = make(map[string]time.Time)
+		}
+		ctx.MetricsState.PerCPUInterruptsLastSeen[ctx.CurrentCPU] = time.Now()
 		return StateCPUInterrupts
 	}
 
-	// Parse interrupt data
-	if matches := ipiRateRegex.FindStringSubmatch(line); matches != nil {
-		if val, err := ParseFloat(matches[1]); err == nil {
-			ctx.IPITotal += val
-			if ctx.CurrentCPU != "" {
-				ctx.MetricsState.PerCPUIPIs[ctx.CurrentCPU] = val
-				ctx.MetricsState.AllSeenCPUs[ctx.CurrentCPU] = true
+	// Parse interrupt data. Each rate is skipped entirely when excluded via
+	// --config exclude_metrics - see ctx.ExcludedMetrics.
+	if !ctx.ExcludedMetrics.Has(MetricPerCPUIPIs) {
+		if matches := ipiRateRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := ParseFloat(matches[1]); err == nil {
+				ctx.IPITotal += val
+				if ctx.CurrentCPU != "" {
+					ctx.MetricsState.PerCPUIPIs[ctx.CurrentCPU] = val
+					ctx.MetricsState.AllSeenCPUs[ctx.CurrentCPU] = true
+				}
 			}
 		}
 	}
 
-	if matches := timerRateRegex.FindStringSubmatch(line); matches != nil {
-		if val, err := ParseFloat(matches[1]); err == nil {
-			ctx.TimerTotal += val
-			if ctx.CurrentCPU != "" {
-				ctx.MetricsState.PerCPUTimers[ctx.CurrentCPU] = val
-				ctx.MetricsState.AllSeenCPUs[ctx.CurrentCPU] = true
+	if !ctx.ExcludedMetrics.Has(MetricPerCPUTimers) {
+		if matches := timerRateRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := ParseFloat(matches[1]); err == nil {
+				ctx.TimerTotal += val
+				if ctx.CurrentCPU != "" {
+					ctx.MetricsState.PerCPUTimers[ctx.CurrentCPU] = val
+					ctx.MetricsState.AllSeenCPUs[ctx.CurrentCPU] = true
+				}
 			}
 		}
 	}
 
-	if matches := totalRateRegex.FindStringSubmatch(line); matches != nil {
-		if val, err := ParseFloat(matches[1]); err == nil {
-			ctx.InterruptsTotal += val
-			if ctx.CurrentCPU != "" {
-				ctx.MetricsState.PerCPUInterrupts[ctx.CurrentCPU] = val
-				ctx.MetricsState.AllSeenCPUs[ctx.CurrentCPU] = true
+	if !ctx.ExcludedMetrics.Has(MetricPerCPUInterrupts) {
+		if matches := totalRateRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := ParseFloat(matches[1]); err == nil {
+				ctx.InterruptsTotal += val
+				if ctx.CurrentCPU != "" {
+					ctx.MetricsState.PerCPUInterrupts[ctx.CurrentCPU] = val
+					ctx.MetricsState.AllSeenCPUs[ctx.CurrentCPU] = true
+				}
 			}
 		}
 	}
@@ -42,38 +42,56 @@ func (h *InSampleHandler) ProcessLine(ctx *ParserContext, line string) ParserSta
 		return StateCPUInterrupts
 	}
 
-	// Check for power metrics
-	if cpuPowerRegex.MatchString(line) || gpuPowerRegex.MatchString(line) ||
-	   anePowerRegex.MatchString(line) || dramPowerRegex.MatchString(line) ||
-	   systemPowerRegex.MatchString(line) {
+	// Check for power metrics. Each regex is only evaluated when its
+	// metric isn't excluded via --config exclude_metrics, so a disabled
+	// metric never reaches FindStringSubmatch.
+	cpuPowerOn := !ctx.ExcludedMetrics.Has(MetricCPUPower)
+	gpuPowerOn := !ctx.ExcludedMetrics.Has(MetricGPUPower)
+	anePowerOn := !ctx.ExcludedMetrics.Has(MetricANEPower)
+	dramPowerOn := !ctx.ExcludedMetrics.Has(MetricDRAMPower)
+	systemPowerOn := !ctx.ExcludedMetrics.Has(MetricSystemPower)
+
+	if (cpuPowerOn && cpuPowerRegex.MatchString(line)) || (gpuPowerOn && gpuPowerRegex.MatchString(line)) ||
+	   (anePowerOn && anePowerRegex.MatchString(line)) || (dramPowerOn && dramPowerRegex.MatchString(line)) ||
+	   (systemPowerOn && systemPowerRegex.MatchString(line)) {
 		// Parse power metrics inline
-		if matches := cpuPowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := ParseFloat(matches[1]); err == nil {
-				ctx.MetricsState.CPUPower = val
+		if cpuPowerOn {
+			if matches := cpuPowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := ParseFloat(matches[1]); err == nil {
+					ctx.MetricsState.CPUPower = val
+				}
 			}
 		}
-		if matches := gpuPowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := ParseFloat(matches[1]); err == nil {
-				ctx.MetricsState.GPUPower = val
+		if gpuPowerOn {
+			if matches := gpuPowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := ParseFloat(matches[1]); err == nil {
+					ctx.MetricsState.GPUPower = val
+				}
 			}
 		}
-		if matches := anePowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := ParseFloat(matches[1]); err == nil {
-				ctx.MetricsState.ANEPower = val
+		if anePowerOn {
+			if matches := anePowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := ParseFloat(matches[1]); err == nil {
+					ctx.MetricsState.ANEPower = val
+				}
 			}
 		}
-		if matches := dramPowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := ParseFloat(matches[1]); err == nil {
-				ctx.MetricsState.DRAMPower = val
+		if dramPowerOn {
+			if matches := dramPowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := ParseFloat(matches[1]); err == nil {
+					ctx.MetricsState.DRAMPower = val
+				}
 			}
 		}
-		if matches := systemPowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := ParseFloat(matches[1]); err == nil {
-				// Convert watts to milliwatts if needed
-				if strings.Contains(line, "Watts") {
-					val *= 1000
+		if systemPowerOn {
+			if matches := systemPowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := ParseFloat(matches[1]); err == nil {
+					// Convert watts to milliwatts if needed
+					if strings.Contains(line, "Watts") {
+						val *= 1000
+					}
+					ctx.MetricsState.SystemPower = val
 				}
-				ctx.MetricsState.SystemPower = val
 			}
 		}
 		return StatePowerMetrics
@@ -52,38 +52,50 @@ func (h *ProcessorUsageHandler) ProcessLine(ctx *ParserContext, line string) Par
 		ctx.MetricsState.GPUFreq = freq
 	}
 
-	// Parse power metrics (these appear at the end of Processor usage section)
-	if matches := cpuPowerRegex.FindStringSubmatch(line); matches != nil {
-		if val, err := ParseFloat(matches[1]); err == nil {
-			ctx.MetricsState.CPUPower = val
+	// Parse power metrics (these appear at the end of Processor usage
+	// section). Each is skipped entirely when excluded via --config
+	// exclude_metrics - see ctx.ExcludedMetrics.
+	if !ctx.ExcludedMetrics.Has(MetricCPUPower) {
+		if matches := cpuPowerRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := ParseFloat(matches[1]); err == nil {
+				ctx.MetricsState.CPUPower = val
+			}
 		}
 	}
 
-	if matches := gpuPowerRegex.FindStringSubmatch(line); matches != nil {
-		if val, err := ParseFloat(matches[1]); err == nil {
-			ctx.MetricsState.GPUPower = val
+	if !ctx.ExcludedMetrics.Has(MetricGPUPower) {
+		if matches := gpuPowerRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := ParseFloat(matches[1]); err == nil {
+				ctx.MetricsState.GPUPower = val
+			}
 		}
 	}
 
-	if matches := anePowerRegex.FindStringSubmatch(line); matches != nil {
-		if val, err := ParseFloat(matches[1]); err == nil {
-			ctx.MetricsState.ANEPower = val
+	if !ctx.ExcludedMetrics.Has(MetricANEPower) {
+		if matches := anePowerRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := ParseFloat(matches[1]); err == nil {
+				ctx.MetricsState.ANEPower = val
+			}
 		}
 	}
 
-	if matches := dramPowerRegex.FindStringSubmatch(line); matches != nil {
-		if val, err := ParseFloat(matches[1]); err == nil {
-			ctx.MetricsState.DRAMPower = val
+	if !ctx.ExcludedMetrics.Has(MetricDRAMPower) {
+		if matches := dramPowerRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := ParseFloat(matches[1]); err == nil {
+				ctx.MetricsState.DRAMPower = val
+			}
 		}
 	}
 
-	if matches := systemPowerRegex.FindStringSubmatch(line); matches != nil {
-		if val, err := ParseFloat(matches[1]); err == nil {
-			// Convert watts to milliwatts if needed
-			if strings.Contains(line, "Watts") {
-				val *= 1000
+	if !ctx.ExcludedMetrics.Has(MetricSystemPower) {
+		if matches := systemPowerRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := ParseFloat(matches[1]); err == nil {
+				// Convert watts to milliwatts if needed
+				if strings.Contains(line, "Watts") {
+					val *= 1000
+				}
+				ctx.MetricsState.SystemPower = val
 			}
-			ctx.MetricsState.SystemPower = val
 		}
 	}
 
@@ -2,13 +2,33 @@ package parser
 
 import (
 	"fmt"
-	"os/exec"
+	"runtime"
 	"strings"
 	"time"
 
+	"powermetrics-tui/internal/memstats"
 	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/proccache"
+	"powermetrics-tui/internal/selector"
 )
 
+// clampCPUPercent bounds a parsed CPU% reading to [0, 100*numCPUs], the
+// most powermetrics could plausibly report if every core were saturated by
+// one task. Catches a PID-reuse artifact or a mid-sample parse glitch
+// producing a wild value, rather than letting it poison CPUHistory/
+// ProcessCPUMsTotal/ProcessCPUEWMA with a single huge outlier.
+func clampCPUPercent(percent float64) float64 {
+	max := 100.0 * float64(runtime.NumCPU())
+	switch {
+	case percent < 0:
+		return 0
+	case percent > max:
+		return max
+	default:
+		return percent
+	}
+}
+
 // RunningTasksHandler handles the running tasks section parsing
 type RunningTasksHandler struct{}
 
@@ -67,8 +87,19 @@ func (h *RunningTasksHandler) ProcessLine(ctx *ParserContext, line string) Parse
 			// Mark this as a dead/exited process immediately
 		}
 
-		// Convert CPU ms/s to percentage (approximate)
-		cpuPercent := cpuMs / 10.0
+		// Convert CPU ms/s to percentage. powermetrics computes this column
+		// itself from its own tick counters already normalized to one
+		// wall-clock second, independent of --interval/-i - unlike
+		// NetworkIn/Out and DiskRead/Write, which really are raw per-
+		// sample totals accumulateCounters scales by the measured interval
+		// (see WaitingForSampleHandler.recordInterval/
+		// ctx.PendingIntervalSeconds). Scaling this conversion by the
+		// interval too would both double-normalize it and break
+		// accumulateCounters' `proc.CPUPercent * 10` inverse, which
+		// reconstructs this same ms/s figure for ProcessCPUMsTotal
+		// regardless of -i. clampCPUPercent still guards against a
+		// recycled PID or a mid-sample glitch producing a nonsense spike.
+		cpuPercent := clampCPUPercent(cpuMs / 10.0)
 
 		// Check if this is a subprocess (indented) or coalition (not indented)
 		isSubprocess := IsIndented(line)
@@ -89,23 +120,32 @@ func (h *RunningTasksHandler) handleSubprocess(ctx *ParserContext, name string,
 
 	if isDead {
 		// Track this as an exited process immediately
-		processName := fmt.Sprintf("Unknown Process (PID %d)", id)
-		h.trackExitedProcess(ctx, id, processName, time.Now())
+		h.trackExitedProcess(ctx, id, exitedProcessName(id), time.Now())
 
 		// Don't add to active processes list
 		return StateRunningTasks
 	}
 
-	// Update process history
-	if ctx.MetricsState.ProcessCPUHistory[id] == nil {
-		ctx.MetricsState.ProcessCPUHistory[id] = make([]float64, 0, 10)
-	}
-	ctx.MetricsState.ProcessCPUHistory[id] = models.AddToHistory(ctx.MetricsState.ProcessCPUHistory[id], cpuPercent, 10)
+	// Update process history. Watchlisted pids keep a much longer buffer
+	// (see models.WatchlistHistorySamples) so a memory-leak curve can be
+	// inspected retroactively instead of only over the last few samples.
+	//
+	// With an active selector, only matching PIDs get a history entry at
+	// all: otherwise ProcessCPUHistory/ProcessMemHistory grow one entry per
+	// PID ever observed and never shrink, so a selector meant to narrow the
+	// view to a handful of processes wouldn't bound the memory it retains.
+	if ctx.Selector == nil || (ctx.Selector.MatchName(name) && ctx.Selector.MatchPID(id)) {
+		historyCap := ctx.MetricsState.HistorySamples(id)
+		if ctx.MetricsState.ProcessCPUHistory[id] == nil {
+			ctx.MetricsState.ProcessCPUHistory[id] = make([]float64, 0, historyCap)
+		}
+		ctx.MetricsState.ProcessCPUHistory[id] = models.AddToHistory(ctx.MetricsState.ProcessCPUHistory[id], cpuPercent, historyCap)
 
-	if ctx.MetricsState.ProcessMemHistory[id] == nil {
-		ctx.MetricsState.ProcessMemHistory[id] = make([]float64, 0, 10)
+		if ctx.MetricsState.ProcessMemHistory[id] == nil {
+			ctx.MetricsState.ProcessMemHistory[id] = make([]float64, 0, historyCap)
+		}
+		ctx.MetricsState.ProcessMemHistory[id] = models.AddToHistory(ctx.MetricsState.ProcessMemHistory[id], userPercent, historyCap)
 	}
-	ctx.MetricsState.ProcessMemHistory[id] = models.AddToHistory(ctx.MetricsState.ProcessMemHistory[id], userPercent, 10)
 
 	// Create subprocess
 	subprocess := models.ProcessInfo{
@@ -117,6 +157,7 @@ func (h *RunningTasksHandler) handleSubprocess(ctx *ParserContext, name string,
 		NetworkMB:     0,
 		CPUHistory:    ctx.MetricsState.ProcessCPUHistory[id],
 		MemoryHistory: ctx.MetricsState.ProcessMemHistory[id],
+		Pinned:        ctx.MetricsState.PinnedPIDs[id],
 	}
 
 	if ctx.CurrentCoalition != nil {
@@ -142,24 +183,26 @@ func (h *RunningTasksHandler) handleCoalition(ctx *ParserContext, name string, i
 	// Check if this is a dead process (marked with <dead-process- prefix)
 	if strings.HasPrefix(name, "<dead-process-") {
 		// Track this as an exited process immediately
-		processName := fmt.Sprintf("Unknown Process (PID %d)", id)
-		h.trackExitedProcess(ctx, id, processName, time.Now())
+		h.trackExitedProcess(ctx, id, exitedProcessName(id), time.Now())
 
 		// Don't create a coalition for it
 		ctx.CurrentCoalition = nil
 		return StateRunningTasks
 	}
 
-	// Update coalition history
-	if ctx.MetricsState.CoalitionCPUHistory[id] == nil {
-		ctx.MetricsState.CoalitionCPUHistory[id] = make([]float64, 0, 10)
-	}
-	ctx.MetricsState.CoalitionCPUHistory[id] = models.AddToHistory(ctx.MetricsState.CoalitionCPUHistory[id], cpuPercent, 10)
+	// Update coalition history. As with handleSubprocess, an active selector
+	// narrows which coalitions keep a history buffer at all.
+	if ctx.Selector == nil || ctx.Selector.MatchCoalition(name) {
+		if ctx.MetricsState.CoalitionCPUHistory[id] == nil {
+			ctx.MetricsState.CoalitionCPUHistory[id] = make([]float64, 0, 10)
+		}
+		ctx.MetricsState.CoalitionCPUHistory[id] = models.AddToHistory(ctx.MetricsState.CoalitionCPUHistory[id], cpuPercent, 10)
 
-	if ctx.MetricsState.CoalitionMemHistory[id] == nil {
-		ctx.MetricsState.CoalitionMemHistory[id] = make([]float64, 0, 10)
+		if ctx.MetricsState.CoalitionMemHistory[id] == nil {
+			ctx.MetricsState.CoalitionMemHistory[id] = make([]float64, 0, 10)
+		}
+		ctx.MetricsState.CoalitionMemHistory[id] = models.AddToHistory(ctx.MetricsState.CoalitionMemHistory[id], userPercent, 10)
 	}
-	ctx.MetricsState.CoalitionMemHistory[id] = models.AddToHistory(ctx.MetricsState.CoalitionMemHistory[id], userPercent, 10)
 
 	// Track coalition name
 	if ctx.MetricsState.CoalitionNames == nil {
@@ -193,8 +236,40 @@ func (h *RunningTasksHandler) Exit(ctx *ParserContext) {
 	// Handle orphaned subprocesses
 	h.handleOrphanedSubprocesses(ctx)
 
+	// Drop anything the configured selector doesn't match before
+	// updateProcessTracking ever sees it, so a non-matching PID is never
+	// inserted into LastSeenPIDs/ProcessNames (not just ProcessCPUHistory/
+	// ProcessMemHistory, which handleSubprocess/handleCoalition already
+	// guard) and the ghost-PID cleanup path below stays bounded to however
+	// many processes the selector actually lets through.
+	if ctx.Selector != nil {
+		ctx.NewProcesses = filterProcesses(ctx.Selector, ctx.NewProcesses)
+		ctx.NewCoalitions = filterCoalitions(ctx.Selector, ctx.NewCoalitions)
+	}
+
 	// Update process tracking
-	h.updateProcessTracking(ctx)
+	tree := h.updateProcessTracking(ctx)
+
+	// Fill in real RSS/VMS/thread-and-FD counts/cmdline/user/start time for
+	// every surviving process from one batched enrichment call, in place of
+	// powermetrics' own userPercent figure and the zeroed Disk/Network/new
+	// fields - see internal/procenrich. --no-enrich swaps in a
+	// procenrich.NoopEnricher, leaving the raw powermetrics-only fields.
+	h.updateProcessEnrichment(ctx)
+
+	// Roll up true (shared-page-aware) memory accounting for each finalized
+	// coalition, now that filtering above has settled which coalitions and
+	// subprocesses actually belong to this sample.
+	h.updateMemoryAccounting(ctx)
+
+	// --follow-children: fold each coalition's un-reported descendants'
+	// CPU/memory into its totals, reusing updateProcessTracking's `ps`
+	// snapshot instead of forking a second one.
+	h.updateFollowChildren(ctx, tree)
+
+	// --include-dead-children: add each coalition leader's reaped-child
+	// CPU delta since the last sample.
+	h.updateDeadChildrenCPU(ctx)
 
 	// Update the state with new data
 	ctx.MetricsState.Processes = ctx.NewProcesses
@@ -206,6 +281,157 @@ func (h *RunningTasksHandler) Exit(ctx *ParserContext) {
 			ctx.MetricsState.CoalitionNames[coalition.CoalitionID] = coalition.Name
 		}
 	}
+
+	// Append LivenessVerifier's running totals to the debug log every so
+	// often rather than every sample, so a persistent spike in false
+	// positives is visible without flooding the log on the happy path.
+	if ctx.SampleCount%60 == 0 {
+		liveness().LogStats()
+	}
+
+	// Persist ProcCache once per sample rather than once per exited PID -
+	// trackExitedProcess/updateProcessTracking's Put calls above only touch
+	// the in-memory map, so a bursty process-churn sample doesn't do a full
+	// MarshalIndent+WriteFile of the whole cache per PID that exited in it.
+	if ctx.ProcCache != nil {
+		ctx.ProcCache.Save()
+	}
+}
+
+// updateProcessEnrichment fills in each of ctx.NewProcesses' MemoryMB/VMS/
+// NumThreads/NumFDs/Cmdline/User/StartTime from a single batched
+// enricher.Enrich call covering every PID in this sample, in place of the
+// per-PID `ps` fork populateMemoryEx used to do. A PID the enricher
+// couldn't resolve (already gone, or --no-enrich's NoopEnricher) keeps
+// whatever it already had - handleSubprocess's userPercent-derived
+// MemoryMB and zeroed Disk/NetworkMB/new fields.
+func (h *RunningTasksHandler) updateProcessEnrichment(ctx *ParserContext) {
+	pids := make([]int, len(ctx.NewProcesses))
+	for i, p := range ctx.NewProcesses {
+		pids[i] = p.PID
+	}
+
+	info := enricher().Enrich(pids)
+	for i := range ctx.NewProcesses {
+		enriched, ok := info[ctx.NewProcesses[i].PID]
+		if !ok {
+			continue
+		}
+		ctx.NewProcesses[i].MemoryMB = enriched.RSSMB
+		ctx.NewProcesses[i].VMS = enriched.VMSMB
+		ctx.NewProcesses[i].NumThreads = enriched.NumThreads
+		ctx.NewProcesses[i].NumFDs = enriched.NumFDs
+		ctx.NewProcesses[i].Cmdline = enriched.Cmdline
+		ctx.NewProcesses[i].User = enriched.User
+		ctx.NewProcesses[i].StartTime = enriched.StartTime
+	}
+}
+
+// updateMemoryAccounting fills in MemoryPSSMB/MemoryUSSMB for every
+// coalition in ctx.NewCoalitions by rolling up memstats.Accountant's
+// per-PID PSS/USS across each coalition's Subprocesses, in place of
+// userPercent's naive sum which double-counts pages subprocesses share.
+func (h *RunningTasksHandler) updateMemoryAccounting(ctx *ParserContext) {
+	a := accountant()
+	for i := range ctx.NewCoalitions {
+		pids := make([]int, len(ctx.NewCoalitions[i].Subprocesses))
+		for j, proc := range ctx.NewCoalitions[i].Subprocesses {
+			pids[j] = proc.PID
+		}
+		pssMB, ussMB := memstats.Rollup(a, pids)
+		ctx.NewCoalitions[i].MemoryPSSMB = pssMB
+		ctx.NewCoalitions[i].MemoryUSSMB = ussMB
+	}
+}
+
+// updateFollowChildren folds each coalition's un-reported descendant
+// processes' CPU/memory into its totals, under --follow-children. A
+// coalition's Subprocesses are only what powermetrics itself reported;
+// this walks tree's PPID index from each of them to also catch grandchild
+// forks (e.g. a shell spawned by a reported subprocess) that powermetrics
+// never attributed to the coalition at all.
+func (h *RunningTasksHandler) updateFollowChildren(ctx *ParserContext, tree *ProcessTree) {
+	if !ctx.FollowChildren || tree == nil {
+		return
+	}
+
+	for i := range ctx.NewCoalitions {
+		c := &ctx.NewCoalitions[i]
+		if len(c.Subprocesses) == 0 {
+			continue
+		}
+
+		seen := make(map[int]bool, len(c.Subprocesses))
+		queue := make([]int, 0, len(c.Subprocesses))
+		for _, sub := range c.Subprocesses {
+			seen[sub.PID] = true
+			queue = append(queue, sub.PID)
+		}
+
+		for len(queue) > 0 {
+			pid := queue[0]
+			queue = queue[1:]
+			for _, child := range tree.Children(pid) {
+				if seen[child] {
+					continue
+				}
+				seen[child] = true
+				if snap, ok := tree.Lookup(child); ok {
+					c.CPUPercent += snap.CPUPercent
+					c.MemoryMB += snap.MemoryMB
+				}
+				queue = append(queue, child)
+			}
+		}
+	}
+}
+
+// updateDeadChildrenCPU adds each coalition leader's reaped-child CPU delta
+// since the last sample to CoalitionCPUHistory, under
+// --include-dead-children. Without this, a burst of children that fork and
+// exit between two powermetrics samples never shows up anywhere except as
+// a <dead-process-N> entry with no CPU attributed to its parent.
+func (h *RunningTasksHandler) updateDeadChildrenCPU(ctx *ParserContext) {
+	if !ctx.IncludeDeadChildren {
+		return
+	}
+	if ctx.MetricsState.CoalitionChildCPUMsTotal == nil {
+		ctx.MetricsState.CoalitionChildCPUMsTotal = make(map[int]float64)
+	}
+
+	reader := childCPUReader()
+	for i := range ctx.NewCoalitions {
+		c := &ctx.NewCoalitions[i]
+		if len(c.Subprocesses) == 0 {
+			continue
+		}
+		leader := c.Subprocesses[0].PID
+
+		cpu, err := reader.Sample(leader)
+		if err != nil {
+			continue
+		}
+
+		cumulativeMs := cpu.Total()
+		prevMs, hadPrev := ctx.MetricsState.CoalitionChildCPUMsTotal[c.CoalitionID]
+		ctx.MetricsState.CoalitionChildCPUMsTotal[c.CoalitionID] = cumulativeMs
+		if !hadPrev {
+			continue
+		}
+
+		deltaMs := cumulativeMs - prevMs
+		if deltaMs <= 0 {
+			// Zero, or negative because the leader PID was reused since
+			// the last sample and its cumulative counter reset with it.
+			continue
+		}
+
+		if ctx.MetricsState.CoalitionCPUHistory[c.CoalitionID] == nil {
+			ctx.MetricsState.CoalitionCPUHistory[c.CoalitionID] = make([]float64, 0, 10)
+		}
+		ctx.MetricsState.CoalitionCPUHistory[c.CoalitionID] = models.AddToHistory(
+			ctx.MetricsState.CoalitionCPUHistory[c.CoalitionID], deltaMs, 10)
+	}
 }
 
 func (h *RunningTasksHandler) handleOrphanedSubprocesses(ctx *ParserContext) {
@@ -239,7 +465,11 @@ func (h *RunningTasksHandler) handleOrphanedSubprocesses(ctx *ParserContext) {
 	}
 }
 
-func (h *RunningTasksHandler) updateProcessTracking(ctx *ParserContext) {
+// updateProcessTracking reconciles LastSeenPIDs/RecentlyExited against the
+// current sample and returns the ProcessTree snapshot it took along the
+// way, so callers further down Exit (e.g. updateFollowChildren) can reuse
+// it instead of forking a second `ps`.
+func (h *RunningTasksHandler) updateProcessTracking(ctx *ParserContext) *ProcessTree {
 	currentTime := time.Now()
 	currentPIDs := make(map[int]bool)
 	currentCoalitionIDs := make(map[int]bool)
@@ -263,25 +493,43 @@ func (h *RunningTasksHandler) updateProcessTracking(ctx *ParserContext) {
 		ctx.MetricsState.CoalitionNames = make(map[int]string)
 	}
 
+	// A single batched `ps` snapshot backs both the liveness check below
+	// and the PPID/UID/Command/State enrichment further down, instead of
+	// forking `ps -p <pid>` once per candidate missing PID every sample.
+	tree, _ := SnapshotProcessTree()
+
 	// Check for processes that are no longer present
 	for pid := range ctx.MetricsState.LastSeenPIDs {
 		if !currentPIDs[pid] {
 			processName := ctx.MetricsState.ProcessNames[pid]
 			if processName == "" {
-				// This is a ghost PID - a PID in LastSeenPIDs without a name
-				// This can happen if the app was restarted with stale state
+				// This is a ghost PID - a PID in LastSeenPIDs without a name.
+				// This can happen if the app was restarted with stale state.
+				// ProcCache, if wired in, remembers this PID from before the
+				// restart; backfill its name and record the exit instead of
+				// silently dropping it.
+				if ctx.ProcCache != nil {
+					if entry, ok := ctx.ProcCache.Get(pid); ok {
+						h.trackExitedProcess(ctx, pid, entry.Name, currentTime)
+					}
+				}
+
 				delete(ctx.MetricsState.LastSeenPIDs, pid)
 				delete(ctx.MetricsState.ProcessCPUHistory, pid)
 				delete(ctx.MetricsState.ProcessMemHistory, pid)
 				delete(ctx.MetricsState.ProcessNames, pid)  // Also clean this up just in case
+				enricher().Invalidate(pid)
 
 				// Don't log this anymore since we know it happens on startup
 				continue
 			}
 
-			// Verify if process is actually dead using ps
-			psCmd := exec.Command("ps", "-p", fmt.Sprintf("%d", pid), "-o", "pid=")
-			if err := psCmd.Run(); err == nil {
+			// Verify if process is actually dead. liveness() layers a
+			// short "confirmed alive" cache and a kill(pid, 0) check in
+			// front of this sample's batched snapshot, so a PID that
+			// drops out of powermetrics for one or two samples isn't
+			// marked exited before it reappears.
+			if liveness().Alive(pid, tree) {
 				// Process is still alive - skip marking as exited
 				continue
 			}
@@ -293,6 +541,7 @@ func (h *RunningTasksHandler) updateProcessTracking(ctx *ParserContext) {
 			delete(ctx.MetricsState.LastSeenPIDs, pid)
 			delete(ctx.MetricsState.ProcessCPUHistory, pid)
 			delete(ctx.MetricsState.ProcessMemHistory, pid)
+			enricher().Invalidate(pid)
 		}
 	}
 
@@ -305,6 +554,17 @@ func (h *RunningTasksHandler) updateProcessTracking(ctx *ParserContext) {
 	}
 	ctx.MetricsState.RecentlyExited = cleanedExited
 
+	// Enrich current processes with PPID/UID/Command/State from the same
+	// batched snapshot, rather than a second round of per-PID `ps` calls.
+	for i := range ctx.NewProcesses {
+		if snap, ok := tree.Lookup(ctx.NewProcesses[i].PID); ok {
+			ctx.NewProcesses[i].PPID = snap.PPID
+			ctx.NewProcesses[i].UID = snap.UID
+			ctx.NewProcesses[i].Command = snap.Command
+			ctx.NewProcesses[i].State = snap.State
+		}
+	}
+
 	// Update tracking maps with current processes
 	for _, proc := range ctx.NewProcesses {
 		ctx.MetricsState.LastSeenPIDs[proc.PID] = currentTime
@@ -312,9 +572,62 @@ func (h *RunningTasksHandler) updateProcessTracking(ctx *ParserContext) {
 		// ALWAYS set process name when we see a process to prevent ghost PIDs
 		ctx.MetricsState.ProcessNames[proc.PID] = proc.Name
 
+		// Record this PID's metadata so a future restart can resolve it as a
+		// ghost PID instead of purging it. FirstSeenTime is preserved across
+		// samples once recorded.
+		if ctx.ProcCache != nil {
+			firstSeen := currentTime
+			if existing, ok := ctx.ProcCache.Get(proc.PID); ok {
+				firstSeen = existing.FirstSeenTime
+			}
+			ctx.ProcCache.Put(proccache.Entry{
+				PID:           proc.PID,
+				Name:          proc.Name,
+				FirstSeenTime: firstSeen,
+				CoalitionName: proc.CoalitionName,
+			})
+		}
+
+		// A PID that reappears after being recorded as exited has been
+		// reused by the OS for an unrelated process (or the original was a
+		// false positive); either way its cumulative CPU-ms total must not
+		// keep counting against whatever now holds that PID.
+		if h.pidRecentlyExited(ctx, proc.PID) {
+			delete(ctx.MetricsState.ProcessCPUMsTotal, proc.PID)
+			delete(ctx.MetricsState.ProcessCPUEWMA, proc.PID)
+		}
+
 		// Remove from recently exited if it reappeared (false positive)
 		h.removeFromRecentlyExited(ctx, proc.PID)
 	}
+
+	return tree
+}
+
+// pidRecentlyExited reports whether pid appears in any RecentlyExited
+// entry's PIDs list.
+func (h *RunningTasksHandler) pidRecentlyExited(ctx *ParserContext, pid int) bool {
+	for _, exited := range ctx.MetricsState.RecentlyExited {
+		for _, existingPID := range exited.PIDs {
+			if existingPID == pid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// exitedProcessName enriches a dead/disappeared PID with its real
+// executable path or command line via pidFinder, falling back to the
+// generic "Unknown Process (PID N)" placeholder when the PID is already
+// gone by the time we look (the common case - powermetrics only reports an
+// empty name for a process that died mid-sample) or the Finder can't name
+// it.
+func exitedProcessName(pid int) string {
+	if name, err := pidFinder().EnrichName(pid); err == nil {
+		return name
+	}
+	return fmt.Sprintf("Unknown Process (PID %d)", pid)
 }
 
 func (h *RunningTasksHandler) trackExitedProcess(ctx *ParserContext, pid int, processName string, currentTime time.Time) {
@@ -379,4 +692,26 @@ func (h *RunningTasksHandler) removeFromRecentlyExited(ctx *ParserContext, pid i
 			}
 		}
 	}
-}
\ No newline at end of file
+}
+// filterProcesses keeps only the processes sel matches by name and PID,
+// then caps the result to sel.TopNCPU's highest-CPU survivors if set.
+func filterProcesses(sel *selector.Set, processes []models.ProcessInfo) []models.ProcessInfo {
+	kept := make([]models.ProcessInfo, 0, len(processes))
+	for _, p := range processes {
+		if sel.MatchName(p.Name) && sel.MatchPID(p.PID) {
+			kept = append(kept, p)
+		}
+	}
+	return selector.TopNProcessesByCPU(kept, sel.TopNCPU())
+}
+
+// filterCoalitions keeps only the coalitions sel matches by name.
+func filterCoalitions(sel *selector.Set, coalitions []models.ProcessCoalition) []models.ProcessCoalition {
+	kept := make([]models.ProcessCoalition, 0, len(coalitions))
+	for _, co := range coalitions {
+		if sel.MatchCoalition(co.Name) {
+			kept = append(kept, co)
+		}
+	}
+	return kept
+}
@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"time"
+
 	"powermetrics-tui/internal/models"
 )
 
@@ -23,12 +25,38 @@ func (h *WaitingForSampleHandler) Enter(ctx *ParserContext) {
 func (h *WaitingForSampleHandler) ProcessLine(ctx *ParserContext, line string) ParserState {
 	if IsNewSample(line) {
 		ctx.SampleCount++
+		h.recordInterval(ctx, line)
 		return StateInSample
 	}
 	return StateWaitingForSample
 }
 
+// recordInterval computes how long the sample that just finished actually
+// covered, preferring the header's own "(N ms elapsed)" value over a
+// wall-clock diff against the previous sample, and stashes it on ctx for
+// accumulateCounters (run next, from Exit) to integrate byte totals
+// against rather than assuming a flat 1s interval.
+func (h *WaitingForSampleHandler) recordInterval(ctx *ParserContext, line string) {
+	now := time.Now()
+	interval := time.Second
+	if elapsed, ok := ParseSampleElapsed(line); ok {
+		interval = elapsed
+	} else if !ctx.LastSampleTime.IsZero() {
+		interval = now.Sub(ctx.LastSampleTime)
+	}
+	ctx.PendingIntervalSeconds = interval.Seconds()
+	ctx.LastSampleTime = now
+}
+
 func (h *WaitingForSampleHandler) Exit(ctx *ParserContext) {
+	// This fires at the start of the NEW sample, after the previous one has
+	// already been fully merged into MetricsState, so it's the right place
+	// to fold that finished sample into the running totals below before we
+	// reset the per-sample scratch state.
+	h.accumulateCounters(ctx)
+	h.summarizeSubprocesses(ctx)
+	h.updateSmoothing(ctx)
+
 	// Prepare for new sample parsing
 	ctx.NewProcesses = make([]models.ProcessInfo, 0)
 	ctx.NewCoalitions = make([]models.ProcessCoalition, 0)
@@ -43,4 +71,117 @@ func (h *WaitingForSampleHandler) Exit(ctx *ParserContext) {
 			ctx.MetricsState.PerCPUTimers[cpu] = 0
 		}
 	}
-}
\ No newline at end of file
+}
+
+// accumulateCounters folds the just-finalized sample's network/disk rates
+// and per-process/coalition CPU percentages into MetricsState's cumulative
+// *Total counters and *Delta fields, following the pattern Arvados
+// crunchstat adopted when it switched from "tx N rx N interval" to
+// "tx N +dN rx N +dN".
+func (h *WaitingForSampleHandler) accumulateCounters(ctx *ParserContext) {
+	state := ctx.MetricsState
+
+	interval := ctx.PendingIntervalSeconds
+	if interval <= 0 {
+		interval = 1
+	}
+	state.SampleIntervalSeconds = interval
+
+	state.NetworkInDelta = state.NetworkIn
+	state.NetworkOutDelta = state.NetworkOut
+	state.DiskReadDelta = state.DiskRead
+	state.DiskWriteDelta = state.DiskWrite
+	state.NetworkInTotal += state.NetworkInDelta
+	state.NetworkOutTotal += state.NetworkOutDelta
+	state.DiskReadTotal += state.DiskReadDelta
+	state.DiskWriteTotal += state.DiskWriteDelta
+
+	const mbToBytes = 1_000_000
+	state.NetworkInTotalBytes += state.NetworkInDelta * interval * mbToBytes
+	state.NetworkOutTotalBytes += state.NetworkOutDelta * interval * mbToBytes
+	state.DiskReadTotalBytes += state.DiskReadDelta * interval * mbToBytes
+	state.DiskWriteTotalBytes += state.DiskWriteDelta * interval * mbToBytes
+
+	if state.ProcessCPUMsTotal == nil {
+		state.ProcessCPUMsTotal = make(map[int]float64)
+	}
+	for i := range state.Processes {
+		proc := &state.Processes[i]
+		state.ProcessCPUMsTotal[proc.PID] += proc.CPUPercent * 10
+		proc.CPUMsTotal = state.ProcessCPUMsTotal[proc.PID]
+	}
+
+	if state.CoalitionCPUMsTotal == nil {
+		state.CoalitionCPUMsTotal = make(map[int]float64)
+	}
+	for i := range state.Coalitions {
+		co := &state.Coalitions[i]
+		state.CoalitionCPUMsTotal[co.CoalitionID] += co.CPUPercent * 10
+		co.CPUMsTotal = state.CoalitionCPUMsTotal[co.CoalitionID]
+	}
+}
+
+// summarizeSubprocesses sums each coalition's direct Subprocesses' CPU
+// milliseconds, estimated power, and wakeups, so the tree view can show
+// which subprocess is driving its parent's cost without re-summing on
+// every redraw.
+func (h *WaitingForSampleHandler) summarizeSubprocesses(ctx *ParserContext) {
+	for i := range ctx.MetricsState.Coalitions {
+		co := &ctx.MetricsState.Coalitions[i]
+
+		var cpuMsSum, powerSum, wakeupsSum float64
+		for _, proc := range co.Subprocesses {
+			cpuMsSum += proc.CPUPercent * 10
+			powerSum += proc.EnergyEstimate
+			wakeupsSum += proc.Wakeups
+		}
+		co.SubprocessCPUMsSum = cpuMsSum
+		co.SubprocessPowerSum = powerSum
+		co.SubprocessWakeupsSum = wakeupsSum
+	}
+}
+
+// updateSmoothing folds the just-finalized sample's power scalars, IPI
+// count, and per-process/coalition CPU% into their EWMAState companions, so
+// the TUI and threshold alerts can work off a stabilized series instead of
+// a single noisy sample.
+func (h *WaitingForSampleHandler) updateSmoothing(ctx *ParserContext) {
+	state := ctx.MetricsState
+	alpha := state.SmoothingAlpha
+	if alpha <= 0 {
+		alpha = models.DefaultSmoothingAlpha
+	}
+	window := state.SmoothingWindow
+	if window <= 0 {
+		window = models.DefaultSmoothingWindow
+	}
+
+	state.CPUPowerSmoothed.Update(state.CPUPower, alpha, window)
+	state.GPUPowerSmoothed.Update(state.GPUPower, alpha, window)
+	state.ANEPowerSmoothed.Update(state.ANEPower, alpha, window)
+	state.DRAMPowerSmoothed.Update(state.DRAMPower, alpha, window)
+	state.SystemPowerSmoothed.Update(state.SystemPower, alpha, window)
+	state.IPIRateSmoothed.Update(float64(state.IPICount), alpha, window)
+
+	if state.ProcessCPUEWMA == nil {
+		state.ProcessCPUEWMA = make(map[int]models.EWMAState)
+	}
+	for i := range state.Processes {
+		proc := &state.Processes[i]
+		ewma := state.ProcessCPUEWMA[proc.PID]
+		ewma.Update(proc.CPUPercent, alpha, window)
+		state.ProcessCPUEWMA[proc.PID] = ewma
+		proc.CPUPercentEWMA = ewma.Value
+	}
+
+	if state.CoalitionCPUEWMA == nil {
+		state.CoalitionCPUEWMA = make(map[int]models.EWMAState)
+	}
+	for i := range state.Coalitions {
+		co := &state.Coalitions[i]
+		ewma := state.CoalitionCPUEWMA[co.CoalitionID]
+		ewma.Update(co.CPUPercent, alpha, window)
+		state.CoalitionCPUEWMA[co.CoalitionID] = ewma
+		co.CPUPercentEWMA = ewma.Value
+	}
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// livenessTTL is how long a PID confirmed alive is trusted without
+// re-verification, so a PID that drops out of a single powermetrics
+// sample and reappears 2-3 samples later isn't re-verified (or marked
+// exited) on every one of those samples.
+const livenessTTL = 3 * time.Second
+
+// LivenessVerifier answers "is this PID actually dead" for
+// updateProcessTracking. It layers two cheap checks in front of the
+// batched `ps -Ao` snapshot updateProcessTracking already builds every
+// sample for PPID/UID/Command/State enrichment: a short-lived
+// "confirmed alive" cache, and an O(1) kill(pid, 0) existence check that
+// needs no exec at all.
+type LivenessVerifier struct {
+	mu        sync.Mutex
+	confirmed map[int]time.Time
+
+	verifications  int
+	falsePositives int
+}
+
+// NewLivenessVerifier returns a LivenessVerifier with an empty cache.
+func NewLivenessVerifier() *LivenessVerifier {
+	return &LivenessVerifier{confirmed: make(map[int]time.Time)}
+}
+
+// Alive reports whether pid is still alive. tree is the same
+// ProcessTree updateProcessTracking already snapshotted this sample;
+// Alive falls back to it only when neither the cache nor kill(pid, 0)
+// can answer on their own.
+func (v *LivenessVerifier) Alive(pid int, tree *ProcessTree) bool {
+	v.mu.Lock()
+	if last, ok := v.confirmed[pid]; ok && time.Since(last) < livenessTTL {
+		v.mu.Unlock()
+		return true
+	}
+	v.verifications++
+	v.mu.Unlock()
+
+	alive := aliveBySignal(pid)
+	// kill(pid, 0) claims pid is gone but this sample's `ps` snapshot still
+	// lists it - e.g. the process exited in the gap between the snapshot
+	// and the kill(pid, 0) check. Trust the
+	// snapshot; count it so a misbehaving kill(0) path is visible in the
+	// debug log instead of silently marking live processes exited.
+	fellBack := !alive && tree.Alive(pid)
+	if fellBack {
+		alive = true
+	}
+
+	v.mu.Lock()
+	if fellBack {
+		v.falsePositives++
+	}
+	if alive {
+		v.confirmed[pid] = time.Now()
+	} else {
+		delete(v.confirmed, pid)
+	}
+	v.mu.Unlock()
+
+	return alive
+}
+
+// aliveBySignal reports whether pid exists via kill(pid, 0): signal 0 is
+// never actually delivered, so this is a pure existence check - the same
+// trick the `kill -0 <pid>` shell idiom relies on.
+func aliveBySignal(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	if err == nil {
+		return true
+	}
+	// EPERM means pid exists but is owned by another user, so it's alive
+	// just not signalable by us; ESRCH (or anything else) means gone.
+	return err == syscall.EPERM
+}
+
+// LogStats appends this verifier's lifetime verification/false-positive
+// counts to the same debug log ParsePowerMetricsOutput writes its
+// ghost-PID/name-change diagnostics to, so a spike in either is visible without
+// attaching a debugger.
+func (v *LivenessVerifier) LogStats() {
+	v.mu.Lock()
+	verifications, falsePositives := v.verifications, v.falsePositives
+	v.mu.Unlock()
+
+	debugFile, err := os.OpenFile("/tmp/powermetrics-debug.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return
+	}
+	defer debugFile.Close()
+	fmt.Fprintf(debugFile, "[%s] LivenessVerifier: %d verifications, %d false positives (kill(0) said dead, ps disagreed)\n",
+		time.Now().Format(time.RFC3339), verifications, falsePositives)
+}
+
+var (
+	livenessMu     sync.RWMutex
+	activeLiveness = NewLivenessVerifier()
+)
+
+// SetLivenessVerifier overrides the package-wide LivenessVerifier, e.g.
+// so a test can inject one with a pre-populated cache.
+func SetLivenessVerifier(v *LivenessVerifier) {
+	livenessMu.Lock()
+	defer livenessMu.Unlock()
+	activeLiveness = v
+}
+
+func liveness() *LivenessVerifier {
+	livenessMu.RLock()
+	defer livenessMu.RUnlock()
+	return activeLiveness
+}
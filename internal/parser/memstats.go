@@ -0,0 +1,30 @@
+package parser
+
+import (
+	"sync"
+
+	"powermetrics-tui/internal/memstats"
+)
+
+// activeAccountant is the memstats.Accountant RunningTasksHandler.Exit
+// consults to fill in each finalized coalition's MemoryPSSMB/MemoryUSSMB.
+// Defaults to memstats.NewAccountant()'s platform-appropriate
+// implementation; guarded like activePIDFinder for the same reason (see
+// pidfinder.go).
+var (
+	accountantMu     sync.RWMutex
+	activeAccountant = memstats.NewAccountant()
+)
+
+// SetAccountant makes a the active memstats.Accountant.
+func SetAccountant(a memstats.Accountant) {
+	accountantMu.Lock()
+	defer accountantMu.Unlock()
+	activeAccountant = a
+}
+
+func accountant() memstats.Accountant {
+	accountantMu.RLock()
+	defer accountantMu.RUnlock()
+	return activeAccountant
+}
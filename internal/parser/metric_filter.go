@@ -0,0 +1,49 @@
+package parser
+
+// MetricFlag is a bit in the exclude-metrics mask compiled from
+// config.Config.ExcludeMetrics. Handlers consult it with Has before doing
+// any regex work for the corresponding field, so a metric a user has
+// excluded costs nothing on the hot parse loop - not even a
+// FindStringSubmatch call - rather than being parsed and then discarded.
+type MetricFlag uint32
+
+// Flags correspond to the exclude_metrics config names in metricFlagNames.
+const (
+	MetricCPUPower MetricFlag = 1 << iota
+	MetricGPUPower
+	MetricANEPower
+	MetricDRAMPower
+	MetricSystemPower
+	MetricPerCPUInterrupts
+	MetricPerCPUIPIs
+	MetricPerCPUTimers
+)
+
+// metricFlagNames maps exclude_metrics config strings to their flag.
+var metricFlagNames = map[string]MetricFlag{
+	"cpu_power":          MetricCPUPower,
+	"gpu_power":          MetricGPUPower,
+	"ane_power":          MetricANEPower,
+	"dram_power":         MetricDRAMPower,
+	"system_power":       MetricSystemPower,
+	"per_cpu_interrupts": MetricPerCPUInterrupts,
+	"per_cpu_ipis":       MetricPerCPUIPIs,
+	"per_cpu_timers":     MetricPerCPUTimers,
+}
+
+// CompileMetricFilter turns a config exclude_metrics list into the bitmask
+// ParserContext.ExcludedMetrics tests with Has. Unknown names are ignored
+// rather than rejected, so a config written against a newer release still
+// starts, just without excluding the metric it doesn't recognize yet.
+func CompileMetricFilter(excluded []string) MetricFlag {
+	var mask MetricFlag
+	for _, name := range excluded {
+		mask |= metricFlagNames[name]
+	}
+	return mask
+}
+
+// Has reports whether flag is set in the mask.
+func (m MetricFlag) Has(flag MetricFlag) bool {
+	return m&flag != 0
+}
@@ -5,18 +5,20 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"powermetrics-tui/internal/config"
 	"powermetrics-tui/internal/models"
 )
 
 var (
 	// New format: interrupts/sec
-	ipiRateRegex    = regexp.MustCompile(`\|-> IPI:\s+([0-9.]+)\s+interrupts/sec`)
-	timerRateRegex  = regexp.MustCompile(`\|-> TIMER:\s+([0-9.]+)\s+interrupts/sec`)
-	totalRateRegex  = regexp.MustCompile(`Total IRQ:\s+([0-9.]+)\s+interrupts/sec`)
+	ipiRateRegex   = regexp.MustCompile(`\|-> IPI:\s+([0-9.]+)\s+interrupts/sec`)
+	timerRateRegex = regexp.MustCompile(`\|-> TIMER:\s+([0-9.]+)\s+interrupts/sec`)
+	totalRateRegex = regexp.MustCompile(`Total IRQ:\s+([0-9.]+)\s+interrupts/sec`)
 
 	// Per-CPU interrupt patterns
 	cpuInterruptRegex = regexp.MustCompile(`^CPU (\d+):$`)
@@ -31,18 +33,26 @@ var (
 	dramPowerRegex   = regexp.MustCompile(`(?:DRAM Power|DRAM Energy|Combined Power \(DRAM\)):\s+([0-9.]+)\s*mW`)
 	systemPowerRegex = regexp.MustCompile(`(?:Combined Power|System Power|System Average).*?:\s+([0-9.]+)\s*(?:mW|Watts)`)
 	// Thermal pattern - Updated for actual format: "Current pressure level: Nominal"
-	thermalRegex     = regexp.MustCompile(`Current pressure level:\s+(\w+)`)
-	tempRegex        = regexp.MustCompile(`([^:]+):\s+([0-9.]+)\s*(?:C|°C)`)
-	batteryRegex     = regexp.MustCompile(`(?:Battery charge|State of Charge|percent_charge):\s+([0-9.]+)(?:%)?`)
+	thermalRegex      = regexp.MustCompile(`Current pressure level:\s+(\w+)`)
+	tempRegex         = regexp.MustCompile(`([^:]+):\s+([0-9.]+)\s*(?:C|°C)`)
+	batteryRegex      = regexp.MustCompile(`(?:Battery charge|State of Charge|percent_charge):\s+([0-9.]+)(?:%)?`)
 	batteryStateRegex = regexp.MustCompile(`Battery state:\s+(\w+)`)
+	backlightRegex    = regexp.MustCompile(`Display backlight level:\s+(\d+)`)
 
 	// CPU frequency patterns (various formats)
 	ecoreFreqRegex = regexp.MustCompile(`E-Cluster HW active frequency:\s+([0-9]+)\s*MHz`)
-	pcoreFreqRegex = regexp.MustCompile(`P\d*-Cluster HW active frequency:\s+([0-9]+)\s*MHz`)  // Matches P0-Cluster, P1-Cluster, P-Cluster
+	pcoreFreqRegex = regexp.MustCompile(`P\d*-Cluster HW active frequency:\s+([0-9]+)\s*MHz`) // Matches P0-Cluster, P1-Cluster, P-Cluster
 	gpuFreqRegex   = regexp.MustCompile(`(?:GPU active frequency|GPU frequency):\s+([0-9]+)\s*MHz`)
+	gpuActiveRegex = regexp.MustCompile(`GPU Active residency:\s+([0-9.]+)%`)
 
 	// Per-CPU frequency
-	cpuFreqRegex   = regexp.MustCompile(`CPU (\d+) frequency:\s+([0-9]+)\s*MHz`)
+	cpuFreqRegex = regexp.MustCompile(`CPU (\d+) frequency:\s+([0-9]+)\s*MHz`)
+
+	// Idle residency patterns: per-CPU active residency (the complement of
+	// idle+down) and per-cluster down residency (the cluster-wide
+	// power-gated state, distinct from any one core's own idle time).
+	perCoreActiveResidencyRegex = regexp.MustCompile(`CPU (\d+) active residency:\s+([0-9.]+)%`)
+	clusterDownResidencyRegex   = regexp.MustCompile(`(\S+-Cluster) down residency:\s+([0-9.]+)%`)
 
 	// Network patterns
 	// Network patterns - Updated for actual format: "in: 70.77 packets/s, 69338.38 bytes/s"
@@ -87,7 +97,7 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 
 	inProcessSection := false
 	currentCoalition := (*models.ProcessCoalition)(nil) // Track current coalition being parsed
-	currentCPU := ""  // Track which CPU we're parsing interrupts for
+	currentCPU := ""                                    // Track which CPU we're parsing interrupts for
 
 	// Initialize maps if needed
 	if state.AllSeenCPUs == nil {
@@ -149,36 +159,44 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 			continue
 		}
 
-		// Parse interrupts - new format (interrupts/sec)
-		if matches := ipiRateRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				ipiTotal += val
-				// If we have a current CPU, track per-CPU data
-				if currentCPU != "" {
-					state.PerCPUIPIs[currentCPU] = val
-					state.AllSeenCPUs[currentCPU] = true
+		// Parse interrupts - new format (interrupts/sec). Each rate is
+		// skipped entirely when excluded via --config exclude_metrics, so a
+		// disabled metric never reaches FindStringSubmatch.
+		if !state.MetricExcluded("per_cpu_ipis") {
+			if matches := ipiRateRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					ipiTotal += val
+					// If we have a current CPU, track per-CPU data
+					if currentCPU != "" {
+						state.PerCPUIPIs[currentCPU] = val
+						state.AllSeenCPUs[currentCPU] = true
+					}
 				}
 			}
 		}
 
-		if matches := timerRateRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				timerTotal += val
-				// If we have a current CPU, track per-CPU data
-				if currentCPU != "" {
-					state.PerCPUTimers[currentCPU] = val
-					state.AllSeenCPUs[currentCPU] = true
+		if !state.MetricExcluded("per_cpu_timers") {
+			if matches := timerRateRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					timerTotal += val
+					// If we have a current CPU, track per-CPU data
+					if currentCPU != "" {
+						state.PerCPUTimers[currentCPU] = val
+						state.AllSeenCPUs[currentCPU] = true
+					}
 				}
 			}
 		}
 
-		if matches := totalRateRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				interrupts += val
-				// If we have a current CPU, track per-CPU data
-				if currentCPU != "" {
-					state.PerCPUInterrupts[currentCPU] = val
-					state.AllSeenCPUs[currentCPU] = true
+		if !state.MetricExcluded("per_cpu_interrupts") {
+			if matches := totalRateRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					interrupts += val
+					// If we have a current CPU, track per-CPU data
+					if currentCPU != "" {
+						state.PerCPUInterrupts[currentCPU] = val
+						state.AllSeenCPUs[currentCPU] = true
+					}
 				}
 			}
 		}
@@ -202,38 +220,49 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 			}
 		}
 
-		// Parse power metrics
-		if matches := cpuPowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				state.CPUPower = val
+		// Parse power metrics. Each is skipped entirely when excluded via
+		// --config exclude_metrics.
+		if !state.MetricExcluded("cpu_power") {
+			if matches := cpuPowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					state.CPUPower = val
+				}
 			}
 		}
 
-		if matches := gpuPowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				state.GPUPower = val
+		if !state.MetricExcluded("gpu_power") {
+			if matches := gpuPowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					state.GPUPower = val
+				}
 			}
 		}
 
-		if matches := anePowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				state.ANEPower = val
+		if !state.SubsystemDisabled(config.SubsystemANE) && !state.MetricExcluded("ane_power") {
+			if matches := anePowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					state.ANEPower = val
+				}
 			}
 		}
 
-		if matches := dramPowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				state.DRAMPower = val
+		if !state.MetricExcluded("dram_power") {
+			if matches := dramPowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					state.DRAMPower = val
+				}
 			}
 		}
 
-		if matches := systemPowerRegex.FindStringSubmatch(line); matches != nil {
-			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
-				// Convert watts to milliwatts if needed
-				if strings.Contains(line, "Watts") {
-					val *= 1000
+		if !state.MetricExcluded("system_power") {
+			if matches := systemPowerRegex.FindStringSubmatch(line); matches != nil {
+				if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
+					// Convert watts to milliwatts if needed
+					if strings.Contains(line, "Watts") {
+						val *= 1000
+					}
+					state.SystemPower = val
 				}
-				state.SystemPower = val
 			}
 		}
 
@@ -277,6 +306,23 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 			}
 		}
 
+		if matches := perCoreActiveResidencyRegex.FindStringSubmatch(line); matches != nil {
+			if cpuNum, err := strconv.Atoi(matches[1]); err == nil {
+				if val, err := strconv.ParseFloat(matches[2], 64); err == nil {
+					state.PerCoreActiveResidency[cpuNum] = val
+					residencyCap := state.PerCPUHistoryCap(30)
+					state.PerCoreResidencyHistory[cpuNum] = models.AddToHistory(
+						state.PerCoreResidencyHistory[cpuNum], val, residencyCap)
+				}
+			}
+		}
+
+		if matches := clusterDownResidencyRegex.FindStringSubmatch(line); matches != nil {
+			if val, err := strconv.ParseFloat(matches[2], 64); err == nil {
+				state.ClusterDownResidency[matches[1]] = val
+			}
+		}
+
 		// Parse network (bytes/s to KB/s)
 		if matches := networkInRegex.FindStringSubmatch(line); matches != nil {
 			if val, err := strconv.ParseFloat(matches[1], 64); err == nil {
@@ -374,7 +420,6 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 				cpuMs, _ := strconv.ParseFloat(matches[3], 64)
 				userPercent, _ := strconv.ParseFloat(matches[4], 64)
 
-
 				// Convert CPU ms/s to percentage (approximate)
 				cpuPercent := cpuMs / 10.0
 
@@ -509,7 +554,6 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 		currentCoalitionIDs[coalition.CoalitionID] = true
 	}
 
-
 	// Check for processes that are no longer present
 	// IMPORTANT: Only track SUBPROCESS PIDs as exited, not coalition IDs
 	for pid := range state.LastSeenPIDs {
@@ -673,13 +717,19 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 
 	// Update the processes and coalitions lists with the new data
 	state.Processes = newProcesses
-	state.Coalitions = newCoalitions
+	if !state.SubsystemDisabled(config.SubsystemCoalitions) {
+		state.Coalitions = newCoalitions
+	} else {
+		state.Coalitions = nil
+	}
 
 	// Organize CPU frequencies based on what we detected
-	organizeCPUFrequencies(state)
+	ecoreCPUs, pcoreCPUs := organizeCPUFrequencies(state)
 
 	// CPU frequency history is now updated in organizeCPUFrequencies
 
+	buildCoreOccupancy(state, ecoreCPUs, pcoreCPUs)
+
 	// Update interrupt totals (convert float rates to int for display)
 	if ipiTotal > 0 {
 		state.IPICount = int(ipiTotal)
@@ -691,27 +741,65 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 		state.TotalInterrupts = int(interrupts)
 	}
 
-	// Update per-CPU interrupt history for all known CPUs
-	// Since we reset all CPUs to 0 at the start, this will include zeros for missing CPUs
-	for cpu := range state.AllSeenCPUs {
-		total := state.PerCPUInterrupts[cpu] // Will be 0 if CPU wasn't in this sample
-		if state.PerCPUInterruptHistory[cpu] == nil {
-			state.PerCPUInterruptHistory[cpu] = make([]float64, 0, 30)
+	// Update per-CPU interrupt history for all known CPUs. Since we reset
+	// all CPUs to 0 at the start, a CPU missing from this sample reads as
+	// 0 here - which, for a CPU that was reporting a steady rate moments
+	// ago, looks like a huge drop in derived views rather than the
+	// transient miss (CPU briefly offlined, a powermetrics hiccup) it
+	// usually is. Treat a 0 as that kind of reset - and carry forward the
+	// last recorded value instead - unless the CPU has actually been
+	// missing longer than interruptResetThreshold, in which case 0 is
+	// taken at face value. Skipped entirely when --config disables
+	// per_cpu_interrupts, since that's the whole point of disabling it:
+	// no more growth of a map keyed by every CPU ever seen.
+	if !state.SubsystemDisabled(config.SubsystemPerCPUInterrupts) {
+		now := time.Now()
+		perCPUCap := state.PerCPUHistoryCap(30)
+		if state.PerCPUInterruptsLastSeen == nil {
+			state.PerCPUInterruptsLastSeen = make(map[string]time.Time)
+		}
+		for cpu := range state.AllSeenCPUs {
+			total := state.PerCPUInterrupts[cpu] // Will be 0 if CPU wasn't in this sample
+			if total != 0 {
+				state.PerCPUInterruptsLastSeen[cpu] = now
+			} else {
+				if lastSeen, ok := state.PerCPUInterruptsLastSeen[cpu]; ok && now.Sub(lastSeen) <= interruptResetThreshold {
+					if hist := state.PerCPUInterruptHistory[cpu]; len(hist) > 0 {
+						total = hist[len(hist)-1]
+						if debugFile, err := os.OpenFile("/tmp/powermetrics-debug.log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644); err == nil {
+							debugFile.WriteString(fmt.Sprintf("[%s] INFO: cpu %s interrupt rate dropped to 0 within %s of last report; carrying forward previous value\n",
+								time.Now().Format("15:04:05"), cpu, interruptResetThreshold))
+							debugFile.Close()
+						}
+					}
+				}
+			}
+			if state.PerCPUInterruptHistory[cpu] == nil {
+				state.PerCPUInterruptHistory[cpu] = make([]float64, 0, perCPUCap)
+			}
+			state.PerCPUInterruptHistory[cpu] = models.AddToHistory(state.PerCPUInterruptHistory[cpu], total, perCPUCap)
 		}
-		state.PerCPUInterruptHistory[cpu] = models.AddToHistory(state.PerCPUInterruptHistory[cpu], total, 30)
 	}
 
-	// Update history
+	// Update history. Each subsystem's slice is skipped when --config names
+	// it in disable, so it stops growing (and the TUI pane fed by it goes
+	// empty) instead of accumulating samples nobody asked for.
 	state.History.IPIHistory = models.AddToIntHistory(state.History.IPIHistory, state.IPICount, state.History.MaxHistory)
 	state.History.TimerHistory = models.AddToIntHistory(state.History.TimerHistory, state.TimerCount, state.History.MaxHistory)
 	state.History.TotalHistory = models.AddToIntHistory(state.History.TotalHistory, state.TotalInterrupts, state.History.MaxHistory)
 	state.History.CPUPowerHistory = models.AddToHistory(state.History.CPUPowerHistory, state.CPUPower, state.History.MaxHistory)
-	state.History.GPUPowerHistory = models.AddToHistory(state.History.GPUPowerHistory, state.GPUPower, state.History.MaxHistory)
+	if !state.SubsystemDisabled(config.SubsystemGPU) {
+		state.History.GPUPowerHistory = models.AddToHistory(state.History.GPUPowerHistory, state.GPUPower, state.History.MaxHistory)
+	}
 	state.History.SystemHistory = models.AddToHistory(state.History.SystemHistory, state.SystemPower, state.History.MaxHistory)
-	state.History.NetworkInHistory = models.AddToHistory(state.History.NetworkInHistory, state.NetworkIn, state.History.MaxHistory)
-	state.History.NetworkOutHistory = models.AddToHistory(state.History.NetworkOutHistory, state.NetworkOut, state.History.MaxHistory)
-	state.History.DiskReadHistory = models.AddToHistory(state.History.DiskReadHistory, state.DiskRead, state.History.MaxHistory)
-	state.History.DiskWriteHistory = models.AddToHistory(state.History.DiskWriteHistory, state.DiskWrite, state.History.MaxHistory)
+	if !state.SubsystemDisabled(config.SubsystemNetwork) {
+		state.History.NetworkInHistory = models.AddToHistory(state.History.NetworkInHistory, state.NetworkIn, state.History.MaxHistory)
+		state.History.NetworkOutHistory = models.AddToHistory(state.History.NetworkOutHistory, state.NetworkOut, state.History.MaxHistory)
+	}
+	if !state.SubsystemDisabled(config.SubsystemDisk) {
+		state.History.DiskReadHistory = models.AddToHistory(state.History.DiskReadHistory, state.DiskRead, state.History.MaxHistory)
+		state.History.DiskWriteHistory = models.AddToHistory(state.History.DiskWriteHistory, state.DiskWrite, state.History.MaxHistory)
+	}
 	state.History.BatteryHistory = models.AddToHistory(state.History.BatteryHistory, state.BatteryCharge, state.History.MaxHistory)
 	state.History.MemoryHistory = models.AddToHistory(state.History.MemoryHistory, state.MemoryUsed, state.History.MaxHistory)
 
@@ -726,6 +814,12 @@ func ParsePowerMetricsOutput(output string, state *models.MetricsState) {
 	}
 }
 
+// interruptResetThreshold bounds how long a CPU can be missing from a
+// sample's interrupt section before a reported 0 is treated as a genuine
+// reading (CPU actually offline) rather than a transient reset artifact to
+// carry forward over. See the per-CPU interrupt history loop above.
+const interruptResetThreshold = 5 * time.Second
+
 func convertToMB(value float64, line string) float64 {
 	if strings.Contains(line, "KB") {
 		return value / 1024
@@ -737,10 +831,14 @@ func convertToMB(value float64, line string) float64 {
 	return value
 }
 
-// organizeCPUFrequencies categorizes CPUs based on cluster information parsed from powermetrics
-func organizeCPUFrequencies(state *models.MetricsState) {
+// organizeCPUFrequencies categorizes CPUs based on cluster information
+// parsed from powermetrics. It returns the absolute CPU indices it placed
+// in each cluster (post --config cpu_filter), so callers like
+// buildCoreOccupancy can reuse the same E/P membership instead of
+// re-deriving it from AllSeenCPUs.
+func organizeCPUFrequencies(state *models.MetricsState) (ecoreCPUsOut, pcoreCPUsOut []int) {
 	if state.AllCpuFreq == nil || len(state.AllCpuFreq) == 0 {
-		return
+		return nil, nil
 	}
 
 	// Separate CPUs based on cluster membership information
@@ -806,6 +904,19 @@ func organizeCPUFrequencies(state *models.MetricsState) {
 		}
 	}
 
+	// Respect --config's cpu_filter: an empty list here means the
+	// corresponding pane below gets no data and the history for cores of
+	// that type simply stops growing, without touching the cluster
+	// detection above.
+	if !state.CPUFilterECores {
+		ecoreCPUs = nil
+	}
+	if !state.CPUFilterPCores {
+		pcoreCPUs = nil
+	}
+
+	freqHistoryCap := state.PerCPUHistoryCap(30)
+
 	// Build E-core frequencies
 	newECores := make([]int, 0, len(ecoreCPUs))
 	for i, cpuID := range ecoreCPUs {
@@ -817,10 +928,10 @@ func organizeCPUFrequencies(state *models.MetricsState) {
 
 		// Update history
 		if state.ECoreFreqHistory[i] == nil {
-			state.ECoreFreqHistory[i] = make([]float64, 0, 30)
+			state.ECoreFreqHistory[i] = make([]float64, 0, freqHistoryCap)
 		}
 		state.ECoreFreqHistory[i] = models.AddToHistory(
-			state.ECoreFreqHistory[i], float64(freq), 30)
+			state.ECoreFreqHistory[i], float64(freq), freqHistoryCap)
 	}
 
 	// Build P-core frequencies
@@ -834,10 +945,10 @@ func organizeCPUFrequencies(state *models.MetricsState) {
 
 		// Update history
 		if state.PCoreFreqHistory[i] == nil {
-			state.PCoreFreqHistory[i] = make([]float64, 0, 30)
+			state.PCoreFreqHistory[i] = make([]float64, 0, freqHistoryCap)
 		}
 		state.PCoreFreqHistory[i] = models.AddToHistory(
-			state.PCoreFreqHistory[i], float64(freq), 30)
+			state.PCoreFreqHistory[i], float64(freq), freqHistoryCap)
 	}
 
 	// Update state
@@ -852,5 +963,129 @@ func organizeCPUFrequencies(state *models.MetricsState) {
 		state.MaxPCores = len(state.PCoreFreq)
 	}
 
+	state.ClusterFreqHistogram["E-Cluster"] = buildFreqHistogram(newECores)
+	state.ClusterFreqHistogram["P-Cluster"] = buildFreqHistogram(newPCores)
+
 	// Keep the AllCpuFreq for reference/debugging
-}
\ No newline at end of file
+
+	return ecoreCPUs, pcoreCPUs
+}
+
+// buildFreqHistogram buckets freqs (MHz) into models.ClusterFreqHistogramBins
+// roughly-equal-width bins spanning [min(freqs), max(freqs)], for the Idle
+// Residency view's per-cluster frequency distribution. Returns nil if freqs
+// is empty so an idle/absent cluster draws no histogram rather than one
+// bin full of zeros.
+func buildFreqHistogram(freqs []int) []int {
+	if len(freqs) == 0 {
+		return nil
+	}
+
+	min, max := freqs[0], freqs[0]
+	for _, f := range freqs {
+		if f < min {
+			min = f
+		}
+		if f > max {
+			max = f
+		}
+	}
+
+	bins := make([]int, models.ClusterFreqHistogramBins)
+	span := max - min
+	if span == 0 {
+		bins[0] = len(freqs)
+		return bins
+	}
+	for _, f := range freqs {
+		bucket := (f - min) * models.ClusterFreqHistogramBins / (span + 1)
+		bins[bucket]++
+	}
+	return bins
+}
+
+// buildCoreOccupancy rebuilds this sample's CoreOccupancyHistory entry for
+// every core in ecoreCPUs/pcoreCPUs. Powermetrics reports each coalition's
+// total CPU ms/s but not which core(s) it actually ran on, so each cluster
+// is handled independently by bin-packing: coalitions are sorted by CPU ms
+// (largest first) and cores by active residency (busiest first), then each
+// core's share of the cluster's total ms is carved off the front of the
+// coalition queue, advancing to the next coalition once the current one's
+// ms is exhausted. This is a plausible approximation of scheduler behavior,
+// not a measurement - see CoreOccupancyHistory's doc comment.
+func buildCoreOccupancy(state *models.MetricsState, ecoreCPUs, pcoreCPUs []int) {
+	if len(state.Coalitions) == 0 {
+		return
+	}
+	occupancyCap := state.PerCPUHistoryCap(20)
+	packCoalitionsOntoCluster(state, ecoreCPUs, occupancyCap)
+	packCoalitionsOntoCluster(state, pcoreCPUs, occupancyCap)
+}
+
+// coalitionMsRemaining tracks one coalition's not-yet-assigned CPU ms while
+// packCoalitionsOntoCluster walks a cluster's cores.
+type coalitionMsRemaining struct {
+	id        int
+	name      string
+	remaining float64
+}
+
+func packCoalitionsOntoCluster(state *models.MetricsState, cpus []int, occupancyCap int) {
+	if len(cpus) == 0 {
+		return
+	}
+
+	cores := append([]int(nil), cpus...)
+	sort.Slice(cores, func(i, j int) bool {
+		return state.PerCoreActiveResidency[cores[i]] > state.PerCoreActiveResidency[cores[j]]
+	})
+	totalResidency := 0.0
+	for _, cpu := range cores {
+		totalResidency += state.PerCoreActiveResidency[cpu]
+	}
+	if totalResidency <= 0 {
+		return
+	}
+
+	queue := make([]coalitionMsRemaining, 0, len(state.Coalitions))
+	totalMs := 0.0
+	for _, co := range state.Coalitions {
+		ms := co.CPUPercent * 10
+		if ms <= 0 {
+			continue
+		}
+		queue = append(queue, coalitionMsRemaining{id: co.CoalitionID, name: co.Name, remaining: ms})
+		totalMs += ms
+	}
+	if totalMs <= 0 {
+		return
+	}
+	sort.Slice(queue, func(i, j int) bool { return queue[i].remaining > queue[j].remaining })
+
+	qi := 0
+	for _, cpu := range cores {
+		budget := totalMs * (state.PerCoreActiveResidency[cpu] / totalResidency)
+
+		var dominant coalitionMsRemaining
+		if qi < len(queue) {
+			dominant = queue[qi]
+			queue[qi].remaining -= budget
+			if queue[qi].remaining <= 0 {
+				qi++
+			}
+		}
+
+		sample := models.CoreOccupancySample{
+			CoalitionID:   dominant.id,
+			CoalitionName: dominant.name,
+			Ms:            budget,
+			TotalMs:       totalMs,
+			FreqMHz:       state.AllCpuFreq[cpu],
+		}
+		history := append(state.CoreOccupancyHistory[cpu], sample)
+		if len(history) > occupancyCap {
+			history = history[len(history)-occupancyCap:]
+		}
+		state.CoreOccupancyHistory[cpu] = history
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strconv"
 	"strings"
 	"regexp"
+	"time"
 )
 
 // Utility functions for common parsing tasks
@@ -31,6 +32,25 @@ func IsNewSample(line string) bool {
 	return strings.Contains(line, "*** Sampled system activity")
 }
 
+// sampleElapsedRegex matches the "(N ms elapsed)" duration powermetrics
+// prints in its "*** Sampled system activity ***" header line.
+var sampleElapsedRegex = regexp.MustCompile(`\(([0-9.]+)\s*ms elapsed\)`)
+
+// ParseSampleElapsed extracts the header-reported duration of the sample
+// that just finished, when powermetrics printed one. Callers fall back to
+// a wall-clock diff between samples when ok is false.
+func ParseSampleElapsed(line string) (d time.Duration, ok bool) {
+	matches := sampleElapsedRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return 0, false
+	}
+	ms, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(ms * float64(time.Millisecond)), true
+}
+
 // IsRunningTasks checks if a line indicates the running tasks section
 func IsRunningTasks(line string) bool {
 	return strings.Contains(line, "*** Running tasks ***")
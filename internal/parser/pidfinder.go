@@ -0,0 +1,33 @@
+package parser
+
+import (
+	"sync"
+
+	"powermetrics-tui/internal/procfind"
+)
+
+// activePIDFinder is the procfind.Finder RunningTasksHandler consults (via
+// pidFinder) to enrich a dead/exited PID's placeholder name instead of
+// forking ps per PID. Defaults to procfind.New("")'s gopsutil-backed
+// Finder, so callers that never touch SetPIDFinder (e.g. existing tests)
+// still get real enrichment rather than a nil-Finder special case at every
+// call site. Guarded like internal/ui's activeScheme/activeCatalog, for the
+// same reason: main wires this once at startup, but nothing prevents a
+// future caller from changing it while a sample is mid-parse.
+var (
+	pidFinderMu     sync.RWMutex
+	activePIDFinder = procfind.New("")
+)
+
+// SetPIDFinder makes f the active Finder.
+func SetPIDFinder(f procfind.Finder) {
+	pidFinderMu.Lock()
+	defer pidFinderMu.Unlock()
+	activePIDFinder = f
+}
+
+func pidFinder() procfind.Finder {
+	pidFinderMu.RLock()
+	defer pidFinderMu.RUnlock()
+	return activePIDFinder
+}
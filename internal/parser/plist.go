@@ -0,0 +1,326 @@
+package parser
+
+import (
+	"time"
+
+	"howett.net/plist"
+
+	"powermetrics-tui/internal/models"
+)
+
+// ParsePowerMetricsPlist parses one sample of `powermetrics -f plist -o
+// /dev/stdout` output and merges it into state. Unlike ParsePowerMetricsOutput,
+// which scrapes powermetrics' human-readable text with a large collection of
+// regexes that have already drifted across macOS releases (see the "New
+// format"/"Old format" comments in parser.go), the plist backend gives stable,
+// versioned field names straight from powermetrics' own serialization, so
+// this function reads fields directly instead of pattern-matching lines.
+//
+// data must be a single plist document (powermetrics with -f plist writes
+// one `<plist>...</plist>` document per sample, back to back); callers
+// streaming multiple samples must split on plist document boundaries before
+// calling this.
+func ParsePowerMetricsPlist(data []byte, state *models.MetricsState) error {
+	var sample map[string]interface{}
+	if _, err := plist.Unmarshal(data, &sample); err != nil {
+		return err
+	}
+
+	state.Mu.Lock()
+	defer state.Mu.Unlock()
+
+	if state.AllSeenCPUs == nil {
+		state.AllSeenCPUs = make(map[string]bool)
+	}
+	if state.PerCPUInterrupts == nil {
+		state.PerCPUInterrupts = make(map[string]float64)
+	}
+	if state.PerCPUIPIs == nil {
+		state.PerCPUIPIs = make(map[string]float64)
+	}
+	if state.PerCPUTimers == nil {
+		state.PerCPUTimers = make(map[string]float64)
+	}
+	for cpu := range state.AllSeenCPUs {
+		state.PerCPUInterrupts[cpu] = 0
+		state.PerCPUIPIs[cpu] = 0
+		state.PerCPUTimers[cpu] = 0
+	}
+
+	eCoreCPUs, pCoreCPUs := parsePlistProcessor(sample, state)
+	parsePlistInterrupts(sample, state)
+	parsePlistNetwork(sample, state)
+	parsePlistDisk(sample, state)
+	parsePlistThermal(sample, state)
+	parsePlistBattery(sample, state)
+	parsePlistMemory(sample, state)
+	state.Processes, state.Coalitions = parsePlistTasks(sample)
+	buildCoreOccupancy(state, eCoreCPUs, pCoreCPUs)
+
+	state.LastUpdate = time.Now()
+	return nil
+}
+
+// parsePlistProcessor fills the power and frequency fields from the
+// top-level "processor" dict. It returns the absolute CPU indices it placed
+// in each cluster, for ParsePowerMetricsPlist to pass to buildCoreOccupancy
+// once coalitions have also been parsed for this sample.
+func parsePlistProcessor(sample map[string]interface{}, state *models.MetricsState) (eCoreCPUsOut, pCoreCPUsOut []int) {
+	proc, ok := plistDict(sample, "processor")
+	if !ok {
+		return nil, nil
+	}
+
+	// Each field is skipped when excluded via --config exclude_metrics,
+	// the same names ParsePowerMetricsOutput checks.
+	if !state.MetricExcluded("cpu_power") {
+		state.CPUPower = plistFloat(proc, "cpu_power")
+	}
+	if !state.MetricExcluded("gpu_power") {
+		state.GPUPower = plistFloat(proc, "gpu_power")
+	}
+	if !state.MetricExcluded("ane_power") {
+		state.ANEPower = plistFloat(proc, "ane_power")
+	}
+	if !state.MetricExcluded("dram_power") {
+		state.DRAMPower = plistFloat(proc, "dram_power")
+	}
+	if !state.MetricExcluded("system_power") {
+		state.SystemPower = plistFloat(proc, "combined_power")
+	}
+
+	var eCore, pCore []int
+	var eCoreCPUFreqs, pCoreCPUFreqs []int
+	var eCoreCPUs, pCoreCPUs []int
+	if state.AllCpuFreq == nil {
+		state.AllCpuFreq = make(map[int]int)
+	}
+	freqCap := state.PerCPUHistoryCap(30)
+	cpuIndex := 0 // absolute CPU index, counted across clusters in order
+	for _, cluster := range plistSlice(proc, "clusters") {
+		c, ok := cluster.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		freq := int(plistFloat(c, "freq_hz") / 1_000_000)
+		name := plistString(c, "name")
+		isECore := name == "E-Cluster" || freq == 0
+		if isECore {
+			eCore = append(eCore, freq)
+		} else {
+			pCore = append(pCore, freq)
+		}
+
+		for _, cpu := range plistSlice(c, "cpus") {
+			i := cpuIndex
+			cpuIndex++
+			cc, ok := cpu.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			cpuFreq := int(plistFloat(cc, "freq_hz") / 1_000_000)
+			state.AllCpuFreq[i] = cpuFreq
+			if isECore {
+				eCoreCPUFreqs = append(eCoreCPUFreqs, cpuFreq)
+				eCoreCPUs = append(eCoreCPUs, i)
+			} else {
+				pCoreCPUFreqs = append(pCoreCPUFreqs, cpuFreq)
+				pCoreCPUs = append(pCoreCPUs, i)
+			}
+
+			active := plistFloat(cc, "active_ratio") * 100
+			state.PerCoreActiveResidency[i] = active
+			state.PerCoreResidencyHistory[i] = models.AddToHistory(
+				state.PerCoreResidencyHistory[i], active, freqCap)
+		}
+		if name != "" {
+			state.ClusterDownResidency[name] = plistFloat(c, "down_ratio") * 100
+		}
+	}
+	// Bucket into "E-Cluster"/"P-Cluster" the same way organizeCPUFrequencies
+	// does for the text-format backend, so multi-die/multi-P-cluster Macs
+	// (e.g. two "P0-Cluster"/"P1-Cluster" dicts) get one merged histogram per
+	// core type instead of a histogram per physical cluster.
+	state.ClusterFreqHistogram["E-Cluster"] = buildFreqHistogram(eCoreCPUFreqs)
+	state.ClusterFreqHistogram["P-Cluster"] = buildFreqHistogram(pCoreCPUFreqs)
+	state.ECoreFreq = eCore
+	state.PCoreFreq = pCore
+	state.GPUFreq = int(plistFloat(proc, "gpu_freq_hz") / 1_000_000)
+
+	return eCoreCPUs, pCoreCPUs
+}
+
+// parsePlistInterrupts fills the per-CPU and aggregate IPI/Timer/total
+// interrupt rates from the "interrupts" dict, keyed by CPU name (e.g.
+// "CPU0") the same way ParsePowerMetricsOutput keys PerCPUIPIs.
+func parsePlistInterrupts(sample map[string]interface{}, state *models.MetricsState) {
+	var ipiTotal, timerTotal, allTotal float64
+	for _, cpu := range plistSlice(sample, "interrupts") {
+		c, ok := cpu.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name := plistString(c, "name")
+		if name == "" {
+			continue
+		}
+		ipi := plistFloat(c, "IPI")
+		timer := plistFloat(c, "TIMER")
+		total := plistFloat(c, "Total")
+
+		if !state.MetricExcluded("per_cpu_ipis") {
+			state.PerCPUIPIs[name] = ipi
+		}
+		if !state.MetricExcluded("per_cpu_timers") {
+			state.PerCPUTimers[name] = timer
+		}
+		if !state.MetricExcluded("per_cpu_interrupts") {
+			state.PerCPUInterrupts[name] = total
+		}
+		state.AllSeenCPUs[name] = true
+
+		ipiTotal += ipi
+		timerTotal += timer
+		allTotal += total
+	}
+
+	state.IPICount = int(ipiTotal)
+	state.TimerCount = int(timerTotal)
+	state.TotalInterrupts = int(allTotal)
+}
+
+func parsePlistNetwork(sample map[string]interface{}, state *models.MetricsState) {
+	net, ok := plistDict(sample, "network")
+	if !ok {
+		return
+	}
+	state.NetworkIn = plistFloat(net, "ibyte_rate")
+	state.NetworkOut = plistFloat(net, "obyte_rate")
+}
+
+func parsePlistDisk(sample map[string]interface{}, state *models.MetricsState) {
+	disk, ok := plistDict(sample, "disk")
+	if !ok {
+		return
+	}
+	state.DiskRead = plistFloat(disk, "rbytes_per_s") / 1024
+	state.DiskWrite = plistFloat(disk, "wbytes_per_s") / 1024
+}
+
+func parsePlistThermal(sample map[string]interface{}, state *models.MetricsState) {
+	if pressure := plistString(sample, "thermal_pressure"); pressure != "" {
+		state.ThermalPressure = pressure
+	}
+	if state.Temperature == nil {
+		state.Temperature = make(map[string]float64)
+	}
+	for name, value := range plistDictRaw(sample, "temperature") {
+		if f, ok := value.(float64); ok {
+			state.Temperature[name] = f
+		}
+	}
+}
+
+func parsePlistBattery(sample map[string]interface{}, state *models.MetricsState) {
+	battery, ok := plistDict(sample, "battery")
+	if !ok {
+		return
+	}
+	state.BatteryCharge = plistFloat(battery, "percent_charge")
+	state.BatteryState = plistString(battery, "state")
+}
+
+func parsePlistMemory(sample map[string]interface{}, state *models.MetricsState) {
+	mem, ok := plistDict(sample, "memory")
+	if !ok {
+		return
+	}
+	state.MemoryUsed = plistFloat(mem, "used_mb")
+	state.MemoryAvailable = plistFloat(mem, "available_mb")
+	state.SwapUsed = plistFloat(mem, "swap_used_mb")
+}
+
+// parsePlistTasks builds the process and coalition lists from the
+// top-level "coalitions" array, each with a nested "tasks" array, mirroring
+// the coalition/subprocess shape ParsePowerMetricsOutput builds from the
+// "Running tasks" text section.
+func parsePlistTasks(sample map[string]interface{}) ([]models.ProcessInfo, []models.ProcessCoalition) {
+	var processes []models.ProcessInfo
+	var coalitions []models.ProcessCoalition
+
+	for _, raw := range plistSlice(sample, "coalitions") {
+		co, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		coalition := models.ProcessCoalition{
+			CoalitionID: int(plistFloat(co, "id")),
+			ParentID:    models.RootCoalitionID,
+			Name:        plistString(co, "name"),
+		}
+
+		for _, rawTask := range plistSlice(co, "tasks") {
+			task, ok := rawTask.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			proc := models.ProcessInfo{
+				PID:            int(plistFloat(task, "pid")),
+				Name:           plistString(task, "name"),
+				CoalitionName:  coalition.Name,
+				CPUPercent:     plistFloat(task, "cputime_ms_per_s") / 10,
+				Wakeups:        plistFloat(task, "interrupt_wakeups") + plistFloat(task, "idle_wakeups"),
+				GPUMS:          plistFloat(task, "gpu_time_ms_per_s"),
+				EnergyEstimate: plistFloat(task, "energy_impact"),
+			}
+			coalition.CPUPercent += proc.CPUPercent
+			coalition.Subprocesses = append(coalition.Subprocesses, proc)
+			processes = append(processes, proc)
+		}
+
+		coalitions = append(coalitions, coalition)
+	}
+
+	return processes, coalitions
+}
+
+// plistDict returns sample[key] as a dict, or ok=false if absent/wrong type.
+func plistDict(sample map[string]interface{}, key string) (map[string]interface{}, bool) {
+	d, ok := sample[key].(map[string]interface{})
+	return d, ok
+}
+
+// plistDictRaw is plistDict without the ok return, for callers that only
+// range over the result.
+func plistDictRaw(sample map[string]interface{}, key string) map[string]interface{} {
+	d, _ := plistDict(sample, key)
+	return d
+}
+
+// plistSlice returns sample[key] as a slice, or nil if absent/wrong type.
+func plistSlice(sample map[string]interface{}, key string) []interface{} {
+	s, _ := sample[key].([]interface{})
+	return s
+}
+
+// plistFloat returns sample[key] as a float64, or 0 if absent/wrong type.
+// powermetrics' plist encoder emits both real and integer numbers, so this
+// accepts either.
+func plistFloat(sample map[string]interface{}, key string) float64 {
+	switch v := sample[key].(type) {
+	case float64:
+		return v
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	default:
+		return 0
+	}
+}
+
+// plistString returns sample[key] as a string, or "" if absent/wrong type.
+func plistString(sample map[string]interface{}, key string) string {
+	s, _ := sample[key].(string)
+	return s
+}
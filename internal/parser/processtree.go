@@ -0,0 +1,127 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// ProcessSnapshot is one `ps` row: everything powermetrics' task section
+// doesn't report about a PID (who its parent is, who owns it, its
+// command line, its run state), plus `ps`' own CPU%/RSS figures - coarser
+// than powermetrics' own accounting, but the only numbers available for a
+// descendant powermetrics never reported on its own (see
+// RunningTasksHandler.updateFollowChildren).
+type ProcessSnapshot struct {
+	PID        int
+	PPID       int
+	UID        int
+	Command    string
+	State      string
+	CPUPercent float64
+	MemoryMB   float64
+}
+
+// ProcessTree is a single `ps` snapshot indexed by PID, doubling as both a
+// liveness check (a PID missing from the map is dead) and a PPID-keyed
+// parent/child index, built once per sample instead of shelling out to
+// `ps -p <pid>` separately for every PID that looks like it might have
+// exited.
+type ProcessTree struct {
+	byPID    map[int]ProcessSnapshot
+	children map[int][]int
+}
+
+// SnapshotProcessTree runs a single batched
+// `ps -Ao pid=,ppid=,uid=,state=,pcpu=,rss=,comm=` and returns the
+// resulting ProcessTree. Callers reuse the one snapshot for the "is this
+// PID actually dead" check, the parent→children tree, and (via
+// CPUPercent/MemoryMB) follow-children rollup, rather than forking `ps`
+// once per candidate PID every sample.
+func SnapshotProcessTree() (*ProcessTree, error) {
+	out, err := exec.Command("ps", "-Ao", "pid=,ppid=,uid=,state=,pcpu=,rss=,comm=").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &ProcessTree{
+		byPID:    make(map[int]ProcessSnapshot),
+		children: make(map[int][]int),
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		snap, ok := parsePSLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		tree.byPID[snap.PID] = snap
+		tree.children[snap.PPID] = append(tree.children[snap.PPID], snap.PID)
+	}
+
+	return tree, nil
+}
+
+// parsePSLine parses one "pid ppid uid state pcpu rss comm" row. comm may
+// itself contain spaces (e.g. "Google Chrome Helper"), so it's everything
+// after the first six fields rather than a seventh Fields() entry.
+func parsePSLine(line string) (ProcessSnapshot, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 7 {
+		return ProcessSnapshot{}, false
+	}
+
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return ProcessSnapshot{}, false
+	}
+	ppid, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return ProcessSnapshot{}, false
+	}
+	uid, err := strconv.Atoi(fields[2])
+	if err != nil {
+		return ProcessSnapshot{}, false
+	}
+	cpuPercent, _ := strconv.ParseFloat(fields[4], 64)
+	rssKB, _ := strconv.ParseFloat(fields[5], 64)
+
+	return ProcessSnapshot{
+		PID:        pid,
+		PPID:       ppid,
+		UID:        uid,
+		State:      fields[3],
+		CPUPercent: cpuPercent,
+		MemoryMB:   rssKB / 1024,
+		Command:    strings.Join(fields[6:], " "),
+	}, true
+}
+
+// Alive reports whether pid appeared in this snapshot.
+func (t *ProcessTree) Alive(pid int) bool {
+	if t == nil {
+		return false
+	}
+	_, ok := t.byPID[pid]
+	return ok
+}
+
+// Lookup returns pid's ProcessSnapshot, if this snapshot saw it.
+func (t *ProcessTree) Lookup(pid int) (ProcessSnapshot, bool) {
+	if t == nil {
+		return ProcessSnapshot{}, false
+	}
+	snap, ok := t.byPID[pid]
+	return snap, ok
+}
+
+// Children returns the PIDs whose PPID is pid, e.g. passing launchd's PID
+// walks straight to every process it spawned.
+func (t *ProcessTree) Children(pid int) []int {
+	if t == nil {
+		return nil
+	}
+	return t.children[pid]
+}
@@ -3,8 +3,14 @@ package parser
 import (
 	"fmt"
 	"regexp"
+	"time"
 
+	"powermetrics-tui/internal/alerts"
 	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/proccache"
+	"powermetrics-tui/internal/proctrack"
+	"powermetrics-tui/internal/promexport"
+	"powermetrics-tui/internal/selector"
 )
 
 // ParserState represents the current state of the powermetrics parser
@@ -98,6 +104,56 @@ type ParserContext struct {
 
 	// Reference to the metrics state being updated
 	MetricsState *models.MetricsState
+
+	// Alerts evaluates threshold rules once a sample is finalized. Nil
+	// when alerting hasn't been configured.
+	Alerts *alerts.Engine
+
+	// Exporter republishes the Prometheus snapshot once a sample is
+	// finalized. Nil when --metrics-listen hasn't been set.
+	Exporter *promexport.Exporter
+
+	// Tracker folds RunningTasksHandler's per-sample processes and
+	// coalitions into rolling-window history once a sample is finalized.
+	// Nil disables rollup tracking entirely.
+	Tracker *proctrack.Tracker
+
+	// Selector, if set, restricts RunningTasksHandler.Exit to only merge
+	// processes/coalitions it matches into MetricsState. Nil tracks
+	// everything, as before.
+	Selector *selector.Set
+
+	// LastSampleTime and PendingIntervalSeconds back
+	// WaitingForSampleHandler's interval tracking: the former is the wall
+	// clock time the previous sample header was seen, the latter is the
+	// duration computed for the sample that just finished (header-reported
+	// or wall-clock), consumed by accumulateCounters to integrate
+	// NetworkIn/Out and DiskRead/Write into byte-accurate totals.
+	LastSampleTime         time.Time
+	PendingIntervalSeconds float64
+
+	// ExcludedMetrics is the compiled --config exclude_metrics bitmask
+	// (see CompileMetricFilter). Zero value excludes nothing.
+	ExcludedMetrics MetricFlag
+
+	// ProcCache persists process metadata across restarts so ghost PIDs -
+	// entries in LastSeenPIDs with no name, because the prior run's state
+	// didn't survive - can be backfilled instead of purged. Nil disables
+	// persistence; updateProcessTracking falls back to purging as before.
+	ProcCache *proccache.Cache
+
+	// FollowChildren enables updateFollowChildren: walking each coalition's
+	// subprocess descendant tree every sample and rolling any CPU/memory
+	// powermetrics didn't already report for them into the coalition's
+	// totals. False leaves coalition totals exactly as powermetrics
+	// reported them, as before.
+	FollowChildren bool
+
+	// IncludeDeadChildren enables updateDeadChildrenCPU: adding each
+	// coalition leader's reaped-child CPU delta (via childcpu.Reader) to
+	// CoalitionCPUHistory, so a burst of children that died between two
+	// samples still shows up instead of only as <dead-process-N>.
+	IncludeDeadChildren bool
 }
 
 // NewParserContext creates a new parser context
@@ -293,6 +349,62 @@ func (sm *StateMachine) EnableDebug(enabled bool) {
 	sm.context.DebugEnabled = enabled
 }
 
+// SetAlertEngine wires a threshold-alerting engine into the state machine.
+// Once set, FinalizeCurrentState evaluates its rules against every
+// finalized sample.
+func (sm *StateMachine) SetAlertEngine(engine *alerts.Engine) {
+	sm.context.Alerts = engine
+}
+
+// SetExporter wires a Prometheus exporter into the state machine. Once set,
+// FinalizeCurrentState republishes its snapshot after every sample.
+func (sm *StateMachine) SetExporter(exporter *promexport.Exporter) {
+	sm.context.Exporter = exporter
+}
+
+// SetTracker wires a process/coalition rollup tracker into the state
+// machine. Once set, FinalizeCurrentState folds the sample RunningTasks
+// just produced into its rolling windows.
+func (sm *StateMachine) SetTracker(tracker *proctrack.Tracker) {
+	sm.context.Tracker = tracker
+}
+
+// SetSelector wires a process/coalition filter into the state machine.
+// Once set, RunningTasksHandler.Exit drops any process/coalition it
+// doesn't match before merging the sample into MetricsState.
+func (sm *StateMachine) SetSelector(sel *selector.Set) {
+	sm.context.Selector = sel
+}
+
+// SetProcCache wires a persistent process-metadata cache into the state
+// machine. Once set, updateProcessTracking consults it to backfill ghost
+// PIDs and records entries for currently-seen processes so a future restart
+// can resolve them in turn.
+func (sm *StateMachine) SetProcCache(cache *proccache.Cache) {
+	sm.context.ProcCache = cache
+}
+
+// SetFollowChildren toggles --follow-children. Once enabled,
+// RunningTasksHandler.Exit rolls each coalition's un-reported descendant
+// processes' CPU/memory into its totals every sample.
+func (sm *StateMachine) SetFollowChildren(enabled bool) {
+	sm.context.FollowChildren = enabled
+}
+
+// SetIncludeDeadChildren toggles --include-dead-children. Once enabled,
+// RunningTasksHandler.Exit adds each coalition leader's reaped-child CPU
+// delta since the last sample to CoalitionCPUHistory.
+func (sm *StateMachine) SetIncludeDeadChildren(enabled bool) {
+	sm.context.IncludeDeadChildren = enabled
+}
+
+// SetMetricFilter wires a --config exclude_metrics bitmask into the state
+// machine. Once set, handlers like ProcessorUsageHandler and
+// InSampleHandler skip regex evaluation for every excluded metric.
+func (sm *StateMachine) SetMetricFilter(mask MetricFlag) {
+	sm.context.ExcludedMetrics = mask
+}
+
 // FinalizeCurrentState forces the Exit method of the current state handler
 // This is useful when parsing is complete and we need to commit data
 func (sm *StateMachine) FinalizeCurrentState() {
@@ -302,6 +414,16 @@ func (sm *StateMachine) FinalizeCurrentState() {
 			handler.Exit(sm.context)
 		}
 	}
+	if sm.context.Tracker != nil {
+		sm.context.Tracker.Observe(sm.context.MetricsState.Processes, sm.context.MetricsState.Coalitions, time.Now())
+	}
+	if sm.context.Alerts != nil {
+		sm.context.Alerts.Evaluate(sm.context.MetricsState)
+	}
+	if sm.context.Exporter != nil {
+		sm.context.Exporter.Publish(sm.context.MetricsState)
+	}
+
 	// Reset to waiting state for next sample
 	sm.TransitionTo(StateWaitingForSample)
 }
\ No newline at end of file
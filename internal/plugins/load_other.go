@@ -0,0 +1,14 @@
+//go:build !(linux || darwin)
+
+package plugins
+
+import "errors"
+
+// ErrUnsupported is returned by LoadDir on any build without Go plugin
+// support (anything but linux or darwin).
+var ErrUnsupported = errors.New("plugins: the Go plugin package isn't supported on this platform")
+
+// LoadDir always fails with ErrUnsupported on this build.
+func LoadDir(dir string) ([]Panel, []error) {
+	return nil, []error{ErrUnsupported}
+}
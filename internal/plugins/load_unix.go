@@ -0,0 +1,49 @@
+//go:build linux || darwin
+
+package plugins
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"plugin"
+)
+
+// LoadDir opens every .so file in dir and returns the Panels they register.
+// Each .so must export a func() Panel symbol named RegisterSymbol; a plugin
+// that's missing the symbol, exports it with the wrong type, or fails to
+// open at all is reported as an error for that file rather than aborting
+// the whole directory, so one broken plugin doesn't take out the others.
+func LoadDir(dir string) ([]Panel, []error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	var panels []Panel
+	var errs []error
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".so" {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+
+		p, err := plugin.Open(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		sym, err := p.Lookup(RegisterSymbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		register, ok := sym.(func() Panel)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: %s has the wrong type, want func() Panel", path, RegisterSymbol))
+			continue
+		}
+		panels = append(panels, register())
+	}
+	return panels, errs
+}
@@ -0,0 +1,47 @@
+// Package plugins lets users extend the TUI with additional draw panels
+// without forking the module, by dropping Go plugin .so files into
+// $XDG_CONFIG_HOME/powermetrics-tui/plugins. Each .so exports a Register
+// func() Panel symbol; LoadDir opens every .so in a directory and returns
+// the Panels they register, which main then registers into
+// internal/layout's Renderer so a layout spec can reference a plugin's
+// Name() like any built-in widget.
+package plugins
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+	"powermetrics-tui/internal/models"
+)
+
+// Panel is the interface a plugin's Register func() Panel must return.
+// Update is called once per finalized sample before Draw, mirroring how the
+// built-in widgets read state directly in their DrawFunc; splitting it out
+// lets a panel do any heavier per-sample bookkeeping (e.g. parsing an
+// external sensor reading) without doing it once per frame if the TUI ever
+// redraws faster than it samples.
+type Panel interface {
+	Name() string
+	Update(state *models.MetricsState)
+	Draw(screen tcell.Screen, x, y, w, h int)
+}
+
+// RegisterSymbol is the exported symbol name (func() Panel) every plugin
+// .so must define.
+const RegisterSymbol = "Register"
+
+// DefaultDir returns $XDG_CONFIG_HOME/powermetrics-tui/plugins, falling back
+// to ~/.config/powermetrics-tui/plugins when XDG_CONFIG_HOME is unset,
+// mirroring internal/layout.DefaultPath's resolution.
+func DefaultDir() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "powermetrics-tui", "plugins")
+}
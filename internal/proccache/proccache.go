@@ -0,0 +1,168 @@
+// Package proccache persists per-PID process metadata (name, first-seen
+// time, coalition, and rolling CPU/mem history) to a JSON file across
+// restarts. Without it, a "ghost PID" - one RunningTasksHandler's
+// updateProcessTracking finds in MetricsState.LastSeenPIDs with no name,
+// because the app was restarted and LastSeenPIDs itself never survives
+// that - is silently purged instead of being recognized as a process this
+// or a prior run already knew about. This mirrors the Beats sessionmd
+// processor's practice of keeping exited-process info around so later
+// events about the same process can still be enriched.
+package proccache
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// maxAge is how long an Entry is trusted without being seen again before
+// it's treated as gone. Without this, a PID the OS has long since reused
+// for an unrelated process would otherwise keep answering Get with the
+// original, long-dead process's name/FirstSeenTime forever.
+const maxAge = 30 * 24 * time.Hour
+
+// maxEntries bounds how many PIDs the cache keeps on disk regardless of
+// age, so a long-running machine that's cycled through many thousands of
+// short-lived processes doesn't grow procs.json without bound.
+const maxEntries = 5000
+
+// Entry is one PID's persisted metadata.
+type Entry struct {
+	PID           int       `json:"pid"`
+	Name          string    `json:"name"`
+	FirstSeenTime time.Time `json:"first_seen_time"`
+	LastSeenTime  time.Time `json:"last_seen_time"`
+	CoalitionName string    `json:"coalition_name,omitempty"`
+	CPUHistory    []float64 `json:"cpu_history,omitempty"`
+	MemHistory    []float64 `json:"mem_history,omitempty"`
+}
+
+// Cache is an in-memory, disk-backed map of PID to Entry.
+type Cache struct {
+	path    string
+	entries map[int]Entry
+}
+
+// DefaultPath returns ~/.cache/powermetrics-tui/procs.json, the location
+// Load/Save use when main doesn't override it.
+func DefaultPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cache", "powermetrics-tui", "procs.json")
+}
+
+// Load reads path into a Cache, starting empty (not an error) if the file
+// doesn't exist yet - the common case on a machine's first run.
+func Load(path string) (*Cache, error) {
+	c := &Cache{path: path, entries: make(map[int]Entry)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for _, e := range entries {
+		if now.Sub(lastSeen(e)) > maxAge {
+			continue
+		}
+		c.entries[e.PID] = e
+	}
+	c.evict()
+	return c, nil
+}
+
+// lastSeen is e.LastSeenTime, falling back to FirstSeenTime for entries
+// persisted before LastSeenTime was added, so an existing procs.json isn't
+// treated as instantly stale the first time it's loaded under this policy.
+func lastSeen(e Entry) time.Time {
+	if e.LastSeenTime.IsZero() {
+		return e.FirstSeenTime
+	}
+	return e.LastSeenTime
+}
+
+// Get returns pid's cached Entry, if one was loaded or Put since and it
+// hasn't aged out: a PID not seen again in over maxAge is treated as gone
+// rather than handed back to a caller like the ghost-PID backfill, which
+// would otherwise risk attributing a long-dead process's identity to
+// whatever unrelated process the OS has since reused that PID for.
+func (c *Cache) Get(pid int) (Entry, bool) {
+	if c == nil {
+		return Entry{}, false
+	}
+	e, ok := c.entries[pid]
+	if !ok {
+		return Entry{}, false
+	}
+	if time.Since(lastSeen(e)) > maxAge {
+		delete(c.entries, pid)
+		return Entry{}, false
+	}
+	return e, ok
+}
+
+// Put records or replaces pid's Entry in memory, stamping LastSeenTime if
+// the caller didn't set one; callers still need Save to persist it.
+func (c *Cache) Put(e Entry) {
+	if c == nil {
+		return
+	}
+	if e.LastSeenTime.IsZero() {
+		e.LastSeenTime = time.Now()
+	}
+	c.entries[e.PID] = e
+}
+
+// evict drops the least-recently-seen entries once the cache exceeds
+// maxEntries, so a machine that's cycled through many thousands of
+// short-lived processes doesn't grow procs.json without bound.
+func (c *Cache) evict() {
+	if len(c.entries) <= maxEntries {
+		return
+	}
+	pids := make([]int, 0, len(c.entries))
+	for pid := range c.entries {
+		pids = append(pids, pid)
+	}
+	sort.Slice(pids, func(i, j int) bool {
+		return lastSeen(c.entries[pids[i]]).Before(lastSeen(c.entries[pids[j]]))
+	})
+	for _, pid := range pids[:len(pids)-maxEntries] {
+		delete(c.entries, pid)
+	}
+}
+
+// Save writes the current entries to disk as a JSON array, creating its
+// parent directory on first use.
+func (c *Cache) Save() error {
+	if c == nil || c.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return err
+	}
+
+	c.evict()
+	entries := make([]Entry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, e)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0o644)
+}
@@ -0,0 +1,85 @@
+package proccache
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFileIsNotError(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), "does-not-exist.json"))
+	if err != nil {
+		t.Fatalf("Load of missing file returned error: %v", err)
+	}
+	if _, ok := c.Get(1); ok {
+		t.Errorf("Get on a freshly loaded empty cache returned an entry")
+	}
+}
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "procs.json")
+
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := Entry{PID: 42, Name: "kernel_task", FirstSeenTime: time.Now().Truncate(time.Second), CoalitionName: "com.apple.xpc"}
+	c.Put(want)
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load after Save: %v", err)
+	}
+	got, ok := reloaded.Get(42)
+	if !ok {
+		t.Fatalf("Get(42) after reload = not found, want %+v", want)
+	}
+	if got.PID != want.PID || got.Name != want.Name || !got.FirstSeenTime.Equal(want.FirstSeenTime) || got.CoalitionName != want.CoalitionName {
+		t.Errorf("Get(42) after reload = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetExpiresEntriesOlderThanMaxAge(t *testing.T) {
+	c, err := Load(filepath.Join(t.TempDir(), "procs.json"))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	c.Put(Entry{PID: 7, Name: "long-gone", LastSeenTime: time.Now().Add(-maxAge - time.Hour)})
+
+	if _, ok := c.Get(7); ok {
+		t.Error("expected an entry not seen in over maxAge to be treated as gone")
+	}
+}
+
+func TestSaveEvictsOldestBeyondMaxEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "procs.json")
+	c, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	base := time.Now()
+	for i := 0; i < maxEntries+10; i++ {
+		c.Put(Entry{PID: i, Name: "p", LastSeenTime: base.Add(time.Duration(i) * time.Second)})
+	}
+	if err := c.Save(); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load after Save: %v", err)
+	}
+	if len(reloaded.entries) != maxEntries {
+		t.Fatalf("expected eviction to cap the cache at %d entries, got %d", maxEntries, len(reloaded.entries))
+	}
+	if _, ok := reloaded.Get(0); ok {
+		t.Error("expected the least-recently-seen entry to have been evicted")
+	}
+	if _, ok := reloaded.Get(maxEntries + 9); !ok {
+		t.Error("expected the most-recently-seen entry to survive eviction")
+	}
+}
@@ -0,0 +1,70 @@
+package procenrich
+
+import (
+	"sync"
+	"time"
+)
+
+// CachingEnricher wraps another Enricher with a per-PID TTL cache, so a
+// long-lived PID isn't re-queried - and `ps` re-forked - every single
+// sample, only once every ttl.
+type CachingEnricher struct {
+	inner Enricher
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[int]cacheEntry
+}
+
+type cacheEntry struct {
+	info    Info
+	sampled time.Time
+}
+
+// NewCachingEnricher wraps inner, serving a cached Info for up to ttl
+// before re-querying inner for that PID.
+func NewCachingEnricher(inner Enricher, ttl time.Duration) *CachingEnricher {
+	return &CachingEnricher{inner: inner, ttl: ttl, entries: make(map[int]cacheEntry)}
+}
+
+func (c *CachingEnricher) Enrich(pids []int) map[int]Info {
+	now := time.Now()
+	result := make(map[int]Info, len(pids))
+
+	c.mu.Lock()
+	var stale []int
+	for _, pid := range pids {
+		if e, ok := c.entries[pid]; ok && now.Sub(e.sampled) < c.ttl {
+			result[pid] = e.info
+			continue
+		}
+		stale = append(stale, pid)
+	}
+	c.mu.Unlock()
+
+	if len(stale) == 0 {
+		return result
+	}
+
+	fresh := c.inner.Enrich(stale)
+
+	c.mu.Lock()
+	for pid, info := range fresh {
+		c.entries[pid] = cacheEntry{info: info, sampled: now}
+		result[pid] = info
+	}
+	c.mu.Unlock()
+
+	return result
+}
+
+// Invalidate forgets pid's cached entry and forwards to inner, so a PID
+// RunningTasksHandler has decided is dead or reused is queried fresh the
+// next time (if ever) it reappears, instead of serving a stale hit for up
+// to ttl longer.
+func (c *CachingEnricher) Invalidate(pid int) {
+	c.mu.Lock()
+	delete(c.entries, pid)
+	c.mu.Unlock()
+	c.inner.Invalidate(pid)
+}
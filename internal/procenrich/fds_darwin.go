@@ -0,0 +1,28 @@
+//go:build darwin && cgo
+
+package procenrich
+
+/*
+#include <libproc.h>
+#include <sys/proc_info.h>
+*/
+import "C"
+
+import "errors"
+
+// ErrUnsupported is never returned on darwin+cgo; it exists so callers can
+// compare against the same sentinel on every build.
+var ErrUnsupported = errors.New("procenrich: fd count requires darwin with cgo enabled")
+
+// numOpenFDs returns pid's open file descriptor count via
+// proc_pidinfo(PROC_PIDLISTFDS), the same libproc call Activity Monitor's
+// per-process "Open Files" figure uses, in place of forking `lsof -p pid`
+// (which itself walks every process on the system) just to count one PID's
+// descriptors.
+func numOpenFDs(pid int) (int, error) {
+	n := C.proc_pidinfo(C.int(pid), C.PROC_PIDLISTFDS, 0, nil, 0)
+	if n <= 0 {
+		return 0, errors.New("procenrich: proc_pidinfo PROC_PIDLISTFDS failed")
+	}
+	return int(n) / int(C.sizeof_struct_proc_fdinfo), nil
+}
@@ -0,0 +1,12 @@
+//go:build !(darwin && cgo)
+
+package procenrich
+
+import "errors"
+
+// ErrUnsupported is always returned by numOpenFDs on this build.
+var ErrUnsupported = errors.New("procenrich: fd count requires darwin with cgo enabled")
+
+func numOpenFDs(pid int) (int, error) {
+	return 0, ErrUnsupported
+}
@@ -0,0 +1,9 @@
+package procenrich
+
+// NoopEnricher resolves nothing, for --no-enrich: RunningTasksHandler still
+// runs, but every process keeps powermetrics' raw fields instead of paying
+// for a `ps` enrichment pass nobody asked for.
+type NoopEnricher struct{}
+
+func (NoopEnricher) Enrich(pids []int) map[int]Info { return nil }
+func (NoopEnricher) Invalidate(int)                 {}
@@ -0,0 +1,34 @@
+// Package procenrich fills in the process detail powermetrics' task
+// section never reports - real RSS/VMS, thread/FD counts, full command
+// line, owning user, and start time - via a batched `ps` call covering
+// every PID in a sample, mirroring gopsutil's Process.fillFromStat/
+// fillFromStatus but scoped to just the PIDs RunningTasksHandler is about
+// to report instead of the whole system.
+package procenrich
+
+import "time"
+
+// Info is one PID's enrichment result as of the last Enricher.Enrich call.
+type Info struct {
+	RSSMB      float64
+	VMSMB      float64
+	NumThreads int
+	NumFDs     int
+	Cmdline    string
+	User       string
+	StartTime  time.Time
+}
+
+// Enricher batch-resolves Info for a set of PIDs.
+type Enricher interface {
+	// Enrich returns whatever Info it can resolve for pids, keyed by PID; a
+	// PID it can't resolve (already exited, permission denied) is simply
+	// absent from the result rather than reported as an error, matching how
+	// the rest of RunningTasksHandler tolerates a dead/racy PID.
+	Enrich(pids []int) map[int]Info
+
+	// Invalidate forgets any cached Info for pid, e.g. once
+	// RunningTasksHandler.updateProcessTracking has decided pid is dead or
+	// been reused by a different process.
+	Invalidate(pid int)
+}
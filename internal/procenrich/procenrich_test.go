@@ -0,0 +1,101 @@
+package procenrich
+
+import (
+	"testing"
+	"time"
+)
+
+// countingEnricher counts how many times Enrich was called with each PID,
+// so tests can assert on CachingEnricher's cache-hit behavior.
+type countingEnricher struct {
+	calls int
+	seen  []int
+}
+
+func (c *countingEnricher) Enrich(pids []int) map[int]Info {
+	c.calls++
+	c.seen = append(c.seen, pids...)
+	out := make(map[int]Info, len(pids))
+	for _, pid := range pids {
+		out[pid] = Info{RSSMB: float64(pid)}
+	}
+	return out
+}
+
+func (c *countingEnricher) Invalidate(int) {}
+
+func TestCachingEnricherServesWithinTTL(t *testing.T) {
+	inner := &countingEnricher{}
+	c := NewCachingEnricher(inner, time.Minute)
+
+	first := c.Enrich([]int{100})
+	if first[100].RSSMB != 100 {
+		t.Fatalf("first Enrich = %+v, want RSSMB 100", first[100])
+	}
+	if inner.calls != 1 {
+		t.Fatalf("expected 1 inner call after first Enrich, got %d", inner.calls)
+	}
+
+	second := c.Enrich([]int{100})
+	if second[100].RSSMB != 100 {
+		t.Fatalf("second Enrich = %+v, want RSSMB 100", second[100])
+	}
+	if inner.calls != 1 {
+		t.Errorf("expected a cache hit to avoid a second inner call, got %d calls", inner.calls)
+	}
+}
+
+func TestCachingEnricherInvalidateForcesRequery(t *testing.T) {
+	inner := &countingEnricher{}
+	c := NewCachingEnricher(inner, time.Minute)
+
+	c.Enrich([]int{100})
+	c.Invalidate(100)
+	c.Enrich([]int{100})
+
+	if inner.calls != 2 {
+		t.Errorf("expected Invalidate to force a second inner call, got %d", inner.calls)
+	}
+}
+
+func TestCachingEnricherRequeriesAfterTTL(t *testing.T) {
+	inner := &countingEnricher{}
+	c := NewCachingEnricher(inner, 0)
+
+	c.Enrich([]int{100})
+	c.Enrich([]int{100})
+
+	if inner.calls != 2 {
+		t.Errorf("expected a zero TTL to always re-query, got %d calls", inner.calls)
+	}
+}
+
+func TestParsePSEnrichLine(t *testing.T) {
+	line := "1234   5120  81920     3 root Wed Jul 30 12:00:00 2026 /usr/sbin/sshd -D -d"
+	pid, info, ok := parsePSEnrichLine(line)
+	if !ok {
+		t.Fatalf("parsePSEnrichLine(%q) failed to parse", line)
+	}
+	if pid != 1234 {
+		t.Errorf("pid = %d, want 1234", pid)
+	}
+	if info.RSSMB != 5 || info.VMSMB != 80 {
+		t.Errorf("RSSMB/VMSMB = %v/%v, want 5/80", info.RSSMB, info.VMSMB)
+	}
+	if info.NumThreads != 3 || info.User != "root" {
+		t.Errorf("NumThreads/User = %d/%q, want 3/root", info.NumThreads, info.User)
+	}
+	if info.Cmdline != "/usr/sbin/sshd -D -d" {
+		t.Errorf("Cmdline = %q, want the full command", info.Cmdline)
+	}
+	want := time.Date(2026, time.July, 30, 12, 0, 0, 0, time.Local)
+	if !info.StartTime.Equal(want) {
+		t.Errorf("StartTime = %v, want %v", info.StartTime, want)
+	}
+}
+
+func TestParsePSEnrichLineTooFewFields(t *testing.T) {
+	if _, _, ok := parsePSEnrichLine("1234 5120"); ok {
+		t.Error("expected a short line to fail parsing")
+	}
+}
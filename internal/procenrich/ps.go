@@ -0,0 +1,89 @@
+package procenrich
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lstartLayout matches `ps -o lstart=`'s fixed five-field format, e.g.
+// "Wed Jul 30 12:00:00 2026".
+const lstartLayout = "Mon Jan 2 15:04:05 2006"
+
+// PSEnricher resolves Info via one batched
+// `ps -o pid=,rss=,vsz=,thcount=,user=,lstart=,command=` call covering
+// every requested PID, rather than forking `ps -p <pid>` once per PID
+// every sample (the approach this replaces - see internal/parser's
+// removed populateMemoryEx). NumFDs comes from a separate, cheap
+// per-PID libproc call (see fds_darwin.go/fds_other.go); ps has no
+// equivalent column.
+type PSEnricher struct{}
+
+// NewPSEnricher returns an Enricher backed by `ps`.
+func NewPSEnricher() *PSEnricher { return &PSEnricher{} }
+
+// Invalidate is a no-op: PSEnricher caches nothing itself (see
+// CachingEnricher for the TTL cache that wraps it).
+func (e *PSEnricher) Invalidate(int) {}
+
+func (e *PSEnricher) Enrich(pids []int) map[int]Info {
+	result := make(map[int]Info, len(pids))
+	if len(pids) == 0 {
+		return result
+	}
+
+	pidList := make([]string, len(pids))
+	for i, pid := range pids {
+		pidList[i] = strconv.Itoa(pid)
+	}
+
+	out, err := exec.Command("ps", "-o", "pid=,rss=,vsz=,thcount=,user=,lstart=,command=",
+		"-p", strings.Join(pidList, ",")).Output()
+	if err != nil {
+		return result
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		pid, info, ok := parsePSEnrichLine(scanner.Text())
+		if !ok {
+			continue
+		}
+		info.NumFDs, _ = numOpenFDs(pid)
+		result[pid] = info
+	}
+	return result
+}
+
+// parsePSEnrichLine parses one "pid rss vsz thcount user <5-field lstart>
+// command..." row. lstart is always exactly five space-separated fields
+// and command may itself contain spaces, so neither can be pulled out by a
+// fixed Fields() index alone.
+func parsePSEnrichLine(line string) (int, Info, bool) {
+	fields := strings.Fields(line)
+	if len(fields) < 10 {
+		return 0, Info{}, false
+	}
+
+	pid, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, Info{}, false
+	}
+	rssKB, _ := strconv.ParseFloat(fields[1], 64)
+	vszKB, _ := strconv.ParseFloat(fields[2], 64)
+	numThreads, _ := strconv.Atoi(fields[3])
+	user := fields[4]
+	startTime, _ := time.ParseInLocation(lstartLayout, strings.Join(fields[5:10], " "), time.Local)
+
+	return pid, Info{
+		RSSMB:      rssKB / 1024,
+		VMSMB:      vszKB / 1024,
+		NumThreads: numThreads,
+		User:       user,
+		StartTime:  startTime,
+		Cmdline:    strings.Join(fields[10:], " "),
+	}, true
+}
@@ -0,0 +1,72 @@
+package procfind
+
+import (
+	"fmt"
+
+	"github.com/shirou/gopsutil/v4/process"
+)
+
+// GopsutilFinder backs Finder with github.com/shirou/gopsutil/v4/process,
+// which reads /proc (Linux) or the host's native process-info calls
+// (anywhere else gopsutil supports) instead of forking ps/pgrep. This is
+// New's default.
+type GopsutilFinder struct{}
+
+// NewGopsutilFinder returns a GopsutilFinder.
+func NewGopsutilFinder() *GopsutilFinder {
+	return &GopsutilFinder{}
+}
+
+func (f *GopsutilFinder) Exists(pid int) bool {
+	ok, err := process.PidExists(int32(pid))
+	return err == nil && ok
+}
+
+func (f *GopsutilFinder) EnrichName(pid int) (string, error) {
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return "", err
+	}
+	if exe, err := proc.Exe(); err == nil && exe != "" {
+		return exe, nil
+	}
+	cmdline, err := proc.Cmdline()
+	if err != nil {
+		return "", err
+	}
+	if cmdline == "" {
+		return "", fmt.Errorf("pid %d: no executable path or command line available", pid)
+	}
+	return cmdline, nil
+}
+
+func (f *GopsutilFinder) OwnerUID(pid int) (string, error) {
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return "", err
+	}
+	uids, err := proc.Uids()
+	if err != nil {
+		return "", err
+	}
+	if len(uids) == 0 {
+		return "", fmt.Errorf("pid %d: no uid reported", pid)
+	}
+	return fmt.Sprintf("%d", uids[0]), nil
+}
+
+func (f *GopsutilFinder) ChildrenOf(pid int) []int {
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return nil
+	}
+	children, err := proc.Children()
+	if err != nil {
+		return nil
+	}
+	ids := make([]int, len(children))
+	for i, child := range children {
+		ids[i] = int(child.Pid)
+	}
+	return ids
+}
@@ -0,0 +1,44 @@
+// Package procfind abstracts PID liveness checks and name/command-line
+// enrichment behind a Finder interface, mirroring how internal/collector
+// hides powermetrics vs gopsutil behind one MetricsCollector interface.
+// Without it, callers like internal/parser's RunningTasksHandler and
+// internal/ui's exited-process view each forked `ps -p <pid>` (or
+// `pgrep`) once per possibly-dead PID, per sample or per frame - a real
+// bottleneck under high process churn, the telegraf procstat plugin hits
+// the same problem and solves it the same way.
+package procfind
+
+// Finder discovers whether a PID is still alive and, for one that's gone,
+// can still be identified (it was seen at least once while running).
+type Finder interface {
+	// Exists reports whether pid currently identifies a running process.
+	Exists(pid int) bool
+
+	// EnrichName returns a descriptive name for pid - its executable path,
+	// falling back to its command line - for replacing placeholder names
+	// like "<dead-process-N>" or "Unknown Process (PID N)". It only
+	// succeeds for a PID that's still running at call time.
+	EnrichName(pid int) (string, error)
+
+	// ChildrenOf returns the PIDs of pid's immediate children, or nil if
+	// pid doesn't exist or has none.
+	ChildrenOf(pid int) []int
+
+	// OwnerUID returns pid's effective-user uid as a string, for matching
+	// against os/user.Lookup's Uid without each caller shelling out to
+	// `ps -o uid=` on its own (see internal/selector's EffectiveUser).
+	OwnerUID(pid int) (string, error)
+}
+
+// New returns the Finder to use for the current run. backend may be
+// "gopsutil", "ps", or "" to auto-detect, which prefers gopsutil since it
+// forks nothing.
+func New(backend string) Finder {
+	switch backend {
+	case "ps":
+		return NewPSFinder()
+	case "gopsutil":
+		return NewGopsutilFinder()
+	}
+	return NewGopsutilFinder()
+}
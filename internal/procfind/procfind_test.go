@@ -0,0 +1,30 @@
+package procfind
+
+import (
+	"os"
+	"testing"
+)
+
+func TestNewSelectsBackend(t *testing.T) {
+	if _, ok := New("gopsutil").(*GopsutilFinder); !ok {
+		t.Errorf("New(%q) = %T, want *GopsutilFinder", "gopsutil", New("gopsutil"))
+	}
+	if _, ok := New("ps").(*PSFinder); !ok {
+		t.Errorf("New(%q) = %T, want *PSFinder", "ps", New("ps"))
+	}
+	if _, ok := New("").(*GopsutilFinder); !ok {
+		t.Errorf("New(%q) = %T, want *GopsutilFinder (the default)", "", New(""))
+	}
+}
+
+func TestGopsutilFinderExists(t *testing.T) {
+	f := NewGopsutilFinder()
+	self := os.Getpid()
+	if !f.Exists(self) {
+		t.Errorf("Exists(%d) for our own PID = false, want true", self)
+	}
+	const implausiblePID = 1<<31 - 2
+	if f.Exists(implausiblePID) {
+		t.Errorf("Exists(%d) = true, want false", implausiblePID)
+	}
+}
@@ -0,0 +1,63 @@
+package procfind
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// PSFinder backs Finder with ps/pgrep subprocess calls - one fork per
+// lookup - for environments where gopsutil's platform support is
+// incomplete or suspect. It exists for parity/debugging (see --pid-finder),
+// not as a default: it's exactly the per-PID forking Finder was added to
+// avoid.
+type PSFinder struct{}
+
+// NewPSFinder returns a PSFinder.
+func NewPSFinder() *PSFinder {
+	return &PSFinder{}
+}
+
+func (f *PSFinder) Exists(pid int) bool {
+	return exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "pid=").Run() == nil
+}
+
+func (f *PSFinder) EnrichName(pid int) (string, error) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "comm=").Output()
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(string(out))
+	if name == "" {
+		return "", fmt.Errorf("pid %d: ps returned no command name", pid)
+	}
+	return name, nil
+}
+
+func (f *PSFinder) OwnerUID(pid int) (string, error) {
+	out, err := exec.Command("ps", "-p", strconv.Itoa(pid), "-o", "uid=").Output()
+	if err != nil {
+		return "", err
+	}
+	uid := strings.TrimSpace(string(out))
+	if uid == "" {
+		return "", fmt.Errorf("pid %d: ps returned no uid", pid)
+	}
+	return uid, nil
+}
+
+func (f *PSFinder) ChildrenOf(pid int) []int {
+	out, err := exec.Command("pgrep", "-P", strconv.Itoa(pid)).Output()
+	if err != nil {
+		return nil
+	}
+
+	var children []int
+	for _, field := range strings.Fields(string(out)) {
+		if n, err := strconv.Atoi(field); err == nil {
+			children = append(children, n)
+		}
+	}
+	return children
+}
@@ -0,0 +1,311 @@
+// Package proctrack keeps rolling-window CPU/memory/wakeups/GPU/ANE/energy
+// history per pid and per coalition across samples, similar to how tools
+// like crunchstat aggregate cgroup accounting over time and report avg/max
+// instead of only the latest instantaneous reading. main's Top pane
+// queries it through GetTopNPinned rather than recomputing its own
+// rollups.
+package proctrack
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"powermetrics-tui/internal/models"
+)
+
+// Kind distinguishes a tracked process from a tracked coalition; PIDs and
+// coalition IDs are independent numbering spaces, so they're kept in
+// separate tables.
+type Kind int
+
+const (
+	KindProcess Kind = iota
+	KindCoalition
+)
+
+// Metric is one of the dimensions GetTopN can rank by.
+type Metric int
+
+const (
+	MetricCPUPercent Metric = iota
+	MetricMemoryMB
+	MetricWakeups
+	MetricGPUMS
+	MetricANEMS
+	MetricEnergyMJ
+)
+
+// Metrics lists every rankable dimension, in the order the Top pane cycles
+// through them.
+var Metrics = []Metric{MetricCPUPercent, MetricMemoryMB, MetricWakeups, MetricGPUMS, MetricANEMS, MetricEnergyMJ}
+
+// String returns a short label suitable for a column header or status line.
+func (m Metric) String() string {
+	switch m {
+	case MetricMemoryMB:
+		return "Memory"
+	case MetricWakeups:
+		return "Wakeups"
+	case MetricGPUMS:
+		return "GPU ms"
+	case MetricANEMS:
+		return "ANE ms"
+	case MetricEnergyMJ:
+		return "Energy"
+	default:
+		return "CPU%"
+	}
+}
+
+// Rolling windows GetTopN can aggregate over.
+const (
+	Window60s = 60 * time.Second
+	Window5m  = 5 * time.Minute
+	Window15m = 15 * time.Minute
+)
+
+// Windows lists the supported windows, in the order the Top pane cycles
+// through them.
+var Windows = []time.Duration{Window60s, Window5m, Window15m}
+
+// maxSamples bounds how many timestamped samples a single entry retains
+// regardless of window, so a stalled clock or unusually fast sampling can't
+// grow memory without bound; it comfortably covers Window15m at a 1s
+// sampling interval with headroom to spare.
+const maxSamples = 1024
+
+// evictAfter is how many consecutive Observe calls an entity may be absent
+// for before its entry is dropped, bounding memory as pids and coalitions
+// come and go.
+const evictAfter = 10
+
+// Sample is one observation of a tracked entity for a single powermetrics
+// sample.
+type Sample struct {
+	CPUPercent float64
+	MemoryMB   float64
+	Wakeups    float64
+	GPUMS      float64
+	ANEMS      float64
+	EnergyMJ   float64
+}
+
+func (s Sample) value(m Metric) float64 {
+	switch m {
+	case MetricMemoryMB:
+		return s.MemoryMB
+	case MetricWakeups:
+		return s.Wakeups
+	case MetricGPUMS:
+		return s.GPUMS
+	case MetricANEMS:
+		return s.ANEMS
+	case MetricEnergyMJ:
+		return s.EnergyMJ
+	default:
+		return s.CPUPercent
+	}
+}
+
+type observation struct {
+	at time.Time
+	s  Sample
+}
+
+// entry is the rollup state for one tracked pid or coalition id. powermetrics
+// doesn't expose a process start_time, so entries approximate the
+// (pid, start_time) key the caller asked for by evicting anything unseen
+// for evictAfter samples: a pid reused after that point gets a fresh entry
+// with a new startedAt rather than silently blending with the old process's
+// history.
+type entry struct {
+	id        int
+	name      string
+	startedAt time.Time
+	missed    int
+	samples   []observation
+}
+
+func (e *entry) observe(now time.Time, s Sample) {
+	e.missed = 0
+	e.samples = append(e.samples, observation{at: now, s: s})
+
+	cut := now.Add(-Window15m)
+	i := 0
+	for i < len(e.samples) && e.samples[i].at.Before(cut) {
+		i++
+	}
+	if i > 0 {
+		e.samples = e.samples[i:]
+	}
+	if len(e.samples) > maxSamples {
+		e.samples = e.samples[len(e.samples)-maxSamples:]
+	}
+}
+
+// rollup returns the average and peak of metric m over the samples observed
+// within window, and whether any samples fell in that window at all.
+func (e *entry) rollup(m Metric, window time.Duration, now time.Time) (avg, max float64, ok bool) {
+	cut := now.Add(-window)
+	var sum float64
+	var count int
+	for _, o := range e.samples {
+		if o.at.Before(cut) {
+			continue
+		}
+		v := o.s.value(m)
+		sum += v
+		if !ok || v > max {
+			max = v
+		}
+		ok = true
+		count++
+	}
+	if count == 0 {
+		return 0, 0, false
+	}
+	return sum / float64(count), max, true
+}
+
+// TopEntry is one row of a GetTopN result.
+type TopEntry struct {
+	Kind      Kind
+	ID        int
+	Name      string
+	StartedAt time.Time
+	Avg       float64
+	Max       float64
+	// Pinned is true when GetTopNPinned kept this entry in because its ID
+	// was in the caller's pinned set, not because it ranked in the top n.
+	Pinned bool
+}
+
+// Tracker accumulates per-pid and per-coalition rollups across samples. The
+// zero value is not usable; construct one with NewTracker. A *Tracker is
+// safe for concurrent use.
+type Tracker struct {
+	mu         sync.Mutex
+	processes  map[int]*entry
+	coalitions map[int]*entry
+}
+
+// NewTracker creates an empty Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		processes:  make(map[int]*entry),
+		coalitions: make(map[int]*entry),
+	}
+}
+
+// Observe folds one sample's processes and coalitions into the tracker,
+// evicting anything not seen for more than evictAfter calls in a row.
+func (t *Tracker) Observe(processes []models.ProcessInfo, coalitions []models.ProcessCoalition, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seen := make(map[int]bool, len(processes))
+	for _, p := range processes {
+		seen[p.PID] = true
+		e := t.processes[p.PID]
+		if e == nil {
+			e = &entry{id: p.PID, startedAt: now}
+			t.processes[p.PID] = e
+		}
+		e.name = p.Name
+		e.observe(now, Sample{
+			CPUPercent: p.CPUPercent,
+			MemoryMB:   p.MemoryMB,
+			Wakeups:    p.Wakeups,
+			GPUMS:      p.GPUMS,
+			ANEMS:      p.ANEMS,
+			EnergyMJ:   p.EnergyEstimate,
+		})
+	}
+	evict(t.processes, seen)
+
+	seenC := make(map[int]bool, len(coalitions))
+	for _, c := range coalitions {
+		seenC[c.CoalitionID] = true
+		e := t.coalitions[c.CoalitionID]
+		if e == nil {
+			e = &entry{id: c.CoalitionID, startedAt: now}
+			t.coalitions[c.CoalitionID] = e
+		}
+		e.name = c.Name
+		e.observe(now, Sample{CPUPercent: c.CPUPercent, MemoryMB: c.MemoryMB})
+	}
+	evict(t.coalitions, seenC)
+}
+
+func evict(table map[int]*entry, seen map[int]bool) {
+	for id, e := range table {
+		if seen[id] {
+			continue
+		}
+		e.missed++
+		if e.missed > evictAfter {
+			delete(table, id)
+		}
+	}
+}
+
+// GetTopN returns up to n entries of the given kind ranked by metric's
+// average over window, highest first. n <= 0 means unlimited.
+func (t *Tracker) GetTopN(kind Kind, metric Metric, window time.Duration, n int) []TopEntry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	table := t.processes
+	if kind == KindCoalition {
+		table = t.coalitions
+	}
+
+	now := time.Now()
+	out := make([]TopEntry, 0, len(table))
+	for _, e := range table {
+		avg, max, ok := e.rollup(metric, window, now)
+		if !ok {
+			continue
+		}
+		out = append(out, TopEntry{Kind: kind, ID: e.id, Name: e.name, StartedAt: e.startedAt, Avg: avg, Max: max})
+	}
+
+	sort.Slice(out, func(i, j int) bool { return out[i].Avg > out[j].Avg })
+	if n > 0 && len(out) > n {
+		out = out[:n]
+	}
+	return out
+}
+
+// GetTopNPinned behaves like GetTopN, except every id in pinned is kept in
+// the result (with Pinned set) even if it falls outside the top n by
+// metric, e.g. a watchlisted process that's idle right now but whose
+// memory curve the user still wants visible. Pinned entries are appended
+// after the ranked top n, in descending Avg order among themselves.
+func (t *Tracker) GetTopNPinned(kind Kind, metric Metric, window time.Duration, n int, pinned map[int]bool) []TopEntry {
+	top := t.GetTopN(kind, metric, window, n)
+	if len(pinned) == 0 {
+		return top
+	}
+
+	inTop := make(map[int]bool, len(top))
+	for i := range top {
+		if pinned[top[i].ID] {
+			top[i].Pinned = true
+			inTop[top[i].ID] = true
+		}
+	}
+
+	all := t.GetTopN(kind, metric, window, 0)
+	var extra []TopEntry
+	for _, e := range all {
+		if pinned[e.ID] && !inTop[e.ID] {
+			e.Pinned = true
+			extra = append(extra, e)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i].Avg > extra[j].Avg })
+
+	return append(top, extra...)
+}
@@ -0,0 +1,116 @@
+package proctrack
+
+import (
+	"testing"
+	"time"
+
+	"powermetrics-tui/internal/models"
+)
+
+func TestObserveAndGetTopN(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+
+	tr.Observe([]models.ProcessInfo{
+		{PID: 100, Name: "alpha", CPUPercent: 10, MemoryMB: 50},
+		{PID: 200, Name: "beta", CPUPercent: 40, MemoryMB: 20},
+	}, nil, now)
+	tr.Observe([]models.ProcessInfo{
+		{PID: 100, Name: "alpha", CPUPercent: 30, MemoryMB: 50},
+		{PID: 200, Name: "beta", CPUPercent: 20, MemoryMB: 20},
+	}, nil, now.Add(time.Second))
+
+	top := tr.GetTopN(KindProcess, MetricCPUPercent, Window60s, 10)
+	if len(top) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(top))
+	}
+	if top[0].ID != 200 || top[0].Avg != 30 {
+		t.Errorf("expected beta first with avg 30, got %+v", top[0])
+	}
+	if top[0].Max != 40 {
+		t.Errorf("expected beta max 40, got %v", top[0].Max)
+	}
+
+	topMem := tr.GetTopN(KindProcess, MetricMemoryMB, Window60s, 1)
+	if len(topMem) != 1 || topMem[0].ID != 100 {
+		t.Errorf("expected alpha to lead by memory, got %+v", topMem)
+	}
+}
+
+func TestObserveEvictsStaleEntries(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+
+	tr.Observe([]models.ProcessInfo{{PID: 1, Name: "gone", CPUPercent: 5}}, nil, now)
+	if got := tr.GetTopN(KindProcess, MetricCPUPercent, Window60s, 10); len(got) != 1 {
+		t.Fatalf("expected the pid to be tracked, got %d entries", len(got))
+	}
+
+	for i := 1; i <= evictAfter+1; i++ {
+		tr.Observe(nil, nil, now.Add(time.Duration(i)*time.Second))
+	}
+
+	if got := tr.GetTopN(KindProcess, MetricCPUPercent, Window60s, 10); len(got) != 0 {
+		t.Errorf("expected pid to be evicted after %d misses, got %d entries", evictAfter+1, len(got))
+	}
+}
+
+func TestGetTopNCoalitions(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+
+	tr.Observe(nil, []models.ProcessCoalition{
+		{CoalitionID: 7, Name: "com.apple.foo", CPUPercent: 15, MemoryMB: 100},
+	}, now)
+
+	procs := tr.GetTopN(KindProcess, MetricCPUPercent, Window60s, 10)
+	if len(procs) != 0 {
+		t.Errorf("expected no process entries, got %d", len(procs))
+	}
+
+	coalitions := tr.GetTopN(KindCoalition, MetricCPUPercent, Window60s, 10)
+	if len(coalitions) != 1 || coalitions[0].Name != "com.apple.foo" {
+		t.Errorf("expected the coalition to be tracked, got %+v", coalitions)
+	}
+}
+
+func TestGetTopNPinnedKeepsOutOfRangeEntries(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+
+	tr.Observe([]models.ProcessInfo{
+		{PID: 1, Name: "busy", CPUPercent: 90},
+		{PID: 2, Name: "idle-but-watched", CPUPercent: 1},
+		{PID: 3, Name: "also-busy", CPUPercent: 80},
+	}, nil, now)
+
+	top := tr.GetTopNPinned(KindProcess, MetricCPUPercent, Window60s, 2, map[int]bool{2: true})
+	if len(top) != 3 {
+		t.Fatalf("expected the pinned entry to be appended beyond n=2, got %d entries: %+v", len(top), top)
+	}
+
+	var sawPinned bool
+	for _, e := range top {
+		if e.ID == 2 {
+			sawPinned = true
+			if !e.Pinned {
+				t.Errorf("expected pid 2 to be marked Pinned")
+			}
+		}
+	}
+	if !sawPinned {
+		t.Error("expected pid 2 to be present despite ranking outside the top 2")
+	}
+}
+
+func TestGetTopNPinnedNoPinned(t *testing.T) {
+	tr := NewTracker()
+	now := time.Now()
+
+	tr.Observe([]models.ProcessInfo{{PID: 1, Name: "alpha", CPUPercent: 10}}, nil, now)
+
+	top := tr.GetTopNPinned(KindProcess, MetricCPUPercent, Window60s, 10, nil)
+	if len(top) != 1 || top[0].Pinned {
+		t.Errorf("expected an unpinned passthrough result, got %+v", top)
+	}
+}
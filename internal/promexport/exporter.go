@@ -0,0 +1,302 @@
+// Package promexport publishes the current models.MetricsState as a
+// Prometheus/OpenMetrics text-exposition endpoint, turning the tool into a
+// lightweight node-exporter equivalent for Apple Silicon power telemetry.
+package promexport
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/selector"
+)
+
+// thermalLevelNames lists powermetrics' thermal pressure strings in
+// increasing severity, so powermetrics_thermal_pressure always exposes one
+// time series per known level regardless of which is currently active.
+var thermalLevelNames = []string{"Nominal", "Moderate", "Heavy", "Trapping", "Sleeping", "Critical"}
+
+// Exporter serves a /metrics endpoint whose body is refreshed once per
+// finalized sample via Publish, so every scrape sees a consistent snapshot
+// rather than a value that changed mid-parse.
+type Exporter struct {
+	addr    string
+	minGap  time.Duration
+	lastPub time.Time
+
+	// CardinalityCap, if positive, limits how many per-process and
+	// per-coalition label series Publish emits: only the CardinalityCap
+	// highest by CPUPercent (see selector.TopNProcessesByCPU) get their own
+	// series, and everything else is folded into one synthetic
+	// pid="0",name="other" (or id="0",coalition="other") series summing
+	// their CPU%/memory, so a machine with thousands of short-lived
+	// processes doesn't blow up Prometheus' per-series storage. Zero means
+	// no cap - one series per process/coalition, as before this field
+	// existed.
+	CardinalityCap int
+
+	mu   sync.RWMutex
+	body []byte
+}
+
+// NewExporter creates an Exporter that will listen on addr (e.g. ":9101")
+// once Start is called. minGap throttles Publish so scrapes never see a
+// snapshot refreshed more often than once per minGap, even if the caller's
+// sampling interval (--interval) is much shorter; minGap of 0 republishes
+// on every call.
+func NewExporter(addr string, minGap time.Duration) *Exporter {
+	return &Exporter{addr: addr, minGap: minGap}
+}
+
+// Start begins serving /metrics in a background goroutine. Listen errors
+// (e.g. address already in use) are returned immediately; errors from the
+// server after that point are not fatal to the rest of the program.
+func (e *Exporter) Start() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.ServeHTTP)
+
+	ln, err := newListener(e.addr)
+	if err != nil {
+		return err
+	}
+
+	go http.Serve(ln, mux)
+	return nil
+}
+
+// ServeHTTP writes the most recently published snapshot.
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	body := e.body
+	e.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	w.Write(body)
+}
+
+// Publish renders state into Prometheus text format and caches it for the
+// next scrape. Call this once per finalized sample, e.g. from
+// StateMachine.FinalizeCurrentState. A call arriving before minGap has
+// elapsed since the last render is a no-op, so a fast --interval doesn't
+// force every sample through text-format rendering.
+func (e *Exporter) Publish(state *models.MetricsState) {
+	if e.minGap > 0 {
+		e.mu.RLock()
+		tooSoon := !e.lastPub.IsZero() && time.Since(e.lastPub) < e.minGap
+		e.mu.RUnlock()
+		if tooSoon {
+			return
+		}
+	}
+
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	var buf bytes.Buffer
+
+	writeGauge(&buf, "powermetrics_cpu_power_milliwatts", "CPU power draw in milliwatts", state.CPUPower, nil)
+	writeGauge(&buf, "powermetrics_gpu_power_milliwatts", "GPU power draw in milliwatts", state.GPUPower, nil)
+	writeGauge(&buf, "powermetrics_ane_power_milliwatts", "Apple Neural Engine power draw in milliwatts", state.ANEPower, nil)
+	writeGauge(&buf, "powermetrics_dram_power_milliwatts", "DRAM power draw in milliwatts", state.DRAMPower, nil)
+	writeGauge(&buf, "powermetrics_system_power_milliwatts", "Total system power draw in milliwatts", state.SystemPower, nil)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_core_frequency_mhz Active core frequency in MHz")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_core_frequency_mhz gauge")
+	for i, freq := range state.ECoreFreq {
+		fmt.Fprintf(&buf, "powermetrics_core_frequency_mhz{cluster=\"E\",core=\"%d\"} %d\n", i, freq)
+	}
+	for i, freq := range state.PCoreFreq {
+		fmt.Fprintf(&buf, "powermetrics_core_frequency_mhz{cluster=\"P\",core=\"%d\"} %d\n", i, freq)
+	}
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_cpu_frequency_mhz Per-CPU frequency in MHz, keyed by absolute CPU index rather than cluster-relative core")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_cpu_frequency_mhz gauge")
+	for cpu, freq := range state.AllCpuFreq {
+		fmt.Fprintf(&buf, "powermetrics_cpu_frequency_mhz{cpu=\"CPU%d\"} %d\n", cpu, freq)
+	}
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_per_cpu_interrupts Per-CPU interrupt rate")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_per_cpu_interrupts gauge")
+	for cpu, rate := range state.PerCPUInterrupts {
+		fmt.Fprintf(&buf, "powermetrics_per_cpu_interrupts{cpu=\"%s\"} %f\n", escapeLabel(cpu), rate)
+	}
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_interrupts_per_sec Per-CPU interrupt rate broken down by kind")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_interrupts_per_sec gauge")
+	for cpu, rate := range state.PerCPUInterrupts {
+		fmt.Fprintf(&buf, "powermetrics_interrupts_per_sec{cpu=\"%s\",kind=\"total\"} %f\n", escapeLabel(cpu), rate)
+	}
+	for cpu, rate := range state.PerCPUIPIs {
+		fmt.Fprintf(&buf, "powermetrics_interrupts_per_sec{cpu=\"%s\",kind=\"ipi\"} %f\n", escapeLabel(cpu), rate)
+	}
+	for cpu, rate := range state.PerCPUTimers {
+		fmt.Fprintf(&buf, "powermetrics_interrupts_per_sec{cpu=\"%s\",kind=\"timer\"} %f\n", escapeLabel(cpu), rate)
+	}
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_disk_bytes_total Cumulative disk bytes transferred")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_disk_bytes_total counter")
+	fmt.Fprintf(&buf, "powermetrics_disk_bytes_total{op=\"read\"} %f\n", state.DiskReadTotalBytes)
+	fmt.Fprintf(&buf, "powermetrics_disk_bytes_total{op=\"write\"} %f\n", state.DiskWriteTotalBytes)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_network_mb_rate Network throughput in MB/s, as reported by the current sample")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_network_mb_rate gauge")
+	fmt.Fprintf(&buf, "powermetrics_network_mb_rate{dir=\"in\"} %f\n", state.NetworkIn)
+	fmt.Fprintf(&buf, "powermetrics_network_mb_rate{dir=\"out\"} %f\n", state.NetworkOut)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_network_mb_total Cumulative network MB since the parser started")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_network_mb_total counter")
+	fmt.Fprintf(&buf, "powermetrics_network_mb_total{dir=\"in\"} %f\n", state.NetworkInTotal)
+	fmt.Fprintf(&buf, "powermetrics_network_mb_total{dir=\"out\"} %f\n", state.NetworkOutTotal)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_network_io_bytes_total Cumulative network bytes since the parser started, integrated against each sample's real interval")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_network_io_bytes_total counter")
+	fmt.Fprintf(&buf, "powermetrics_network_io_bytes_total{dir=\"in\"} %f\n", state.NetworkInTotalBytes)
+	fmt.Fprintf(&buf, "powermetrics_network_io_bytes_total{dir=\"out\"} %f\n", state.NetworkOutTotalBytes)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_disk_mb_rate Disk throughput in MB/s, as reported by the current sample")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_disk_mb_rate gauge")
+	fmt.Fprintf(&buf, "powermetrics_disk_mb_rate{op=\"read\"} %f\n", state.DiskRead)
+	fmt.Fprintf(&buf, "powermetrics_disk_mb_rate{op=\"write\"} %f\n", state.DiskWrite)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_disk_mb_total Cumulative disk MB since the parser started")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_disk_mb_total counter")
+	fmt.Fprintf(&buf, "powermetrics_disk_mb_total{op=\"read\"} %f\n", state.DiskReadTotal)
+	fmt.Fprintf(&buf, "powermetrics_disk_mb_total{op=\"write\"} %f\n", state.DiskWriteTotal)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_disk_io_bytes_total Cumulative disk bytes transferred since the parser started, integrated against each sample's real interval")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_disk_io_bytes_total counter")
+	fmt.Fprintf(&buf, "powermetrics_disk_io_bytes_total{op=\"read\"} %f\n", state.DiskReadTotalBytes)
+	fmt.Fprintf(&buf, "powermetrics_disk_io_bytes_total{op=\"write\"} %f\n", state.DiskWriteTotalBytes)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_thermal_pressure 1 for the current thermal pressure level, 0 for every other known level")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_thermal_pressure gauge")
+	for _, level := range thermalLevelNames {
+		active := 0.0
+		if level == state.ThermalPressure {
+			active = 1
+		}
+		fmt.Fprintf(&buf, "powermetrics_thermal_pressure{level=\"%s\"} %f\n", escapeLabel(level), active)
+	}
+
+	writeGauge(&buf, "powermetrics_battery_charge_percent", "Battery charge percentage", state.BatteryCharge, nil)
+
+	writeGauge(&buf, "powermetrics_memory_used_mb", "Memory used in MB", state.MemoryUsed, nil)
+	writeGauge(&buf, "powermetrics_memory_available_mb", "Memory available in MB", state.MemoryAvailable, nil)
+	writeGauge(&buf, "powermetrics_swap_used_mb", "Swap used in MB", state.SwapUsed, nil)
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_temperature_celsius Per-sensor temperature in degrees Celsius")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_temperature_celsius gauge")
+	for sensor, temp := range state.Temperature {
+		fmt.Fprintf(&buf, "powermetrics_temperature_celsius{sensor=\"%s\"} %f\n", escapeLabel(sensor), temp)
+	}
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_process_cpu_percent Per-process CPU percentage")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_process_cpu_percent gauge")
+	fmt.Fprintln(&buf, "# HELP powermetrics_process_memory_mb Per-process resident memory in MB")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_process_memory_mb gauge")
+	fmt.Fprintln(&buf, "# HELP powermetrics_process_cpu_ms Per-process CPU milliseconds per second, as reported by powermetrics")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_process_cpu_ms gauge")
+	fmt.Fprintln(&buf, "# HELP powermetrics_process_cpu_ms_total Cumulative per-process CPU milliseconds since the parser started")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_process_cpu_ms_total counter")
+	for _, proc := range e.capProcesses(state.Processes) {
+		labels := fmt.Sprintf("{pid=\"%d\",name=\"%s\",coalition=\"%s\"}", proc.PID, escapeLabel(proc.Name), escapeLabel(proc.CoalitionName))
+		fmt.Fprintf(&buf, "powermetrics_process_cpu_percent%s %f\n", labels, proc.CPUPercent)
+		fmt.Fprintf(&buf, "powermetrics_process_memory_mb%s %f\n", labels, proc.MemoryMB)
+		fmt.Fprintf(&buf, "powermetrics_process_cpu_ms%s %f\n", labels, proc.CPUPercent*10)
+		fmt.Fprintf(&buf, "powermetrics_process_cpu_ms_total%s %f\n", labels, proc.CPUMsTotal)
+	}
+
+	fmt.Fprintln(&buf, "# HELP powermetrics_coalition_cpu_percent Per-coalition CPU percentage")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_coalition_cpu_percent gauge")
+	fmt.Fprintln(&buf, "# HELP powermetrics_coalition_memory_mb Per-coalition resident memory in MB")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_coalition_memory_mb gauge")
+	fmt.Fprintln(&buf, "# HELP powermetrics_coalition_cpu_ms_total Cumulative per-coalition CPU milliseconds since the parser started")
+	fmt.Fprintln(&buf, "# TYPE powermetrics_coalition_cpu_ms_total counter")
+	for _, co := range e.capCoalitions(state.Coalitions) {
+		labels := fmt.Sprintf("{coalition=\"%s\",id=\"%d\"}", escapeLabel(co.Name), co.CoalitionID)
+		fmt.Fprintf(&buf, "powermetrics_coalition_cpu_percent%s %f\n", labels, co.CPUPercent)
+		fmt.Fprintf(&buf, "powermetrics_coalition_memory_mb%s %f\n", labels, co.MemoryMB)
+		fmt.Fprintf(&buf, "powermetrics_coalition_cpu_ms_total%s %f\n", labels, co.CPUMsTotal)
+	}
+
+	e.mu.Lock()
+	e.body = buf.Bytes()
+	e.lastPub = time.Now()
+	e.mu.Unlock()
+}
+
+// capProcesses returns processes unchanged if e.CardinalityCap is unset;
+// otherwise it keeps the CardinalityCap highest by CPUPercent and folds
+// everything else into one synthetic "other" entry summing their CPU%/
+// memory/CPU-ms, so the low end of a long process tail collapses to a
+// single label series instead of one per PID.
+func (e *Exporter) capProcesses(processes []models.ProcessInfo) []models.ProcessInfo {
+	if e.CardinalityCap <= 0 || e.CardinalityCap >= len(processes) {
+		return processes
+	}
+
+	kept := selector.TopNProcessesByCPU(processes, e.CardinalityCap)
+	keptPIDs := make(map[int]bool, len(kept))
+	for _, p := range kept {
+		keptPIDs[p.PID] = true
+	}
+
+	other := models.ProcessInfo{Name: "other", CoalitionName: "other"}
+	for _, p := range processes {
+		if keptPIDs[p.PID] {
+			continue
+		}
+		other.CPUPercent += p.CPUPercent
+		other.MemoryMB += p.MemoryMB
+		other.CPUMsTotal += p.CPUMsTotal
+	}
+
+	return append(append([]models.ProcessInfo(nil), kept...), other)
+}
+
+// capCoalitions is capProcesses' coalition counterpart.
+func (e *Exporter) capCoalitions(coalitions []models.ProcessCoalition) []models.ProcessCoalition {
+	if e.CardinalityCap <= 0 || e.CardinalityCap >= len(coalitions) {
+		return coalitions
+	}
+
+	ranked := append([]models.ProcessCoalition(nil), coalitions...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].CPUPercent > ranked[j].CPUPercent
+	})
+	kept := ranked[:e.CardinalityCap]
+	keptIDs := make(map[int]bool, len(kept))
+	for _, co := range kept {
+		keptIDs[co.CoalitionID] = true
+	}
+
+	other := models.ProcessCoalition{Name: "other", CoalitionID: 0}
+	for _, co := range ranked[e.CardinalityCap:] {
+		other.CPUPercent += co.CPUPercent
+		other.MemoryMB += co.MemoryMB
+		other.CPUMsTotal += co.CPUMsTotal
+	}
+
+	return append(append([]models.ProcessCoalition(nil), kept...), other)
+}
+
+func writeGauge(buf *bytes.Buffer, name, help string, value float64, labels map[string]string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(buf, "%s %f\n", name, value)
+}
+
+func escapeLabel(s string) string {
+	var buf bytes.Buffer
+	for _, r := range s {
+		if r == '"' || r == '\\' {
+			buf.WriteRune('\\')
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
@@ -0,0 +1,9 @@
+package promexport
+
+import "net"
+
+// newListener is split out so tests can exercise Exporter without binding a
+// real network port by substituting a different dialer if needed later.
+func newListener(addr string) (net.Listener, error) {
+	return net.Listen("tcp", addr)
+}
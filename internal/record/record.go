@@ -0,0 +1,91 @@
+// Package record implements capture and playback of raw `powermetrics`
+// output so bug reports are reproducible and the UI/parser can be developed
+// without a Mac. A recording is a small JSON header describing the host and
+// sampler args, followed by one framed chunk per invocation of
+// `powermetrics -n 1`, each tagged with its offset from the start of
+// capture so replay can honor the original inter-sample timing.
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// Header captures the context a recording was made in, so replays and bug
+// reports can be interpreted later without guessing at the environment.
+type Header struct {
+	Host                string    `json:"host"`
+	PowermetricsVersion string    `json:"powermetrics_version"`
+	SamplerArgs         string    `json:"sampler_args"`
+	StartTime           time.Time `json:"start_time"`
+}
+
+// frame is the on-disk shape of one captured sample.
+type frame struct {
+	OffsetMs int64  `json:"offset_ms"`
+	Length   int    `json:"length"`
+}
+
+// Recorder tees raw powermetrics output to disk as a sequence of framed,
+// timestamped chunks.
+type Recorder struct {
+	w     *bufio.Writer
+	f     *os.File
+	start time.Time
+}
+
+// NewRecorder creates path (truncating any existing file), writes header,
+// and returns a Recorder ready to accept samples via Write.
+func NewRecorder(path string, header Header) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	w := bufio.NewWriter(f)
+	headerLine, err := json.Marshal(header)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := fmt.Fprintln(w, string(headerLine)); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return &Recorder{w: w, f: f, start: header.StartTime}, nil
+}
+
+// Write appends one raw powermetrics output chunk, framed with its offset
+// from recording start.
+func (r *Recorder) Write(output string) error {
+	fr := frame{
+		OffsetMs: time.Since(r.start).Milliseconds(),
+		Length:   len(output),
+	}
+	frameLine, err := json.Marshal(fr)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(r.w, string(frameLine)); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(r.w, output); err != nil {
+		return err
+	}
+	_, err = r.w.WriteString("\n")
+	return err
+}
+
+// Close flushes buffered output and closes the underlying file.
+func (r *Recorder) Close() error {
+	if err := r.w.Flush(); err != nil {
+		r.f.Close()
+		return err
+	}
+	return r.f.Close()
+}
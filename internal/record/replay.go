@@ -0,0 +1,265 @@
+package record
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"powermetrics-tui/internal/parser"
+)
+
+// PlaybackControl lets a caller pause, resume, and seek an in-progress
+// Replay from another goroutine, e.g. the TUI's key-event handler reacting
+// to Space/←/→. The zero value is unpaused, at position 0, with no pending
+// seek. A nil *PlaybackControl is always unpaused and ignores seeks, so
+// passing one is optional.
+type PlaybackControl struct {
+	paused int32
+	posMs  int64
+	seekMs int64
+}
+
+// SetPaused pauses or resumes playback.
+func (c *PlaybackControl) SetPaused(paused bool) {
+	if c == nil {
+		return
+	}
+	var v int32
+	if paused {
+		v = 1
+	}
+	atomic.StoreInt32(&c.paused, v)
+}
+
+// Paused reports whether playback is currently paused.
+func (c *PlaybackControl) Paused() bool {
+	if c == nil {
+		return false
+	}
+	return atomic.LoadInt32(&c.paused) == 1
+}
+
+// RequestSeek asks Replay to jump forward (positive d) or backward
+// (negative d) relative to the current position, e.g. ±10s for a ←/→
+// keybinding. Multiple calls before Replay drains the request accumulate.
+func (c *PlaybackControl) RequestSeek(d time.Duration) {
+	if c == nil {
+		return
+	}
+	atomic.AddInt64(&c.seekMs, d.Milliseconds())
+}
+
+// Position reports how far into the recording playback has reached, for a
+// status line.
+func (c *PlaybackControl) Position() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(atomic.LoadInt64(&c.posMs)) * time.Millisecond
+}
+
+func (c *PlaybackControl) setPosition(d time.Duration) {
+	if c == nil {
+		return
+	}
+	atomic.StoreInt64(&c.posMs, d.Milliseconds())
+}
+
+func (c *PlaybackControl) hasPendingSeek() bool {
+	if c == nil {
+		return false
+	}
+	return atomic.LoadInt64(&c.seekMs) != 0
+}
+
+func (c *PlaybackControl) drainSeek() time.Duration {
+	if c == nil {
+		return 0
+	}
+	return time.Duration(atomic.SwapInt64(&c.seekMs, 0)) * time.Millisecond
+}
+
+// Sample is one decoded chunk from a recording: the offset it was captured
+// at and the raw powermetrics text for that invocation.
+type Sample struct {
+	Offset time.Duration
+	Output string
+}
+
+// Reader parses a recording written by Recorder.
+type Reader struct {
+	Header Header
+
+	br *bufio.Reader
+}
+
+// Open reads the header from path and returns a Reader positioned at the
+// first sample frame.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	br := bufio.NewReader(f)
+	headerLine, err := br.ReadString('\n')
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	var header Header
+	if err := json.Unmarshal([]byte(headerLine), &header); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("record: invalid header: %w", err)
+	}
+
+	return &Reader{Header: header, br: br}, nil
+}
+
+// Next returns the next sample, or io.EOF when the recording is exhausted.
+func (r *Reader) Next() (Sample, error) {
+	frameLine, err := r.br.ReadString('\n')
+	if err != nil {
+		return Sample{}, err
+	}
+
+	var fr frame
+	if err := json.Unmarshal([]byte(frameLine), &fr); err != nil {
+		return Sample{}, fmt.Errorf("record: invalid frame: %w", err)
+	}
+
+	buf := make([]byte, fr.Length)
+	if _, err := io.ReadFull(r.br, buf); err != nil {
+		return Sample{}, err
+	}
+	// Skip the trailing newline written after each chunk.
+	if _, err := r.br.ReadByte(); err != nil && err != io.EOF {
+		return Sample{}, err
+	}
+
+	return Sample{Offset: time.Duration(fr.OffsetMs) * time.Millisecond, Output: string(buf)}, nil
+}
+
+// Replay drives sm.ProcessLine with every line of every sample in the
+// recording, honoring the original inter-sample timing scaled by speed
+// (speed > 1 replays faster than real time, e.g. --replay-speed=4x -> 4).
+// FinalizeCurrentState is called once per sample, matching how a complete
+// powermetrics invocation is committed during live collection. ctrl may be
+// nil; when non-nil, playback blocks before each sample while ctrl.Paused(),
+// and honors ctrl.RequestSeek by jumping to the requested offset via seekTo.
+func Replay(path string, sm *parser.StateMachine, speed float64, ctrl *PlaybackControl) error {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	r, err := Open(path)
+	if err != nil {
+		return err
+	}
+
+	var lastOffset time.Duration
+	for {
+		if delta := ctrl.drainSeek(); delta != 0 {
+			target := lastOffset + delta
+			if target < 0 {
+				target = 0
+			}
+			newR, reached, err := seekTo(path, sm, target)
+			if err != nil {
+				return err
+			}
+			r = newR
+			lastOffset = reached
+			ctrl.setPosition(lastOffset)
+		}
+
+		sample, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for ctrl.Paused() && !ctrl.hasPendingSeek() {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if wait := sample.Offset - lastOffset; wait > 0 {
+			time.Sleep(time.Duration(float64(wait) / speed))
+		}
+		lastOffset = sample.Offset
+		ctrl.setPosition(lastOffset)
+
+		for _, line := range strings.Split(sample.Output, "\n") {
+			if err := sm.ProcessLine(line); err != nil {
+				return err
+			}
+		}
+		sm.FinalizeCurrentState()
+	}
+}
+
+// seekTo rebuilds sm's state from the start of the recording up through the
+// sample that reaches target, without Replay's real-time pacing, so a seek
+// lands on a MetricsState with history/sparklines populated exactly as if
+// playback had reached that point normally rather than jumping there cold.
+// Returns a Reader positioned to continue normal playback from there, and
+// the offset actually reached (the first sample at or past target, since a
+// already-read Reader can't be partially rewound to land exactly on it).
+func seekTo(path string, sm *parser.StateMachine, target time.Duration) (*Reader, time.Duration, error) {
+	r, err := Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var reached time.Duration
+	for {
+		sample, err := r.Next()
+		if err == io.EOF {
+			return r, reached, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, line := range strings.Split(sample.Output, "\n") {
+			if err := sm.ProcessLine(line); err != nil {
+				return nil, 0, err
+			}
+		}
+		sm.FinalizeCurrentState()
+		reached = sample.Offset
+
+		if sample.Offset >= target {
+			return r, reached, nil
+		}
+	}
+}
+
+// Duration reports the offset of a recording's last sample, for a replay
+// status line's "time remaining" denominator.
+func Duration(path string) (time.Duration, error) {
+	r, err := Open(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var last time.Duration
+	for {
+		sample, err := r.Next()
+		if err == io.EOF {
+			return last, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		last = sample.Offset
+	}
+}
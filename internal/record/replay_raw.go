@@ -0,0 +1,92 @@
+package record
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	"powermetrics-tui/internal/parser"
+)
+
+// ReplayRawText drives sm with a raw powermetrics text capture - e.g. one
+// made by hand with `sudo powermetrics ... | tee capture.txt`, rather than
+// through Recorder - instead of the JSON-framed format Open/Reader expect.
+// The file is split into samples at each "*** Sampled system activity"
+// header, and playback is paced using that header's own "(N ms elapsed)"
+// duration (see parser.ParseSampleElapsed) scaled by speed, so a capture
+// replays at the cadence it was actually taken at. This is what lets a
+// user-reported bug or a real corpus be replayed without ever having used
+// --record to capture it.
+func ReplayRawText(path string, sm *parser.StateMachine, speed float64, ctrl *PlaybackControl) error {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	for _, sample := range splitRawSamples(string(data)) {
+		for ctrl.Paused() {
+			time.Sleep(100 * time.Millisecond)
+		}
+
+		if wait := sample.elapsed; wait > 0 {
+			time.Sleep(time.Duration(float64(wait) / speed))
+		}
+
+		for _, line := range sample.lines {
+			if err := sm.ProcessLine(line); err != nil {
+				return err
+			}
+		}
+		sm.FinalizeCurrentState()
+	}
+	return nil
+}
+
+// rawSample is one "*** Sampled system activity" block from a raw capture,
+// along with the elapsed duration its own header reported (0 for the first
+// sample in a file, which has no preceding sample to be elapsed since).
+type rawSample struct {
+	lines   []string
+	elapsed time.Duration
+}
+
+// splitRawSamples breaks a raw powermetrics capture into per-sample blocks
+// at each new-sample header line.
+func splitRawSamples(text string) []rawSample {
+	var samples []rawSample
+	var current []string
+
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		var elapsed time.Duration
+		for _, line := range current {
+			if d, ok := parser.ParseSampleElapsed(line); ok {
+				elapsed = d
+				break
+			}
+		}
+		samples = append(samples, rawSample{lines: current, elapsed: elapsed})
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if parser.IsNewSample(line) && len(current) > 0 {
+			flush()
+			current = nil
+		}
+		current = append(current, line)
+	}
+	flush()
+
+	// The first sample's header has nothing preceding it to be elapsed
+	// since, so don't wait before replaying it.
+	if len(samples) > 0 {
+		samples[0].elapsed = 0
+	}
+	return samples
+}
@@ -0,0 +1,209 @@
+package remote
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"powermetrics-tui/internal/models"
+)
+
+// initialBackoff and maxBackoff bound the exponential reconnect delay a
+// Client uses after a dropped or refused connection.
+const (
+	initialBackoff = time.Second
+	maxBackoff     = 30 * time.Second
+)
+
+// Status summarizes a Client's connection health for a UI indicator.
+type Status struct {
+	Connected   bool
+	Latency     time.Duration // time.Since(state.LastUpdate) as of the most recent frame
+	Dropped     uint64        // cumulative frames the Server reported dropping for us (Seq gaps)
+	ReconnectIn time.Duration // length of the backoff wait before the next dial attempt, while disconnected
+	LastError   string
+}
+
+// Client dials a Server, decodes each frame into a shared
+// models.MetricsState, and reconnects with exponential backoff whenever the
+// connection drops.
+type Client struct {
+	addr      string
+	authToken string
+	tlsConfig *tls.Config
+
+	mu     sync.Mutex
+	status Status
+
+	stopCh chan struct{}
+}
+
+// NewClient creates a Client that will dial addr once Start is called.
+// tlsConfig may be nil for plaintext.
+func NewClient(addr, authToken string, tlsConfig *tls.Config) *Client {
+	return &Client{
+		addr:      addr,
+		authToken: authToken,
+		tlsConfig: tlsConfig,
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// Start begins the connect-stream-reconnect loop in a background
+// goroutine, filling state from whatever Server it reaches at addr.
+func (c *Client) Start(state *models.MetricsState) {
+	go c.run(state)
+}
+
+// Stop ends the reconnect loop and closes any active connection.
+func (c *Client) Stop() {
+	close(c.stopCh)
+}
+
+// Status returns a snapshot of the Client's current connection health.
+func (c *Client) Status() Status {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.status
+}
+
+func (c *Client) setStatus(fn func(*Status)) {
+	c.mu.Lock()
+	fn(&c.status)
+	c.mu.Unlock()
+}
+
+func (c *Client) run(state *models.MetricsState) {
+	backoff := initialBackoff
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		default:
+		}
+
+		err := c.connectAndStream(state, &backoff)
+		c.setStatus(func(s *Status) {
+			s.Connected = false
+			s.ReconnectIn = backoff
+			if err != nil {
+				s.LastError = err.Error()
+			}
+		})
+
+		select {
+		case <-c.stopCh:
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+		c.setStatus(func(s *Status) { s.ReconnectIn = backoff })
+	}
+}
+
+// connectAndStream dials once, performs the handshake, and decodes frames
+// into state until the connection breaks or Stop is called. backoff is
+// reset to initialBackoff as soon as the handshake succeeds, so a
+// connection that stays up for a while doesn't inherit a long delay from
+// an earlier failure.
+func (c *Client) connectAndStream(state *models.MetricsState, backoff *time.Duration) error {
+	var conn net.Conn
+	var err error
+	if c.tlsConfig != nil {
+		conn, err = tls.Dial("tcp", c.addr, c.tlsConfig)
+	} else {
+		conn, err = net.Dial("tcp", c.addr)
+	}
+	if err != nil {
+		return fmt.Errorf("remote: dial %s: %w", c.addr, err)
+	}
+	defer conn.Close()
+
+	authLine, err := json.Marshal(authRequest{Token: c.authToken})
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(conn, string(authLine)); err != nil {
+		return fmt.Errorf("remote: send auth: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	respLine, err := br.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("remote: read auth response: %w", err)
+	}
+	var resp authResponse
+	if err := json.Unmarshal([]byte(respLine), &resp); err != nil {
+		return fmt.Errorf("remote: invalid auth response: %w", err)
+	}
+	if !resp.OK {
+		return fmt.Errorf("remote: server rejected connection: %s", resp.Error)
+	}
+
+	hsLine, err := br.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("remote: read handshake: %w", err)
+	}
+	var hs Handshake
+	if err := json.Unmarshal([]byte(hsLine), &hs); err != nil {
+		return fmt.Errorf("remote: invalid handshake: %w", err)
+	}
+	if hs.SchemaVersion != SchemaVersion {
+		return fmt.Errorf("remote: server schema version %d, client expects %d", hs.SchemaVersion, SchemaVersion)
+	}
+
+	*backoff = initialBackoff
+	c.setStatus(func(s *Status) {
+		s.Connected = true
+		s.LastError = ""
+		s.ReconnectIn = 0
+	})
+
+	var lastSeq uint64
+	first := true
+	for {
+		select {
+		case <-c.stopCh:
+			return nil
+		default:
+		}
+
+		payload, err := readFrame(br)
+		if err != nil {
+			return fmt.Errorf("remote: read frame: %w", err)
+		}
+
+		var env envelope
+		if err := json.Unmarshal(payload, &env); err != nil {
+			return fmt.Errorf("remote: invalid envelope: %w", err)
+		}
+
+		var dropped uint64
+		if !first && env.Seq > lastSeq+1 {
+			dropped = env.Seq - lastSeq - 1
+		}
+		first = false
+		lastSeq = env.Seq
+
+		state.Mu.Lock()
+		err = json.Unmarshal(env.State, state)
+		state.Mu.Unlock()
+		if err != nil {
+			return fmt.Errorf("remote: invalid state snapshot: %w", err)
+		}
+
+		latency := time.Since(state.LastUpdate)
+		c.setStatus(func(s *Status) {
+			s.Latency = latency
+			s.Dropped += dropped
+		})
+	}
+}
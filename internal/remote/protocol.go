@@ -0,0 +1,109 @@
+// Package remote implements the client/server split behind --serve and
+// --connect: a Server re-broadcasts every finalized models.MetricsState to
+// any number of read-only TCP subscribers, and a Client dials in and fills
+// its own models.MetricsState from the wire. The UI never has to know the
+// difference - every Draw*ViewWithStartY function keeps reading the same
+// struct it always has, whether that struct was filled by powermetrics or
+// by a Client.
+//
+// Frames mirror the on-disk format internal/record already uses for
+// --record/--replay: a JSON header line giving the payload length, that
+// many raw bytes, then a trailing newline. Payloads are JSON, reusing
+// models.MetricsState's existing `json:"..."` tags (internal/jsonexport
+// marshals the same struct for --export), not msgpack or gob - gob chokes
+// on the embedded sync.RWMutex and this tree has no msgpack dependency
+// vendored. One consequence of reusing those tags: history/sparkline
+// fields tagged `json:"-"` aren't sent over the wire, so a connected
+// client only gets each sample's instantaneous values. Every Draw*View
+// already guards `len(history) > 0` before drawing a sparkline, so this
+// degrades gracefully rather than crashing.
+package remote
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// SchemaVersion identifies the wire-compatible subset of
+// models.MetricsState's JSON shape. A Client refuses to stream from a
+// Server advertising a different version.
+const SchemaVersion = 1
+
+// Handshake is the line a Server sends once a connection is authorized,
+// before any sample frames follow.
+type Handshake struct {
+	SchemaVersion int    `json:"schema_version"`
+	Samplers      string `json:"samplers"`
+}
+
+// authRequest is the first line a Client always sends, even with an empty
+// Token, so the Server always has exactly one line to read before deciding
+// whether to proceed.
+type authRequest struct {
+	Token string `json:"token"`
+}
+
+// authResponse tells the Client whether its authRequest was accepted. The
+// connection is closed immediately after this is written when OK is false.
+type authResponse struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// envelope wraps each published snapshot with a monotonically increasing
+// sequence number, so a Client can notice gaps - samples the Server's
+// per-subscriber queue had to drop - without the Server needing a separate
+// side channel to report them.
+type envelope struct {
+	Seq   uint64          `json:"seq"`
+	State json.RawMessage `json:"state"`
+}
+
+// frame is the length header written before every payload, the same shape
+// internal/record uses for its on-disk frames.
+type frame struct {
+	Length int `json:"length"`
+}
+
+// writeFrame writes one length-prefixed frame: a header line giving the
+// payload length, the payload, and a trailing newline.
+func writeFrame(w io.Writer, payload []byte) error {
+	header, err := json.Marshal(frame{Length: len(payload)})
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(w, string(header)); err != nil {
+		return err
+	}
+	if _, err := w.Write(payload); err != nil {
+		return err
+	}
+	_, err = w.Write([]byte("\n"))
+	return err
+}
+
+// readFrame reads one frame written by writeFrame.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	headerLine, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	var fr frame
+	if err := json.Unmarshal([]byte(headerLine), &fr); err != nil {
+		return nil, fmt.Errorf("remote: invalid frame header: %w", err)
+	}
+
+	buf := make([]byte, fr.Length)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	// Skip the trailing newline written after the payload.
+	if _, err := r.ReadByte(); err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return buf, nil
+}
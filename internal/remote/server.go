@@ -0,0 +1,183 @@
+package remote
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+
+	"powermetrics-tui/internal/models"
+)
+
+// subscriberQueue bounds how many unsent frames Publish buffers for a slow
+// subscriber before it starts dropping frames for that subscriber rather
+// than blocking every other subscriber - or the sampler goroutine calling
+// Publish - on one stalled TCP connection.
+const subscriberQueue = 4
+
+// Server runs the collection-side half of --serve: it accepts any number of
+// read-only TCP subscribers and re-broadcasts every models.MetricsState
+// Publish is called with.
+type Server struct {
+	addr      string
+	authToken string
+	tlsConfig *tls.Config
+	samplers  string
+
+	seq uint64
+
+	mu          sync.Mutex
+	subscribers map[chan []byte]struct{}
+	ln          net.Listener
+}
+
+// NewServer creates a Server that will listen on addr once Start is
+// called. tlsConfig may be nil for plaintext. authToken, if non-empty, is
+// required (via the authRequest handshake) from every connecting client.
+// samplers is advertised to clients in the Handshake for display only.
+func NewServer(addr, authToken string, tlsConfig *tls.Config, samplers string) *Server {
+	return &Server{
+		addr:        addr,
+		authToken:   authToken,
+		tlsConfig:   tlsConfig,
+		samplers:    samplers,
+		subscribers: make(map[chan []byte]struct{}),
+	}
+}
+
+// Start begins accepting connections in a background goroutine. Listen
+// errors (e.g. address already in use) are returned immediately; errors
+// from individual connections after that point are not fatal to the rest
+// of the program.
+func (s *Server) Start() error {
+	var ln net.Listener
+	var err error
+	if s.tlsConfig != nil {
+		ln, err = tls.Listen("tcp", s.addr, s.tlsConfig)
+	} else {
+		ln, err = net.Listen("tcp", s.addr)
+	}
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go s.handleConn(conn)
+		}
+	}()
+	return nil
+}
+
+// Close stops accepting new connections and drops any subscribers still
+// attached, ending their handleConn goroutines.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	for ch := range s.subscribers {
+		close(ch)
+		delete(s.subscribers, ch)
+	}
+	s.mu.Unlock()
+
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Close()
+}
+
+// handleConn authorizes one connection, sends the Handshake, then
+// relays every frame Publish hands it until the connection breaks.
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	br := bufio.NewReader(conn)
+	authLine, err := br.ReadString('\n')
+	if err != nil {
+		return
+	}
+
+	var req authRequest
+	if err := json.Unmarshal([]byte(authLine), &req); err != nil {
+		return
+	}
+	if s.authToken != "" && subtle.ConstantTimeCompare([]byte(req.Token), []byte(s.authToken)) != 1 {
+		resp, _ := json.Marshal(authResponse{OK: false, Error: "invalid auth token"})
+		fmt.Fprintln(conn, string(resp))
+		return
+	}
+
+	resp, err := json.Marshal(authResponse{OK: true})
+	if err != nil {
+		return
+	}
+	if _, err := fmt.Fprintln(conn, string(resp)); err != nil {
+		return
+	}
+
+	hs, err := json.Marshal(Handshake{SchemaVersion: SchemaVersion, Samplers: s.samplers})
+	if err != nil {
+		return
+	}
+	if _, err := fmt.Fprintln(conn, string(hs)); err != nil {
+		return
+	}
+
+	ch := make(chan []byte, subscriberQueue)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.subscribers, ch)
+		s.mu.Unlock()
+	}()
+
+	for payload := range ch {
+		if err := writeFrame(conn, payload); err != nil {
+			return
+		}
+	}
+}
+
+// Publish renders state as JSON, wraps it with the next sequence number,
+// and fans it out to every subscriber. A subscriber whose queue is still
+// full from the previous sample has this frame dropped for it rather than
+// blocking the caller - typically the same sampler goroutine that's also
+// updating state for the local TUI.
+func (s *Server) Publish(state *models.MetricsState) error {
+	state.Mu.RLock()
+	stateJSON, err := json.Marshal(state)
+	state.Mu.RUnlock()
+	if err != nil {
+		return fmt.Errorf("remote: marshal snapshot: %w", err)
+	}
+
+	payload, err := json.Marshal(envelope{
+		Seq:   atomic.AddUint64(&s.seq, 1),
+		State: stateJSON,
+	})
+	if err != nil {
+		return fmt.Errorf("remote: marshal envelope: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for ch := range s.subscribers {
+		select {
+		case ch <- payload:
+		default:
+			// Subscriber isn't keeping up; drop this frame for it. The
+			// Client notices the resulting Seq gap and counts it.
+		}
+	}
+	return nil
+}
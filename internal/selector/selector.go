@@ -0,0 +1,358 @@
+// Package selector restricts which processes/coalitions are tracked,
+// turning the tool from a full-system view into a targeted monitor for one
+// app or user session. The matcher kinds (name pattern, regex, pidfile,
+// effective user, executable path, and macOS bundle ID) are borrowed from
+// telegraf's procstat PIDFinder.
+package selector
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"howett.net/plist"
+
+	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/procfind"
+)
+
+// ProcessSelector is something that can accept or reject a process/
+// coalition by name, PID, or coalition name. A matcher that doesn't examine
+// one of the three should return true for it, so combining matchers with
+// Set's AND semantics narrows down rather than vetoes by default.
+type ProcessSelector interface {
+	MatchName(name string) bool
+	MatchPID(pid int) bool
+	MatchCoalition(name string) bool
+}
+
+// NamePattern matches process/coalition names against a shell glob (e.g.
+// "com.apple.*"), via path.Match semantics.
+type NamePattern string
+
+func (p NamePattern) MatchName(name string) bool {
+	ok, _ := filepath.Match(string(p), name)
+	return ok
+}
+func (p NamePattern) MatchPID(int) bool               { return true }
+func (p NamePattern) MatchCoalition(name string) bool { return p.MatchName(name) }
+
+// NameRegex matches process/coalition names against a compiled regular
+// expression, for patterns a glob can't express (e.g. "^(Chrome|ghostty)$").
+type NameRegex struct {
+	re *regexp.Regexp
+}
+
+// NewNameRegex compiles pattern into a NameRegex matcher.
+func NewNameRegex(pattern string) (NameRegex, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return NameRegex{}, fmt.Errorf("filter regex %q: %w", pattern, err)
+	}
+	return NameRegex{re: re}, nil
+}
+
+func (r NameRegex) MatchName(name string) bool      { return r.re.MatchString(name) }
+func (r NameRegex) MatchPID(int) bool               { return true }
+func (r NameRegex) MatchCoalition(name string) bool { return r.re.MatchString(name) }
+
+// PIDFile matches processes whose PID appears in a file listing one PID per
+// line, e.g. a set of services' /var/run/*.pid files concatenated ahead of
+// time. It never restricts names/coalitions.
+type PIDFile struct {
+	pids map[int]bool
+}
+
+// LoadPIDFile reads path and returns a PIDFile matcher for the PIDs it
+// lists, one per line; blank lines are skipped.
+func LoadPIDFile(path string) (PIDFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return PIDFile{}, fmt.Errorf("filter pid file %q: %w", path, err)
+	}
+	pids := make(map[int]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		pid, err := strconv.Atoi(line)
+		if err != nil {
+			return PIDFile{}, fmt.Errorf("filter pid file %q: invalid pid %q", path, line)
+		}
+		pids[pid] = true
+	}
+	return PIDFile{pids: pids}, nil
+}
+
+func (f PIDFile) MatchName(string) bool      { return true }
+func (f PIDFile) MatchPID(pid int) bool      { return f.pids[pid] }
+func (f PIDFile) MatchCoalition(string) bool { return true }
+
+// EffectiveUser matches processes running as a given username, resolved to
+// a uid via os/user and compared against the configured procfind.Finder's
+// OwnerUID, the same pluggable gopsutil/ps backend RunningTasksHandler uses
+// for dead-PID enrichment, rather than shelling out to `ps` on its own.
+type EffectiveUser struct {
+	uid    string
+	finder procfind.Finder
+}
+
+// NewEffectiveUser resolves username to a uid, matching pids via finder's
+// OwnerUID.
+func NewEffectiveUser(finder procfind.Finder, username string) (EffectiveUser, error) {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return EffectiveUser{}, fmt.Errorf("filter user %q: %w", username, err)
+	}
+	return EffectiveUser{uid: u.Uid, finder: finder}, nil
+}
+
+func (e EffectiveUser) MatchName(string) bool      { return true }
+func (e EffectiveUser) MatchCoalition(string) bool { return true }
+
+func (e EffectiveUser) MatchPID(pid int) bool {
+	uid, err := e.finder.OwnerUID(pid)
+	if err != nil {
+		return false
+	}
+	return uid == e.uid
+}
+
+// ExePattern matches processes whose resolved executable path (or, failing
+// that, command line - see procfind.Finder.EnrichName) satisfies a shell
+// glob, e.g. "*/Chromium.app/*" to catch every helper process inside a
+// bundle regardless of its task name.
+type ExePattern struct {
+	pattern string
+	finder  procfind.Finder
+}
+
+// NewExePattern builds an ExePattern matcher, matching pids via finder's
+// EnrichName.
+func NewExePattern(finder procfind.Finder, pattern string) ExePattern {
+	return ExePattern{pattern: pattern, finder: finder}
+}
+
+func (e ExePattern) MatchName(string) bool      { return true }
+func (e ExePattern) MatchCoalition(string) bool { return true }
+
+func (e ExePattern) MatchPID(pid int) bool {
+	exe, err := e.finder.EnrichName(pid)
+	if err != nil {
+		return false
+	}
+	return globMatchAnySegment(e.pattern, exe)
+}
+
+// globMatchAnySegment matches pattern against s using shell-glob syntax,
+// except that unlike path/filepath.Match, "*" is allowed to span "/"
+// separators. Bundle-relative patterns like "*/Chromium.app/*" are meant
+// to match a resolved executable several directories below the bundle
+// root (e.g. ".../Chromium.app/Contents/MacOS/Chromium Helper"), which
+// filepath.Match's per-segment "*" can never do.
+func globMatchAnySegment(pattern, s string) bool {
+	var re strings.Builder
+	re.WriteByte('^')
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			re.WriteString(".*")
+		case '?':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	re.WriteByte('$')
+	matched, err := regexp.MatchString(re.String(), s)
+	return err == nil && matched
+}
+
+// BundleIDMatch matches processes launched from a macOS .app bundle whose
+// Info.plist declares a given CFBundleIdentifier, e.g. "com.google.Chrome" -
+// a stable identifier across app updates/renames, unlike the task name
+// powermetrics reports.
+type BundleIDMatch struct {
+	bundleID string
+	finder   procfind.Finder
+}
+
+// NewBundleIDMatch builds a BundleIDMatch matcher, matching pids via
+// finder's EnrichName.
+func NewBundleIDMatch(finder procfind.Finder, bundleID string) BundleIDMatch {
+	return BundleIDMatch{bundleID: bundleID, finder: finder}
+}
+
+func (b BundleIDMatch) MatchName(string) bool      { return true }
+func (b BundleIDMatch) MatchCoalition(string) bool { return true }
+
+func (b BundleIDMatch) MatchPID(pid int) bool {
+	exe, err := b.finder.EnrichName(pid)
+	if err != nil {
+		return false
+	}
+	id, ok := bundleIdentifier(exe)
+	return ok && id == b.bundleID
+}
+
+// bundleIdentifier walks exe's path upward looking for a "*.app" component,
+// then reads that bundle's Contents/Info.plist for CFBundleIdentifier. exe
+// is typically something like "/Applications/Chromium.app/Contents/MacOS/
+// Chromium Helper (Renderer)", several directories below the bundle root.
+func bundleIdentifier(exe string) (string, bool) {
+	dir := exe
+	for dir != "/" && dir != "." && dir != "" {
+		if strings.HasSuffix(dir, ".app") {
+			break
+		}
+		dir = filepath.Dir(dir)
+	}
+	if !strings.HasSuffix(dir, ".app") {
+		return "", false
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "Contents", "Info.plist"))
+	if err != nil {
+		return "", false
+	}
+	var info struct {
+		CFBundleIdentifier string `plist:"CFBundleIdentifier"`
+	}
+	if _, err := plist.Unmarshal(data, &info); err != nil {
+		return "", false
+	}
+	if info.CFBundleIdentifier == "" {
+		return "", false
+	}
+	return info.CFBundleIdentifier, true
+}
+
+// Set combines zero or more ProcessSelectors with AND semantics: a process/
+// coalition must satisfy every configured matcher. An empty Set matches
+// everything, so filtering is opt-in.
+type Set struct {
+	matchers []ProcessSelector
+
+	// topNCPU, if positive, additionally caps the matched processes to the
+	// N with the highest CPUPercent this sample, applied by
+	// TopNProcessesByCPU after the name/pid matchers above have already
+	// narrowed the set. Unlike the other matchers it can't be expressed as
+	// a per-PID predicate, since ranking requires seeing every candidate
+	// at once.
+	topNCPU int
+}
+
+// New builds a Set from the given matchers.
+func New(matchers ...ProcessSelector) *Set {
+	return &Set{matchers: matchers}
+}
+
+// SetTopNCPU caps MatchName/MatchPID's surviving processes to the n
+// highest by CPUPercent per sample (see TopNProcessesByCPU); n <= 0
+// disables the cap.
+func (s *Set) SetTopNCPU(n int) {
+	s.topNCPU = n
+}
+
+// TopNCPU returns the configured top-N-by-CPU cap, or 0 if unset.
+func (s *Set) TopNCPU() int {
+	return s.topNCPU
+}
+
+func (s *Set) MatchName(name string) bool {
+	for _, m := range s.matchers {
+		if !m.MatchName(name) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Set) MatchPID(pid int) bool {
+	for _, m := range s.matchers {
+		if !m.MatchPID(pid) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Set) MatchCoalition(name string) bool {
+	for _, m := range s.matchers {
+		if !m.MatchCoalition(name) {
+			return false
+		}
+	}
+	return true
+}
+
+// TopNProcessesByCPU returns the n processes in processes with the highest
+// CPUPercent, highest first; n <= 0 or n >= len(processes) returns
+// processes itself, unsorted and uncopied. Otherwise the ranking is done on
+// a copy, so the caller's backing array is never reordered. Ties keep their
+// relative order from processes, since sort.SliceStable is used.
+func TopNProcessesByCPU(processes []models.ProcessInfo, n int) []models.ProcessInfo {
+	if n <= 0 || n >= len(processes) {
+		return processes
+	}
+
+	ranked := append([]models.ProcessInfo(nil), processes...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].CPUPercent > ranked[j].CPUPercent
+	})
+	return ranked[:n]
+}
+
+// FromFlags builds a Set from the --filter-* CLI flag values, skipping any
+// left empty. It returns a nil Set (no filtering at all) if every argument
+// is empty, so callers can skip wiring it into ParserContext entirely.
+// finder backs the user/exe/bundle-id matchers' PID lookups - the same
+// procfind.Finder main.go wires up for dead-PID enrichment, per
+// --pid-finder. topNCPU is passed straight through to SetTopNCPU.
+func FromFlags(finder procfind.Finder, namePattern, pidFile, regex, username, exePattern, bundleID string, topNCPU int) (*Set, error) {
+	var matchers []ProcessSelector
+
+	if namePattern != "" {
+		matchers = append(matchers, NamePattern(namePattern))
+	}
+	if pidFile != "" {
+		m, err := LoadPIDFile(pidFile)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	if regex != "" {
+		m, err := NewNameRegex(regex)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	if username != "" {
+		m, err := NewEffectiveUser(finder, username)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+	if exePattern != "" {
+		matchers = append(matchers, NewExePattern(finder, exePattern))
+	}
+	if bundleID != "" {
+		matchers = append(matchers, NewBundleIDMatch(finder, bundleID))
+	}
+
+	if len(matchers) == 0 && topNCPU <= 0 {
+		return nil, nil
+	}
+	s := New(matchers...)
+	s.SetTopNCPU(topNCPU)
+	return s, nil
+}
@@ -0,0 +1,226 @@
+package selector
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+
+	"powermetrics-tui/internal/models"
+	"powermetrics-tui/internal/procfind"
+)
+
+// stubFinder is a minimal procfind.Finder for matchers that resolve a PID
+// to a uid or executable path without forking anything.
+type stubFinder struct {
+	uid map[int]string
+	exe map[int]string
+}
+
+var _ procfind.Finder = stubFinder{}
+
+func (s stubFinder) Exists(pid int) bool { return true }
+func (s stubFinder) EnrichName(pid int) (string, error) {
+	exe, ok := s.exe[pid]
+	if !ok {
+		return "", fmt.Errorf("pid %d: no exe stubbed", pid)
+	}
+	return exe, nil
+}
+func (s stubFinder) ChildrenOf(pid int) []int { return nil }
+func (s stubFinder) OwnerUID(pid int) (string, error) {
+	uid, ok := s.uid[pid]
+	if !ok {
+		return "", fmt.Errorf("pid %d: no uid stubbed", pid)
+	}
+	return uid, nil
+}
+
+func TestNamePatternGlob(t *testing.T) {
+	p := NamePattern("com.apple.*")
+	if !p.MatchName("com.apple.WindowServer") {
+		t.Error("expected the glob to match")
+	}
+	if p.MatchName("com.example.App") {
+		t.Error("expected the glob not to match a different prefix")
+	}
+}
+
+func TestNameRegexMatchesCoalitionToo(t *testing.T) {
+	re, err := NewNameRegex("^(Chrome|ghostty)$")
+	if err != nil {
+		t.Fatalf("NewNameRegex: %v", err)
+	}
+	if !re.MatchName("Chrome") || !re.MatchCoalition("ghostty") {
+		t.Error("expected the regex to match both names and coalition names")
+	}
+	if re.MatchName("bash") {
+		t.Error("expected bash not to match")
+	}
+}
+
+func TestLoadPIDFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pids.txt")
+	if err := os.WriteFile(path, []byte("100\n200\n\n"), 0o644); err != nil {
+		t.Fatalf("writing pidfile: %v", err)
+	}
+
+	f, err := LoadPIDFile(path)
+	if err != nil {
+		t.Fatalf("LoadPIDFile: %v", err)
+	}
+	if !f.MatchPID(100) || !f.MatchPID(200) || f.MatchPID(300) {
+		t.Error("unexpected pidfile matches")
+	}
+	if !f.MatchName("anything") || !f.MatchCoalition("anything") {
+		t.Error("expected a pidfile matcher not to restrict names/coalitions")
+	}
+}
+
+func TestSetCombinesMatchersWithAnd(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pids.txt")
+	if err := os.WriteFile(path, []byte("100\n"), 0o644); err != nil {
+		t.Fatalf("writing pidfile: %v", err)
+	}
+	f, err := LoadPIDFile(path)
+	if err != nil {
+		t.Fatalf("LoadPIDFile: %v", err)
+	}
+
+	s := New(NamePattern("nginx*"), f)
+	if !s.MatchName("nginx") {
+		t.Error("expected the name to pass through (pidfile never restricts names)")
+	}
+	if !s.MatchPID(100) {
+		t.Error("expected pid 100 to pass through (name matcher never restricts pids)")
+	}
+	if s.MatchPID(999) {
+		t.Error("expected pid 999 to be rejected by the pidfile matcher")
+	}
+}
+
+func TestSetEmptyMatchesEverything(t *testing.T) {
+	s := New()
+	if !s.MatchName("anything") || !s.MatchPID(1) || !s.MatchCoalition("anything") {
+		t.Error("expected an empty Set to match everything")
+	}
+}
+
+func TestFromFlagsNoneConfigured(t *testing.T) {
+	s, err := FromFlags(stubFinder{}, "", "", "", "", "", "", 0)
+	if err != nil {
+		t.Fatalf("FromFlags: %v", err)
+	}
+	if s != nil {
+		t.Error("expected a nil Set when no filter flags are set")
+	}
+}
+
+func TestFromFlagsInvalidRegex(t *testing.T) {
+	if _, err := FromFlags(stubFinder{}, "", "", "(", "", "", "", 0); err == nil {
+		t.Error("expected an error compiling an invalid regex")
+	}
+}
+
+func TestFromFlagsTopNCPUAloneBuildsASet(t *testing.T) {
+	s, err := FromFlags(stubFinder{}, "", "", "", "", "", "", 5)
+	if err != nil {
+		t.Fatalf("FromFlags: %v", err)
+	}
+	if s == nil {
+		t.Fatal("expected a non-nil Set when only --filter-top-n-cpu is set")
+	}
+	if s.TopNCPU() != 5 {
+		t.Errorf("expected TopNCPU() == 5, got %d", s.TopNCPU())
+	}
+}
+
+func TestTopNProcessesByCPU(t *testing.T) {
+	processes := []models.ProcessInfo{
+		{PID: 1, Name: "low", CPUPercent: 5},
+		{PID: 2, Name: "high", CPUPercent: 90},
+		{PID: 3, Name: "mid", CPUPercent: 40},
+	}
+
+	top2 := TopNProcessesByCPU(processes, 2)
+	if len(top2) != 2 || top2[0].PID != 2 || top2[1].PID != 3 {
+		t.Errorf("expected [high, mid] ranked by CPU%%, got %+v", top2)
+	}
+
+	if unchanged := TopNProcessesByCPU(processes, 0); len(unchanged) != len(processes) {
+		t.Errorf("expected n<=0 to return every process unranked, got %d", len(unchanged))
+	}
+}
+
+func TestEffectiveUserMatchesViaFinder(t *testing.T) {
+	me, err := user.Current()
+	if err != nil {
+		t.Skipf("user.Current: %v (no resolvable user in this sandbox)", err)
+	}
+
+	finder := stubFinder{uid: map[int]string{100: me.Uid, 200: "99999"}}
+	e, err := NewEffectiveUser(finder, me.Username)
+	if err != nil {
+		t.Fatalf("NewEffectiveUser: %v", err)
+	}
+	if !e.MatchPID(100) {
+		t.Error("expected pid 100 (running as the current user) to match")
+	}
+	if e.MatchPID(200) {
+		t.Error("expected pid 200 (a different uid) not to match")
+	}
+	if e.MatchPID(999) {
+		t.Error("expected an unresolvable pid not to match")
+	}
+}
+
+func TestExePatternMatchesResolvedExe(t *testing.T) {
+	finder := stubFinder{exe: map[int]string{
+		100: "/Applications/Chromium.app/Contents/MacOS/Chromium Helper",
+		200: "/usr/sbin/sshd",
+	}}
+	m := NewExePattern(finder, "*/Chromium.app/*")
+	if !m.MatchPID(100) {
+		t.Error("expected pid 100's bundle-relative path to match")
+	}
+	if m.MatchPID(200) {
+		t.Error("expected pid 200 (sshd) not to match")
+	}
+	if m.MatchPID(999) {
+		t.Error("expected an unresolvable pid not to match")
+	}
+}
+
+func TestBundleIDMatchReadsInfoPlist(t *testing.T) {
+	dir := t.TempDir()
+	bundle := filepath.Join(dir, "Chromium.app", "Contents")
+	if err := os.MkdirAll(bundle, 0o755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	plistData := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>org.chromium.Chromium</string>
+</dict>
+</plist>`
+	if err := os.WriteFile(filepath.Join(bundle, "Info.plist"), []byte(plistData), 0o644); err != nil {
+		t.Fatalf("writing Info.plist: %v", err)
+	}
+
+	finder := stubFinder{exe: map[int]string{
+		100: filepath.Join(bundle, "MacOS", "Chromium Helper (Renderer)"),
+		200: "/usr/sbin/sshd",
+	}}
+	m := NewBundleIDMatch(finder, "org.chromium.Chromium")
+	if !m.MatchPID(100) {
+		t.Error("expected pid 100 to match via Info.plist's CFBundleIdentifier")
+	}
+	if m.MatchPID(200) {
+		t.Error("expected pid 200 (outside any bundle) not to match")
+	}
+}
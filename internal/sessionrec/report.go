@@ -0,0 +1,220 @@
+package sessionrec
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+)
+
+// WriteHTMLReport reads the .jsonl trace at jsonlPath and writes a
+// self-contained HTML timeline report to htmlPath: stacked SVG tracks for
+// the power rails, average E-core/P-core frequency, and top-process CPU%,
+// sharing a single zoomable/pannable time axis with a hover readout. There
+// is no per-core frequency track and no thermal pressure track; thermal
+// pressure appears only as text in the hover readout. Everything (data, styling, interaction) is inlined so
+// the report is a single file that opens straight in a browser, with no
+// server or network access required.
+func WriteHTMLReport(jsonlPath, htmlPath string) error {
+	snaps, err := ReadSnapshots(jsonlPath)
+	if err != nil {
+		return fmt.Errorf("sessionrec: reading trace: %w", err)
+	}
+
+	data, err := json.Marshal(snaps)
+	if err != nil {
+		return fmt.Errorf("sessionrec: marshal trace for report: %w", err)
+	}
+
+	f, err := os.Create(htmlPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tmpl := template.Must(template.New("report").Parse(reportHTML))
+	return tmpl.Execute(f, struct {
+		Data     template.JS
+		Count    int
+		JSONPath string
+	}{
+		Data:     template.JS(data),
+		Count:    len(snaps),
+		JSONPath: jsonlPath,
+	})
+}
+
+// reportHTML is the full report document. The JS below is intentionally
+// small and dependency-free (no CDN fetch, so the file stays self-
+// contained offline): each track is one <svg> with a <polyline> per
+// series, a shared xScale recomputed on pan/zoom, and a crosshair <line>
+// + <text> driven by mousemove.
+const reportHTML = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>powermetrics-tui session report ({{.Count}} samples)</title>
+<style>
+  body { background: #111; color: #ddd; font-family: -apple-system, sans-serif; margin: 0; padding: 16px; }
+  h1 { font-size: 14px; color: #0af; font-weight: normal; }
+  .track { margin-bottom: 4px; }
+  .track-label { font-size: 11px; color: #888; margin-bottom: 2px; }
+  svg { width: 100%; height: 70px; background: #1a1a1a; display: block; cursor: crosshair; }
+  .line { fill: none; stroke-width: 1.5; }
+  .crosshair { stroke: #555; stroke-width: 1; }
+  #hover { font-size: 11px; color: #0f0; white-space: pre; }
+  #hint { font-size: 10px; color: #666; }
+</style>
+</head>
+<body>
+<h1>powermetrics-tui session report &mdash; {{.Count}} samples</h1>
+<div id="hint">Scroll to zoom, drag to pan, hover for exact values.</div>
+<div id="hover">&nbsp;</div>
+<div id="tracks"></div>
+<script>
+const samples = {{.Data}};
+const colors = {
+  cpu_power_mw: "#f55", gpu_power_mw: "#5af", ane_power_mw: "#fa5",
+  dram_power_mw: "#5f5", system_power_mw: "#ccc"
+};
+let viewStart = 0, viewEnd = samples.length;
+
+// Derive per-sample fields the raw trace doesn't carry directly: average
+// E/P core frequency (for a compact frequency track in place of a full
+// per-core heatmap grid) and each of the top processes' CPU% (0 when that
+// process isn't in that sample's top_processes list).
+function avg(arr) {
+  if (!arr || arr.length === 0) return 0;
+  return arr.reduce((a, b) => a + b, 0) / arr.length;
+}
+const procTotals = {};
+for (const s of samples) {
+  for (const p of (s.top_processes || [])) {
+    procTotals[p.name] = (procTotals[p.name] || 0) + p.cpu_percent;
+  }
+}
+const topProcNames = Object.keys(procTotals).sort((a, b) => procTotals[b] - procTotals[a]).slice(0, 5);
+const procColors = ["#f55", "#5af", "#fa5", "#5f5", "#a5f"];
+for (const s of samples) {
+  s.ecore_avg = avg(s.e_core_freq_mhz);
+  s.pcore_avg = avg(s.p_core_freq_mhz);
+  for (const name of topProcNames) {
+    const hit = (s.top_processes || []).find(p => p.name === name);
+    s["proc_" + name] = hit ? hit.cpu_percent : 0;
+  }
+}
+
+function fmtVal(v) { return (Math.round(v * 10) / 10).toString(); }
+
+function seriesExtent(key) {
+  let max = 0;
+  for (const s of samples) if (s[key] > max) max = s[key];
+  return max || 1;
+}
+
+function makeTrack(label, series) {
+  const container = document.createElement("div");
+  container.className = "track";
+  const labelDiv = document.createElement("div");
+  labelDiv.className = "track-label";
+  labelDiv.textContent = label;
+  container.appendChild(labelDiv);
+
+  const svg = document.createElementNS("http://www.w3.org/2000/svg", "svg");
+  svg.setAttribute("viewBox", "0 0 1000 70");
+  svg.setAttribute("preserveAspectRatio", "none");
+  container.appendChild(svg);
+  document.getElementById("tracks").appendChild(svg);
+
+  svg.render = function() {
+    while (svg.firstChild) svg.removeChild(svg.firstChild);
+    const n = viewEnd - viewStart;
+    if (n <= 1) return;
+    for (const s of series) {
+      const max = seriesExtent(s.key);
+      let pts = "";
+      for (let i = viewStart; i < viewEnd; i++) {
+        const x = ((i - viewStart) / (n - 1)) * 1000;
+        const y = 65 - (samples[i][s.key] / max) * 60;
+        pts += x.toFixed(1) + "," + (isFinite(y) ? y.toFixed(1) : 65) + " ";
+      }
+      const poly = document.createElementNS("http://www.w3.org/2000/svg", "polyline");
+      poly.setAttribute("points", pts);
+      poly.setAttribute("class", "line");
+      poly.setAttribute("stroke", s.color);
+      svg.appendChild(poly);
+    }
+  };
+
+  svg.addEventListener("wheel", (ev) => {
+    ev.preventDefault();
+    const n = viewEnd - viewStart;
+    const factor = ev.deltaY > 0 ? 1.2 : 0.8;
+    let newN = Math.max(4, Math.min(samples.length, Math.round(n * factor)));
+    const mid = (viewStart + viewEnd) / 2;
+    viewStart = Math.max(0, Math.round(mid - newN / 2));
+    viewEnd = Math.min(samples.length, viewStart + newN);
+    renderAll();
+  });
+
+  let dragStartX = null, dragStartView = null;
+  svg.addEventListener("mousedown", (ev) => {
+    dragStartX = ev.clientX;
+    dragStartView = [viewStart, viewEnd];
+  });
+  window.addEventListener("mouseup", () => { dragStartX = null; });
+  svg.addEventListener("mousemove", (ev) => {
+    const rect = svg.getBoundingClientRect();
+    const frac = (ev.clientX - rect.left) / rect.width;
+    const n = viewEnd - viewStart;
+    const idx = Math.min(samples.length - 1, Math.max(0, viewStart + Math.round(frac * (n - 1))));
+    showHover(idx);
+
+    if (dragStartX !== null) {
+      const deltaFrac = (ev.clientX - dragStartX) / rect.width;
+      const shift = Math.round(-deltaFrac * n);
+      let ns = dragStartView[0] + shift, ne = dragStartView[1] + shift;
+      if (ns < 0) { ne -= ns; ns = 0; }
+      if (ne > samples.length) { ns -= (ne - samples.length); ne = samples.length; }
+      viewStart = Math.max(0, ns);
+      viewEnd = Math.min(samples.length, ne);
+      renderAll();
+    }
+  });
+
+  return svg;
+}
+
+function showHover(idx) {
+  const s = samples[idx];
+  if (!s) return;
+  document.getElementById("hover").textContent =
+    s.time + "  cpu=" + fmtVal(s.cpu_power_mw) + "mW gpu=" + fmtVal(s.gpu_power_mw) +
+    "mW ane=" + fmtVal(s.ane_power_mw) + "mW dram=" + fmtVal(s.dram_power_mw) +
+    "mW sys=" + fmtVal(s.system_power_mw) + "mW thermal=" + s.thermal_pressure;
+}
+
+const tracks = [];
+tracks.push(makeTrack("Power rails (CPU/GPU/ANE/DRAM/System)", [
+  {key: "cpu_power_mw", color: colors.cpu_power_mw},
+  {key: "gpu_power_mw", color: colors.gpu_power_mw},
+  {key: "ane_power_mw", color: colors.ane_power_mw},
+  {key: "dram_power_mw", color: colors.dram_power_mw},
+  {key: "system_power_mw", color: colors.system_power_mw},
+]));
+tracks.push(makeTrack("Core frequency, avg MHz (E-cores red, P-cores blue)", [
+  {key: "ecore_avg", color: "#f55"},
+  {key: "pcore_avg", color: "#5af"},
+]));
+tracks.push(makeTrack("Top processes, CPU% (" + topProcNames.join(", ") + ")",
+  topProcNames.map((name, i) => ({key: "proc_" + name, color: procColors[i % procColors.length]}))));
+
+function renderAll() {
+  for (const t of tracks) t.render();
+}
+renderAll();
+if (samples.length > 0) showHover(0);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,148 @@
+// Package sessionrec captures decoded models.MetricsState snapshots to a
+// compact .jsonl trace for post-hoc analysis, and renders a self-contained
+// HTML timeline report from that trace. This is distinct from
+// internal/record, which captures powermetrics' raw text for deterministic
+// --replay: a sessionrec trace already has each sample's regexes/plist
+// fields resolved and is decimated to the top-N processes, so it's meant
+// for graphing a run afterward rather than feeding it back through the
+// parser.
+package sessionrec
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"powermetrics-tui/internal/models"
+)
+
+// ProcessSample is one process' contribution to a Snapshot's top-N list.
+type ProcessSample struct {
+	Name       string  `json:"name"`
+	CPUPercent float64 `json:"cpu_percent"`
+}
+
+// Snapshot is one recorded sample: the fields chunk6-2 asked for a timeline
+// of, pulled off models.MetricsState at capture time.
+type Snapshot struct {
+	Time             time.Time          `json:"time"`
+	CPUPower         float64            `json:"cpu_power_mw"`
+	GPUPower         float64            `json:"gpu_power_mw"`
+	ANEPower         float64            `json:"ane_power_mw"`
+	DRAMPower        float64            `json:"dram_power_mw"`
+	SystemPower      float64            `json:"system_power_mw"`
+	ECoreFreq        []int              `json:"e_core_freq_mhz"`
+	PCoreFreq        []int              `json:"p_core_freq_mhz"`
+	ThermalPressure  string             `json:"thermal_pressure"`
+	PerCPUInterrupts map[string]float64 `json:"per_cpu_interrupts"`
+	TopProcesses     []ProcessSample    `json:"top_processes"`
+}
+
+// SnapshotFrom builds a Snapshot from state's current values, trimming its
+// process list to the topN highest by CPU%. Callers must hold state.Mu for
+// reading (or not care about torn reads, e.g. a fresh *MetricsState built
+// for this purpose alone).
+func SnapshotFrom(state *models.MetricsState, topN int) Snapshot {
+	processes := make([]models.ProcessInfo, len(state.Processes))
+	copy(processes, state.Processes)
+	sort.Slice(processes, func(i, j int) bool {
+		return processes[i].CPUPercent > processes[j].CPUPercent
+	})
+	if len(processes) > topN {
+		processes = processes[:topN]
+	}
+
+	top := make([]ProcessSample, len(processes))
+	for i, p := range processes {
+		top[i] = ProcessSample{Name: p.Name, CPUPercent: p.CPUPercent}
+	}
+
+	interrupts := make(map[string]float64, len(state.PerCPUInterrupts))
+	for cpu, rate := range state.PerCPUInterrupts {
+		interrupts[cpu] = rate
+	}
+
+	return Snapshot{
+		Time:             time.Now(),
+		CPUPower:         state.CPUPower,
+		GPUPower:         state.GPUPower,
+		ANEPower:         state.ANEPower,
+		DRAMPower:        state.DRAMPower,
+		SystemPower:      state.SystemPower,
+		ECoreFreq:        append([]int(nil), state.ECoreFreq...),
+		PCoreFreq:        append([]int(nil), state.PCoreFreq...),
+		ThermalPressure:  state.ThermalPressure,
+		PerCPUInterrupts: interrupts,
+		TopProcesses:     top,
+	}
+}
+
+// Recorder appends one JSON-encoded Snapshot per line to a .jsonl file,
+// mirroring internal/record.Recorder's incremental-write shape so a crash
+// mid-session still leaves every sample captured so far readable.
+type Recorder struct {
+	w *bufio.Writer
+	f *os.File
+}
+
+// NewRecorder creates path (truncating any existing file) and returns a
+// Recorder ready to accept snapshots via Write.
+func NewRecorder(path string) (*Recorder, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{w: bufio.NewWriter(f), f: f}, nil
+}
+
+// Write appends one snapshot as a line of JSON.
+func (r *Recorder) Write(snap Snapshot) error {
+	line, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if _, err := r.w.Write(line); err != nil {
+		return err
+	}
+	if err := r.w.WriteByte('\n'); err != nil {
+		return err
+	}
+	return r.w.Flush()
+}
+
+// Close flushes buffered output and closes the underlying file.
+func (r *Recorder) Close() error {
+	if err := r.w.Flush(); err != nil {
+		r.f.Close()
+		return err
+	}
+	return r.f.Close()
+}
+
+// ReadSnapshots loads every snapshot from a .jsonl trace written by
+// Recorder, e.g. for WriteHTMLReport or any future offline tooling.
+func ReadSnapshots(path string) ([]Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var snaps []Snapshot
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for {
+		var snap Snapshot
+		if err := dec.Decode(&snap); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("sessionrec: decode snapshot: %w", err)
+		}
+		snaps = append(snaps, snap)
+	}
+	return snaps, nil
+}
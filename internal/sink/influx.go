@@ -0,0 +1,76 @@
+package sink
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"powermetrics-tui/internal/models"
+)
+
+// influxSink writes one InfluxDB line-protocol point per metric to the v1
+// HTTP write API, all under a single "powermetrics" measurement with each
+// point's map becoming its tag set.
+type influxSink struct {
+	writeURL string
+	client   *http.Client
+}
+
+// newInfluxSink builds an influxSink from the host:port/db portion of an
+// influx-lp:// target, e.g. "localhost:8086/powermetrics".
+func newInfluxSink(hostPortDB string) (*influxSink, error) {
+	hostPort, db, ok := strings.Cut(hostPortDB, "/")
+	if !ok || hostPort == "" || db == "" {
+		return nil, fmt.Errorf("expected host:port/db, got %q", hostPortDB)
+	}
+	return &influxSink{
+		writeURL: fmt.Sprintf("http://%s/write?db=%s", hostPort, db),
+		client:   &http.Client{},
+	}, nil
+}
+
+func (s *influxSink) Publish(state *models.MetricsState) error {
+	buf := renderLineProtocol(state)
+
+	resp, err := s.client.Post(s.writeURL, "text/plain; charset=utf-8", &buf)
+	if err != nil {
+		return fmt.Errorf("influx-lp: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// renderLineProtocol renders one InfluxDB line-protocol point per metric,
+// all under a single "powermetrics" measurement with each point's map
+// becoming its tag set. Shared by influxSink (HTTP write API) and
+// influxUDPSink (telegraf-style UDP line protocol).
+func renderLineProtocol(state *models.MetricsState) bytes.Buffer {
+	state.Mu.RLock()
+	pts := points(state)
+	ts := state.LastUpdate
+	state.Mu.RUnlock()
+
+	var buf bytes.Buffer
+	for _, p := range pts {
+		buf.WriteString("powermetrics")
+		keys := make([]string, 0, len(p.tags))
+		for k := range p.tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&buf, ",%s=%s", k, escapeTagValue(p.tags[k]))
+		}
+		fmt.Fprintf(&buf, " %s=%f %d\n", p.name, p.value, ts.UnixNano())
+	}
+	return buf
+}
+
+// escapeTagValue escapes the characters InfluxDB line protocol treats as
+// tag-value delimiters.
+func escapeTagValue(v string) string {
+	r := strings.NewReplacer(" ", "\\ ", ",", "\\,", "=", "\\=")
+	return r.Replace(v)
+}
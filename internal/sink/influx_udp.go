@@ -0,0 +1,36 @@
+package sink
+
+import (
+	"net"
+
+	"powermetrics-tui/internal/models"
+)
+
+// influxUDPSink writes the same InfluxDB line-protocol points as
+// influxSink, but over UDP with no write API/db on the wire - the
+// transport telegraf's socket_listener input (and influxd's old UDP
+// listener) expect, for setups that don't want an HTTP round trip per
+// sample.
+type influxUDPSink struct {
+	conn net.Conn
+}
+
+// newInfluxUDPSink dials the host:port portion of an influx-udp:// target.
+// As with newStatsdSink, UDP "dialing" never touches the network, so a
+// listener that isn't up yet is not an error here.
+func newInfluxUDPSink(hostPort string) (*influxUDPSink, error) {
+	conn, err := net.Dial("udp", hostPort)
+	if err != nil {
+		return nil, err
+	}
+	return &influxUDPSink{conn: conn}, nil
+}
+
+func (s *influxUDPSink) Publish(state *models.MetricsState) error {
+	buf := renderLineProtocol(state)
+
+	// Best-effort, like statsdSink: a dropped datagram to a listener that
+	// isn't up shouldn't take the TUI down.
+	_, err := s.conn.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,72 @@
+package sink
+
+import (
+	"fmt"
+	"sort"
+
+	"powermetrics-tui/internal/models"
+)
+
+// point is one (name, tags, value) sample, the common schema both the
+// influx and statsd formatters render from so they never drift apart on
+// which fields are exported.
+type point struct {
+	name  string
+	tags  map[string]string
+	value float64
+}
+
+// points builds the common tag schema - power rails, per-CPU frequency and
+// interrupt rate, memory, thermal pressure, and battery - from state.
+// Callers must hold state.Mu for reading.
+func points(state *models.MetricsState) []point {
+	pts := []point{
+		{name: "cpu_power_mw", value: state.CPUPower},
+		{name: "gpu_power_mw", value: state.GPUPower},
+		{name: "ane_power_mw", value: state.ANEPower},
+		{name: "dram_power_mw", value: state.DRAMPower},
+		{name: "system_power_mw", value: state.SystemPower},
+		{name: "memory_used_mb", value: state.MemoryUsed},
+		{name: "memory_available_mb", value: state.MemoryAvailable},
+		{name: "swap_used_mb", value: state.SwapUsed},
+		{name: "battery_charge_percent", value: state.BatteryCharge},
+	}
+
+	for cpu, freq := range state.AllCpuFreq {
+		pts = append(pts, point{
+			name:  "cpu_frequency_mhz",
+			tags:  map[string]string{"cpu": fmt.Sprintf("CPU%d", cpu)},
+			value: float64(freq),
+		})
+	}
+	for cpu, rate := range state.PerCPUInterrupts {
+		pts = append(pts, point{
+			name:  "per_cpu_interrupts",
+			tags:  map[string]string{"cpu": cpu},
+			value: rate,
+		})
+	}
+	for sensor, temp := range state.Temperature {
+		pts = append(pts, point{
+			name:  "temperature_celsius",
+			tags:  map[string]string{"sensor": sensor},
+			value: temp,
+		})
+	}
+	if state.ThermalPressure != "" {
+		pts = append(pts, point{
+			name:  "thermal_pressure",
+			tags:  map[string]string{"level": state.ThermalPressure},
+			value: 1,
+		})
+	}
+
+	// Stable order so successive samples diff cleanly in a tailing client.
+	sort.Slice(pts, func(i, j int) bool {
+		if pts[i].name != pts[j].name {
+			return pts[i].name < pts[j].name
+		}
+		return pts[i].tags["cpu"]+pts[i].tags["sensor"] < pts[j].tags["cpu"]+pts[j].tags["sensor"]
+	})
+	return pts
+}
@@ -0,0 +1,81 @@
+// Package sink fans each finalized models.MetricsState sample out to one
+// or more structured-output backends - InfluxDB line protocol, statsd, or
+// NDJSON on stdout - behind a single --output target, mirroring how
+// telegraf/cc-metric-collector emit the same collected metrics to
+// multiple backends at once.
+package sink
+
+import (
+	"fmt"
+	"strings"
+
+	"powermetrics-tui/internal/models"
+)
+
+// Sink publishes one finalized sample. Call Publish once per finalized
+// sample, e.g. from StateMachine.FinalizeCurrentState, the same place
+// promexport.Exporter.Publish and jsonexport.Exporter.Publish are called.
+type Sink interface {
+	Publish(state *models.MetricsState) error
+}
+
+// multiSink fans a single Publish out to every configured Sink, returning
+// the first error but still publishing to the rest so one broken backend
+// doesn't silently stop the others.
+type multiSink struct {
+	sinks []Sink
+}
+
+func (m *multiSink) Publish(state *models.MetricsState) error {
+	var firstErr error
+	for _, s := range m.sinks {
+		if err := s.Publish(state); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// NewSinksForTargets parses a comma-separated --output value into a single
+// Sink that fans out to each target. Recognized schemes are
+// "influx-lp://host:port/db", "influx-udp://host:port", "statsd://host:port",
+// and the bare value "stdout-jsonl". An empty targets string returns
+// (nil, nil).
+func NewSinksForTargets(targets string) (Sink, error) {
+	targets = strings.TrimSpace(targets)
+	if targets == "" {
+		return nil, nil
+	}
+
+	var sinks []Sink
+	for _, target := range strings.Split(targets, ",") {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			continue
+		}
+		s, err := newSinkForTarget(target)
+		if err != nil {
+			return nil, fmt.Errorf("sink %q: %w", target, err)
+		}
+		sinks = append(sinks, s)
+	}
+	if len(sinks) == 1 {
+		return sinks[0], nil
+	}
+	return &multiSink{sinks: sinks}, nil
+}
+
+func newSinkForTarget(target string) (Sink, error) {
+	switch {
+	case target == "stdout-jsonl":
+		return newStdoutJSONLSink(), nil
+	case strings.HasPrefix(target, "influx-lp://"):
+		return newInfluxSink(strings.TrimPrefix(target, "influx-lp://"))
+	case strings.HasPrefix(target, "influx-udp://"):
+		return newInfluxUDPSink(strings.TrimPrefix(target, "influx-udp://"))
+	case strings.HasPrefix(target, "statsd://"):
+		return newStatsdSink(strings.TrimPrefix(target, "statsd://"))
+	default:
+		return nil, fmt.Errorf("unrecognized scheme (want stdout-jsonl, influx-lp://, influx-udp://, or statsd://)")
+	}
+}
@@ -0,0 +1,63 @@
+package sink
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"powermetrics-tui/internal/models"
+)
+
+// statsdSink writes each metric as a statsd gauge over UDP. statsd has no
+// native tag support in the plain protocol, so tags are folded into the
+// metric name dot-path instead (e.g. "powermetrics.cpu_frequency_mhz.CPU0").
+type statsdSink struct {
+	conn net.Conn
+}
+
+// newStatsdSink dials the host:port portion of a statsd:// target. UDP
+// "dialing" never touches the network, so a statsd daemon that isn't
+// listening yet is not an error here - see (*statsdSink).Publish.
+func newStatsdSink(hostPort string) (*statsdSink, error) {
+	conn, err := net.Dial("udp", hostPort)
+	if err != nil {
+		return nil, err
+	}
+	return &statsdSink{conn: conn}, nil
+}
+
+func (s *statsdSink) Publish(state *models.MetricsState) error {
+	state.Mu.RLock()
+	pts := points(state)
+	state.Mu.RUnlock()
+
+	var buf strings.Builder
+	for _, p := range pts {
+		fmt.Fprintf(&buf, "powermetrics.%s:%f|g\n", statsdName(p), p.value)
+	}
+
+	// Best-effort like the alerts shell hook: a dropped UDP datagram to a
+	// statsd daemon that isn't listening shouldn't take the TUI down.
+	_, err := s.conn.Write([]byte(buf.String()))
+	return err
+}
+
+// statsdName appends p's tags, sorted by key, to its metric name as
+// dot-path segments.
+func statsdName(p point) string {
+	if len(p.tags) == 0 {
+		return p.name
+	}
+	keys := make([]string, 0, len(p.tags))
+	for k := range p.tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	name := p.name
+	for _, k := range keys {
+		name += "." + p.tags[k]
+	}
+	return name
+}
@@ -0,0 +1,22 @@
+package sink
+
+import (
+	"powermetrics-tui/internal/jsonexport"
+	"powermetrics-tui/internal/models"
+)
+
+// stdoutJSONLSink adapts jsonexport.Exporter so "stdout-jsonl" reuses the
+// same NDJSON rendering --export=- already produces, instead of a second
+// JSON encoder with its own opinions about what a snapshot looks like.
+type stdoutJSONLSink struct {
+	exporter *jsonexport.Exporter
+}
+
+func newStdoutJSONLSink() *stdoutJSONLSink {
+	jsonSink, _ := jsonexport.NewSinkForTarget("-") // "-" never errors
+	return &stdoutJSONLSink{exporter: jsonexport.NewExporter(jsonSink)}
+}
+
+func (s *stdoutJSONLSink) Publish(state *models.MetricsState) error {
+	return s.exporter.Publish(state)
+}
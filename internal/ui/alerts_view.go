@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"powermetrics-tui/internal/alerts"
+)
+
+// DrawAlertsPane renders fired alerts inside a bordered box, most recent
+// first. scroll is how many events to scroll back from the most recent one
+// (0 = bottom/latest); it's clamped internally and the clamped value is
+// returned so the caller can keep its stored scroll position in range.
+// muted reflects alerts.Engine.Muted, shown in the title so a silenced
+// session isn't mistaken for an idle one. It's meant to be composed
+// alongside a view rather than being a ViewType of its own.
+func DrawAlertsPane(screen tcell.Screen, x, y, width, height int, events []alerts.Event, scroll int, muted bool) int {
+	DrawBox(screen, x, y, width, height, tcell.StyleDefault.Foreground(tcell.ColorGray))
+
+	maxRows := height - 2
+	maxScroll := len(events) - maxRows
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if scroll < 0 {
+		scroll = 0
+	}
+	if scroll > maxScroll {
+		scroll = maxScroll
+	}
+
+	title := " Alerts (x: mute) "
+	if muted {
+		title = " Alerts [MUTED] (x: unmute) "
+	}
+	if scroll > 0 {
+		title = fmt.Sprintf(" Alerts (scrolled back %d, PgDn to catch up) ", scroll)
+	}
+	DrawText(screen, x+2, y, title, tcell.StyleDefault.Bold(true).Foreground(tcell.ColorRed))
+
+	end := len(events) - 1 - scroll
+	start := end - maxRows + 1
+	if start < 0 {
+		start = 0
+	}
+
+	row := y + 1
+	for i := end; i >= start; i-- {
+		ev := events[i]
+		color := tcell.ColorYellow
+		switch ev.Severity {
+		case "critical":
+			color = tcell.ColorRed
+		case "info":
+			color = tcell.ColorGray
+		}
+
+		line := fmt.Sprintf("%s %s: %s", ev.Time.Format("15:04:05"), ev.Rule, ev.Message)
+		if len(line) > width-4 {
+			line = line[:width-7] + "..."
+		}
+		DrawText(screen, x+2, row, line, tcell.StyleDefault.Foreground(color))
+		row++
+		if row >= y+height-1 {
+			break
+		}
+	}
+
+	if len(events) == 0 {
+		DrawText(screen, x+2, y+1, "No alerts fired yet.", tcell.StyleDefault.Foreground(tcell.ColorGray).Italic(true))
+	}
+
+	return scroll
+}
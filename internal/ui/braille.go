@@ -0,0 +1,238 @@
+package ui
+
+import (
+	"github.com/gdamore/tcell/v2"
+)
+
+// DownsamplePolicy controls how a long history is reduced to fit a narrow
+// width for DrawBrailleSparkline and DrawStackedBarHistory.
+type DownsamplePolicy int
+
+const (
+	DownsampleAvg DownsamplePolicy = iota
+	DownsampleMin
+	DownsampleMax
+)
+
+// downsample reduces data to exactly buckets values using policy. If data
+// already fits (or is shorter), it's returned unchanged.
+func downsample(data []float64, buckets int, policy DownsamplePolicy) []float64 {
+	if buckets <= 0 || len(data) <= buckets {
+		return data
+	}
+
+	out := make([]float64, buckets)
+	bucketSize := float64(len(data)) / float64(buckets)
+
+	for i := 0; i < buckets; i++ {
+		start := int(float64(i) * bucketSize)
+		end := int(float64(i+1) * bucketSize)
+		if end <= start {
+			end = start + 1
+		}
+		if end > len(data) {
+			end = len(data)
+		}
+
+		slice := data[start:end]
+		switch policy {
+		case DownsampleMin:
+			v := slice[0]
+			for _, s := range slice {
+				if s < v {
+					v = s
+				}
+			}
+			out[i] = v
+		case DownsampleMax:
+			v := slice[0]
+			for _, s := range slice {
+				if s > v {
+					v = s
+				}
+			}
+			out[i] = v
+		default: // DownsampleAvg
+			sum := 0.0
+			for _, s := range slice {
+				sum += s
+			}
+			out[i] = sum / float64(len(slice))
+		}
+	}
+
+	return out
+}
+
+// brailleDotBits gives the bit to set for (column, row) within a single
+// braille cell, column 0..1 (left/right), row 0..3 (top to bottom).
+var brailleDotBits = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40}, // left column, dots 1,2,3,7
+	{0x08, 0x10, 0x20, 0x80}, // right column, dots 4,5,6,8
+}
+
+// brailleChar builds a single braille character whose two sub-columns are
+// filled bottom-up to levels (0..4 dots each), like a miniature bar chart.
+func brailleChar(levels [2]int) rune {
+	var bits rune
+	for col := 0; col < 2; col++ {
+		level := levels[col]
+		if level > 4 {
+			level = 4
+		}
+		for row := 0; row < level; row++ {
+			// Fill from the bottom row (row 3) upward.
+			bits |= brailleDotBits[col][3-row]
+		}
+	}
+	return rune(0x2800) + bits
+}
+
+// DrawBrailleSparkline draws a sparkline using braille dot characters,
+// packing 2 samples horizontally and 4 vertical levels into each terminal
+// cell — 2x the horizontal density of DrawSparkline's block glyphs, at the
+// cost of vertical resolution (4 dot levels per cell against the block
+// glyphs' 8). A long
+// history is reduced to 2*width samples first using policy.
+func DrawBrailleSparkline(screen tcell.Screen, x, y, width int, data []float64, color tcell.Color, policy DownsamplePolicy) {
+	if len(data) == 0 || width <= 0 {
+		return
+	}
+
+	samples := downsample(data, width*2, policy)
+
+	min, max := samples[0], samples[0]
+	for _, v := range samples {
+		if v < min {
+			min = v
+		}
+		if v > max {
+			max = v
+		}
+	}
+	if max == min {
+		max = min + 1
+	}
+
+	level := func(v float64) int {
+		l := int(((v - min) / (max - min)) * 4)
+		if l < 0 {
+			l = 0
+		}
+		if l > 4 {
+			l = 4
+		}
+		return l
+	}
+
+	for col := 0; col < width; col++ {
+		var levels [2]int
+		for sub := 0; sub < 2; sub++ {
+			idx := col*2 + sub
+			if idx < len(samples) {
+				levels[sub] = level(samples[idx])
+			}
+		}
+		screen.SetContent(x+col, y, brailleChar(levels), nil, tcell.StyleDefault.Foreground(color))
+	}
+}
+
+// StackedComponent is one contributor to a DrawStackedBar, e.g. CPU power
+// within total system power.
+type StackedComponent struct {
+	Label   string
+	Value   float64
+	History []float64
+	Color   tcell.Color
+}
+
+// DrawStackedBar renders the current value of each component as a
+// proportional segment of a single horizontal bar, so e.g. CPU/GPU/ANE/DRAM
+// contributions to total power are visible at a glance instead of as
+// separate bars.
+func DrawStackedBar(screen tcell.Screen, x, y, width int, components []StackedComponent) {
+	total := 0.0
+	for _, c := range components {
+		total += c.Value
+	}
+	if total <= 0 {
+		for i := 0; i < width; i++ {
+			screen.SetContent(x+i, y, '░', nil, tcell.StyleDefault.Foreground(tcell.ColorGray))
+		}
+		return
+	}
+
+	col := 0
+	for _, c := range components {
+		segment := int(float64(width) * (c.Value / total))
+		for i := 0; i < segment && col < width; i++ {
+			screen.SetContent(x+col, y, '█', nil, tcell.StyleDefault.Foreground(c.Color))
+			col++
+		}
+	}
+	for ; col < width; col++ {
+		screen.SetContent(x+col, y, '░', nil, tcell.StyleDefault.Foreground(tcell.ColorGray))
+	}
+}
+
+// DrawStackedBarHistory renders a history of stacked contributions across
+// width columns: each column's height reflects the summed magnitude of all
+// components at that point in time, colored by whichever component
+// dominated. Each component's History is independently downsampled to width
+// buckets using policy so a long history renders correctly into a narrow
+// width.
+func DrawStackedBarHistory(screen tcell.Screen, x, y, width, height int, components []StackedComponent, policy DownsamplePolicy) {
+	if width <= 0 || height <= 0 || len(components) == 0 {
+		return
+	}
+
+	downsampled := make([][]float64, len(components))
+	maxTotal := 0.0
+	for i, c := range components {
+		downsampled[i] = downsample(c.History, width, policy)
+	}
+	for col := 0; col < width; col++ {
+		sum := 0.0
+		for _, series := range downsampled {
+			if col < len(series) {
+				sum += series[col]
+			}
+		}
+		if sum > maxTotal {
+			maxTotal = sum
+		}
+	}
+	if maxTotal <= 0 {
+		maxTotal = 1
+	}
+
+	for col := 0; col < width; col++ {
+		sum := 0.0
+		dominant := 0
+		dominantValue := -1.0
+		for i, series := range downsampled {
+			if col >= len(series) {
+				continue
+			}
+			v := series[col]
+			sum += v
+			if v > dominantValue {
+				dominantValue = v
+				dominant = i
+			}
+		}
+
+		barHeight := int((sum / maxTotal) * float64(height))
+		if barHeight > height {
+			barHeight = height
+		}
+		color := tcell.ColorWhite
+		if dominant < len(components) {
+			color = components[dominant].Color
+		}
+
+		for row := 0; row < barHeight; row++ {
+			screen.SetContent(x+col, y+height-1-row, '█', nil, tcell.StyleDefault.Foreground(color))
+		}
+	}
+}
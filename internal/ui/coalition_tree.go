@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"powermetrics-tui/internal/models"
+)
+
+// CoalitionTreeNode is one row of a flattened, depth-first coalition tree,
+// ready for DrawCoalitionTreePane. Callers build this by walking
+// MetricsState.Coalitions via ParentID and consulting RollupCPU/RollupMemory
+// for the subtree totals.
+type CoalitionTreeNode struct {
+	Coalition  models.ProcessCoalition
+	Depth      int
+	HasChildren bool
+	RollupCPU    float64
+	RollupMemory float64
+
+	// Subprocesses is non-nil only when the caller has expanded this
+	// coalition's subprocess list (see main.go's treeExpandedSubprocs),
+	// already sorted and filtered via ProcessCoalition.SubprocessRows.
+	Subprocesses []models.SubprocessRow
+}
+
+// DrawCoalitionTreePane renders a collapsible coalition tree: nodes is the
+// already-collapsed/expanded flattened view the caller computed (a
+// collapsed node's children are simply absent from the slice), cursor is
+// the index of the currently-selected row.
+func DrawCoalitionTreePane(screen tcell.Screen, x, y, width, height int, nodes []CoalitionTreeNode, cursor int, collapsed map[int]bool) {
+	DrawBox(screen, x, y, width, height, tcell.StyleDefault.Foreground(tcell.ColorGray))
+
+	title := " Coalition Tree (Enter: expand/collapse, s: subprocesses, o: sort, z: zero%, p: parent, Esc: close) "
+	DrawText(screen, x+2, y, title, tcell.StyleDefault.Bold(true).Foreground(tcell.ColorTeal))
+
+	header := fmt.Sprintf("%-34s %10s %10s %10s", "Name", "CPU%", "Mem MB", "PSS MB")
+	DrawText(screen, x+2, y+1, header, tcell.StyleDefault.Bold(true))
+
+	if len(nodes) == 0 {
+		DrawText(screen, x+2, y+2, "No coalitions tracked.", tcell.StyleDefault.Foreground(tcell.ColorGray).Italic(true))
+		return
+	}
+
+	row := y + 2
+	for i, n := range nodes {
+		if row >= y+height-1 {
+			break
+		}
+
+		marker := "  "
+		if n.HasChildren {
+			if collapsed[n.Coalition.CoalitionID] {
+				marker = "+ "
+			} else {
+				marker = "- "
+			}
+		}
+
+		name := marker + n.Coalition.Name
+		indent := n.Depth * 2
+		if indent+len(name) > 34 {
+			name = name[:max(34-indent, 0)]
+		}
+		pss := n.Coalition.MemoryPSSMB
+		line := fmt.Sprintf("%*s%-*s %10.2f %10.2f %10.2f", indent, "", 34-indent, name, n.RollupCPU, n.RollupMemory, pss)
+
+		style := tcell.StyleDefault.Foreground(tcell.ColorWhite)
+		if i == cursor {
+			style = style.Reverse(true)
+		}
+		DrawText(screen, x+2, row, line, style)
+		row++
+
+		for _, sub := range n.Subprocesses {
+			if row >= y+height-1 {
+				break
+			}
+			subIndent := (n.Depth + 1) * 2
+			subName := fmt.Sprintf("%*s%s%s", subIndent, "", sub.SubprocessConnector(), sub.Process.Name)
+			if len(subName) > 34 {
+				subName = subName[:34]
+			}
+			subLine := fmt.Sprintf("%-34s %9.1f%% %10.2f", subName, sub.PercentOfParent, sub.Process.MemoryMB)
+			DrawText(screen, x+2, row, subLine, tcell.StyleDefault.Foreground(tcell.ColorGray))
+			row++
+		}
+	}
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"sync"
+
+	"powermetrics-tui/internal/i18n"
+)
+
+// activeCatalog is the i18n.Catalog every draw function consults (via tr)
+// instead of hardcoding English labels, so SetCatalog can change the TUI's
+// language at startup without threading a Catalog argument through every
+// Draw* call. Guarded like activeScheme in scheme.go, for the same reason:
+// main's input-handling goroutine could in principle write it while the
+// render loop reads it each frame.
+var (
+	catalogMu     sync.RWMutex
+	activeCatalog = mustLoadDefaultCatalog()
+)
+
+func mustLoadDefaultCatalog() i18n.Catalog {
+	cat, err := i18n.Load(i18n.DefaultLocale)
+	if err != nil {
+		// The default locale is embedded in the binary; a failure here is a
+		// build-time bug in internal/i18n's translations, not something a
+		// user's environment can trigger.
+		panic(err)
+	}
+	return cat
+}
+
+// SetCatalog makes cat the active translation catalog.
+func SetCatalog(cat i18n.Catalog) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	activeCatalog = cat
+}
+
+// tr looks up key in the active catalog, e.g. tr("ui.system.memory").
+func tr(key string) string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	return activeCatalog.Value(key)
+}
+
+// catalog returns the active catalog itself, for callers that need its
+// number/unit formatting (FormatFloat, FormatRate) alongside tr's labels.
+func catalog() i18n.Catalog {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	return activeCatalog
+}
@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"sync"
+
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -18,19 +20,103 @@ func GetViewInfo() []ViewInfo {
 		{Name: "Interrupts", Shortcut: "1", Icon: "⚡"},
 		{Name: "Power", Shortcut: "2", Icon: "🔋"},
 		{Name: "Frequency", Shortcut: "3", Icon: "📊"},
-		{Name: "Processes", Shortcut: "4", Icon: "📱"},
-		{Name: "Network", Shortcut: "5", Icon: "🌐"},
-		{Name: "Disk", Shortcut: "6", Icon: "💾"},
-		{Name: "Thermal", Shortcut: "7", Icon: "🌡️"},
-		{Name: "Battery", Shortcut: "8", Icon: "🔌"},
-		{Name: "System", Shortcut: "9", Icon: "💻"},
+		{Name: "Idle Residency", Shortcut: "4", Icon: "💤"},
+		{Name: "Processes", Shortcut: "5", Icon: "📱"},
+		{Name: "Network", Shortcut: "6", Icon: "🌐"},
+		{Name: "Disk", Shortcut: "7", Icon: "💾"},
+		{Name: "Thermal", Shortcut: "8", Icon: "🌡️"},
+		{Name: "Battery", Shortcut: "9", Icon: "🔌"},
+		{Name: "System", Shortcut: "", Icon: "💻"},
 		{Name: "Combined", Shortcut: "0", Icon: "📈"},
+		{Name: "Core Map", Shortcut: "", Icon: "🗺️"},
+		{Name: "Layout", Shortcut: "", Icon: "🧩"},
+	}
+}
+
+// MenuItemRect is the column range [X, X+Width) a drawn menu item occupies
+// on row Y. DrawMenuBar/DrawCompactMenuBar record one per item on every
+// draw so HandleMouse can translate a click or hover back to the
+// ViewType it belongs to without re-deriving the same spacing logic.
+type MenuItemRect struct {
+	View  ViewType
+	Y     int
+	X     int
+	Width int
+}
+
+// menuRectsMu guards menuRects/hoveredView, written by the render loop
+// (DrawMenuBar/DrawCompactMenuBar) and read/written by HandleMouse from
+// the input-handling goroutine, mirroring activeScheme's schemeMu split.
+var (
+	menuRectsMu sync.RWMutex
+	menuRects   []MenuItemRect
+	hoveredView = ViewType(-1)
+)
+
+func setMenuRects(rects []MenuItemRect) {
+	menuRectsMu.Lock()
+	defer menuRectsMu.Unlock()
+	menuRects = rects
+}
+
+// hoveredRect reports the ViewType HandleMouse most recently hovered, and
+// whether anything is currently hovered at all, so DrawMenuBar/
+// DrawCompactMenuBar can give that item a distinct style.
+func hoveredRect() (ViewType, bool) {
+	menuRectsMu.RLock()
+	defer menuRectsMu.RUnlock()
+	return hoveredView, hoveredView >= 0
+}
+
+// HandleMouse resolves a tcell.EventMouse against the menu bar's most
+// recently drawn item rects. A mouse-wheel event cycles currentView by
+// one regardless of cursor position; otherwise, motion over an item
+// updates the hover highlight the next DrawMenuBar/DrawCompactMenuBar
+// picks up, and a primary-button click inside an item's rect returns
+// (that item's ViewType, true) to switch to it. Anything else (motion
+// outside every rect, a click that doesn't land in one) clears the hover
+// and returns (currentView, false).
+func HandleMouse(ev *tcell.EventMouse, currentView ViewType) (ViewType, bool) {
+	buttons := ev.Buttons()
+	if buttons&tcell.WheelUp != 0 {
+		return ViewType((int(currentView) - 1 + int(ViewCount)) % int(ViewCount)), true
+	}
+	if buttons&tcell.WheelDown != 0 {
+		return ViewType((int(currentView) + 1) % int(ViewCount)), true
 	}
+
+	x, y := ev.Position()
+	menuRectsMu.RLock()
+	rects := menuRects
+	menuRectsMu.RUnlock()
+
+	var hit *MenuItemRect
+	for i := range rects {
+		if r := rects[i]; y == r.Y && x >= r.X && x < r.X+r.Width {
+			hit = &rects[i]
+			break
+		}
+	}
+
+	menuRectsMu.Lock()
+	if hit != nil {
+		hoveredView = hit.View
+	} else {
+		hoveredView = -1
+	}
+	menuRectsMu.Unlock()
+
+	if hit != nil && buttons&tcell.Button1 != 0 {
+		return hit.View, true
+	}
+	return currentView, false
 }
 
 // DrawMenuBar draws the menu bar at the top of the screen
 func DrawMenuBar(screen tcell.Screen, width int, currentView ViewType) {
 	views := GetViewInfo()
+	hovered, hasHover := hoveredRect()
+	rects := make([]MenuItemRect, 0, len(views))
 
 	// Draw background bar
 	for x := 0; x < width; x++ {
@@ -42,14 +128,19 @@ func DrawMenuBar(screen tcell.Screen, width int, currentView ViewType) {
 	for i, view := range views {
 		// Determine if this is the current view
 		isCurrent := ViewType(i) == currentView
+		isHovered := hasHover && hovered == ViewType(i) && !isCurrent
 
 		// Create the menu item text
 		menuItem := fmt.Sprintf(" %s %s ", view.Shortcut, view.Name)
+		itemStart := x
 
-		// Set style based on whether it's selected
+		// Set style based on whether it's selected or hovered
 		style := tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite)
-		if isCurrent {
+		switch {
+		case isCurrent:
 			style = tcell.StyleDefault.Background(tcell.ColorTeal).Foreground(tcell.ColorBlack).Bold(true)
+		case isHovered:
+			style = tcell.StyleDefault.Background(tcell.ColorSteelBlue).Foreground(tcell.ColorWhite)
 		}
 
 		// Draw the menu item
@@ -57,6 +148,7 @@ func DrawMenuBar(screen tcell.Screen, width int, currentView ViewType) {
 			screen.SetContent(x, 0, ch, nil, style)
 			x++
 		}
+		rects = append(rects, MenuItemRect{View: ViewType(i), Y: 0, X: itemStart, Width: x - itemStart})
 
 		// Add separator if not last item
 		if i < len(views)-1 {
@@ -64,11 +156,15 @@ func DrawMenuBar(screen tcell.Screen, width int, currentView ViewType) {
 			x++
 		}
 	}
+
+	setMenuRects(rects)
 }
 
 // DrawCompactMenuBar draws a more compact menu bar
 func DrawCompactMenuBar(screen tcell.Screen, width int, currentView ViewType) int {
 	views := GetViewInfo()
+	hovered, hasHover := hoveredRect()
+	rects := make([]MenuItemRect, 0, len(views))
 
 	y := 0
 	// Draw title bar
@@ -93,6 +189,7 @@ func DrawCompactMenuBar(screen tcell.Screen, width int, currentView ViewType) in
 	x = 2
 	for i, view := range views {
 		isCurrent := ViewType(i) == currentView
+		isHovered := hasHover && hovered == ViewType(i) && !isCurrent
 
 		// Use brackets for current view
 		var menuItem string
@@ -101,11 +198,15 @@ func DrawCompactMenuBar(screen tcell.Screen, width int, currentView ViewType) in
 		} else {
 			menuItem = fmt.Sprintf(" %s ", view.Name)
 		}
+		itemStart := x
 
 		// Set style
 		style := tcell.StyleDefault.Foreground(tcell.ColorGray)
-		if isCurrent {
+		switch {
+		case isCurrent:
 			style = tcell.StyleDefault.Foreground(tcell.ColorTeal).Bold(true)
+		case isHovered:
+			style = tcell.StyleDefault.Foreground(tcell.ColorSteelBlue).Bold(true)
 		}
 
 		// Draw the menu item
@@ -113,6 +214,7 @@ func DrawCompactMenuBar(screen tcell.Screen, width int, currentView ViewType) in
 			screen.SetContent(x, y, ch, nil, style)
 			x++
 		}
+		rects = append(rects, MenuItemRect{View: ViewType(i), Y: y, X: itemStart, Width: x - itemStart})
 
 		// Add space between items
 		if i < len(views)-1 {
@@ -121,5 +223,7 @@ func DrawCompactMenuBar(screen tcell.Screen, width int, currentView ViewType) in
 		}
 	}
 
+	setMenuRects(rects)
+
 	return y + 1 // Return the next available y position
-}
\ No newline at end of file
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestHandleMouseClickSwitchesView(t *testing.T) {
+	setMenuRects([]MenuItemRect{
+		{View: ViewInterrupts, Y: 0, X: 0, Width: 10},
+		{View: ViewProcesses, Y: 0, X: 10, Width: 10},
+	})
+
+	ev := tcell.NewEventMouse(12, 0, tcell.Button1, tcell.ModNone)
+	view, switched := HandleMouse(ev, ViewInterrupts)
+	if !switched {
+		t.Fatalf("HandleMouse() switched = false, want true for a click inside ViewProcesses' rect")
+	}
+	if view != ViewProcesses {
+		t.Errorf("HandleMouse() view = %v, want %v", view, ViewProcesses)
+	}
+}
+
+func TestHandleMouseClickOutsideRectsDoesNotSwitch(t *testing.T) {
+	setMenuRects([]MenuItemRect{
+		{View: ViewInterrupts, Y: 0, X: 0, Width: 10},
+	})
+
+	ev := tcell.NewEventMouse(50, 5, tcell.Button1, tcell.ModNone)
+	view, switched := HandleMouse(ev, ViewInterrupts)
+	if switched {
+		t.Fatalf("HandleMouse() switched = true, want false for a click outside every rect")
+	}
+	if view != ViewInterrupts {
+		t.Errorf("HandleMouse() view = %v, want unchanged %v", view, ViewInterrupts)
+	}
+}
+
+func TestHandleMouseHoverUpdatesWithoutSwitching(t *testing.T) {
+	setMenuRects([]MenuItemRect{
+		{View: ViewInterrupts, Y: 0, X: 0, Width: 10},
+		{View: ViewProcesses, Y: 0, X: 10, Width: 10},
+	})
+
+	ev := tcell.NewEventMouse(12, 0, tcell.ButtonNone, tcell.ModNone)
+	view, switched := HandleMouse(ev, ViewInterrupts)
+	if switched {
+		t.Fatalf("HandleMouse() switched = true, want false for plain motion with no button held")
+	}
+	if view != ViewInterrupts {
+		t.Errorf("HandleMouse() view = %v, want unchanged %v", view, ViewInterrupts)
+	}
+
+	got, ok := hoveredRect()
+	if !ok || got != ViewProcesses {
+		t.Errorf("hoveredRect() = (%v, %v), want (%v, true)", got, ok, ViewProcesses)
+	}
+}
+
+func TestHandleMouseWheelCyclesView(t *testing.T) {
+	setMenuRects(nil)
+
+	up := tcell.NewEventMouse(0, 0, tcell.WheelUp, tcell.ModNone)
+	if view, switched := HandleMouse(up, ViewPower); !switched || view != ViewInterrupts {
+		t.Errorf("HandleMouse(WheelUp) from ViewPower = (%v, %v), want (%v, true)", view, switched, ViewInterrupts)
+	}
+
+	down := tcell.NewEventMouse(0, 0, tcell.WheelDown, tcell.ModNone)
+	if view, switched := HandleMouse(down, ViewInterrupts); !switched || view != ViewPower {
+		t.Errorf("HandleMouse(WheelDown) from ViewInterrupts = (%v, %v), want (%v, true)", view, switched, ViewPower)
+	}
+}
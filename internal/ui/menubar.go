@@ -0,0 +1,296 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// MenuBarMode selects how MenuBar.Draw lays out the view tabs. ModeAuto
+// measures the tabs against the terminal width and picks the most
+// detailed mode that still fits, falling back to ModeScroll if even
+// ModeIconsOnly would overrun it.
+type MenuBarMode int
+
+const (
+	ModeAuto MenuBarMode = iota
+	ModeFull
+	ModeCompact
+	ModeIconsOnly
+	ModeScroll
+)
+
+// MenuBar holds the menu bar's layout mode and the scroll position
+// ModeScroll (or ModeAuto falling back to it) needs to persist across
+// draws, since a redraw that reset the scroll offset every frame would
+// never let the user actually scroll past the first screenful of tabs.
+type MenuBar struct {
+	Mode MenuBarMode
+
+	scrollOffset int
+}
+
+// NewMenuBar returns a MenuBar in ModeAuto.
+func NewMenuBar() *MenuBar {
+	return &MenuBar{Mode: ModeAuto}
+}
+
+// Draw renders the menu bar in m.Mode (or, for ModeAuto, whichever mode
+// fits width best) and returns the next free Y row, the same contract
+// DrawCompactMenuBar already has. Item rects for HandleMouse are
+// recorded by whichever mode actually draws, same as DrawMenuBar/
+// DrawCompactMenuBar.
+func (m *MenuBar) Draw(screen tcell.Screen, width int, currentView ViewType) int {
+	views := GetViewInfo()
+	mode := m.Mode
+	if mode == ModeAuto {
+		mode = m.resolveAutoMode(views, width)
+	}
+
+	switch mode {
+	case ModeFull:
+		DrawMenuBar(screen, width, currentView)
+		return 1
+	case ModeCompact:
+		return m.drawCompact(screen, width, views, currentView)
+	case ModeIconsOnly:
+		return m.drawIconsOnly(screen, width, views, currentView)
+	default:
+		return m.drawScroll(screen, width, views, currentView)
+	}
+}
+
+// resolveAutoMode picks the most detailed mode whose items fit within
+// width without clipping, falling back to ModeScroll - which always
+// "fits" by construction - if even icons-only overruns it.
+func (m *MenuBar) resolveAutoMode(views []ViewInfo, width int) MenuBarMode {
+	if fullMenuBarWidth(views) <= width {
+		return ModeFull
+	}
+	if compactMenuBarWidth(views) <= width {
+		return ModeCompact
+	}
+	if iconsOnlyMenuBarWidth(views) <= width {
+		return ModeIconsOnly
+	}
+	return ModeScroll
+}
+
+func fullMenuBarWidth(views []ViewInfo) int {
+	w := 2
+	for i, v := range views {
+		w += len(fmt.Sprintf(" %s %s ", v.Shortcut, v.Name))
+		if i < len(views)-1 {
+			w++ // separator
+		}
+	}
+	return w
+}
+
+func compactMenuBarWidth(views []ViewInfo) int {
+	w := 2
+	for i, v := range views {
+		w += len(fmt.Sprintf("[%s]", v.Name)) // brackets are the widest form DrawCompactMenuBar draws
+		if i < len(views)-1 {
+			w++ // space
+		}
+	}
+	return w
+}
+
+func iconsOnlyText(v ViewInfo) string {
+	return fmt.Sprintf(" %s%s ", v.Icon, v.Shortcut)
+}
+
+func iconsOnlyMenuBarWidth(views []ViewInfo) int {
+	w := 2
+	for _, v := range views {
+		w += len([]rune(iconsOnlyText(v)))
+	}
+	return w
+}
+
+// drawCompact is DrawCompactMenuBar's tab row alone, without the title
+// row above it, for when ModeAuto has already decided the full
+// DrawMenuBar-style layout doesn't fit but the compact one does.
+func (m *MenuBar) drawCompact(screen tcell.Screen, width int, views []ViewInfo, currentView ViewType) int {
+	hovered, hasHover := hoveredRect()
+	rects := make([]MenuItemRect, 0, len(views))
+
+	for x := 0; x < width; x++ {
+		screen.SetContent(x, 0, ' ', nil, tcell.StyleDefault.Background(tcell.ColorDarkBlue))
+	}
+
+	x := 2
+	for i, view := range views {
+		isCurrent := ViewType(i) == currentView
+		isHovered := hasHover && hovered == ViewType(i) && !isCurrent
+
+		var menuItem string
+		if isCurrent {
+			menuItem = fmt.Sprintf("[%s]", view.Name)
+		} else {
+			menuItem = fmt.Sprintf(" %s ", view.Name)
+		}
+		itemStart := x
+
+		style := tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorGray)
+		switch {
+		case isCurrent:
+			style = tcell.StyleDefault.Background(tcell.ColorTeal).Foreground(tcell.ColorBlack).Bold(true)
+		case isHovered:
+			style = tcell.StyleDefault.Background(tcell.ColorSteelBlue).Foreground(tcell.ColorWhite)
+		}
+
+		for _, ch := range menuItem {
+			screen.SetContent(x, 0, ch, nil, style)
+			x++
+		}
+		rects = append(rects, MenuItemRect{View: ViewType(i), Y: 0, X: itemStart, Width: x - itemStart})
+
+		if i < len(views)-1 {
+			x++
+		}
+	}
+
+	setMenuRects(rects)
+	return 1
+}
+
+// drawIconsOnly renders each item as its Icon+Shortcut only, for
+// terminals too narrow even for drawCompact's bracketed names.
+func (m *MenuBar) drawIconsOnly(screen tcell.Screen, width int, views []ViewInfo, currentView ViewType) int {
+	hovered, hasHover := hoveredRect()
+	rects := make([]MenuItemRect, 0, len(views))
+
+	for x := 0; x < width; x++ {
+		screen.SetContent(x, 0, ' ', nil, tcell.StyleDefault.Background(tcell.ColorDarkBlue))
+	}
+
+	x := 1
+	for i, view := range views {
+		isCurrent := ViewType(i) == currentView
+		isHovered := hasHover && hovered == ViewType(i) && !isCurrent
+
+		menuItem := iconsOnlyText(view)
+		itemStart := x
+
+		style := tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorWhite)
+		switch {
+		case isCurrent:
+			style = tcell.StyleDefault.Background(tcell.ColorTeal).Foreground(tcell.ColorBlack).Bold(true)
+		case isHovered:
+			style = tcell.StyleDefault.Background(tcell.ColorSteelBlue).Foreground(tcell.ColorWhite)
+		}
+
+		for _, ch := range menuItem {
+			if x >= width {
+				break
+			}
+			screen.SetContent(x, 0, ch, nil, style)
+			x++
+		}
+		rects = append(rects, MenuItemRect{View: ViewType(i), Y: 0, X: itemStart, Width: x - itemStart})
+	}
+
+	setMenuRects(rects)
+	return 1
+}
+
+// drawScroll renders a horizontally scrollable strip of drawCompact-style
+// items, with a "◀"/"▶" affordance on either edge when there's more
+// content in that direction, and adjusts m.scrollOffset so currentView's
+// item is always at least partly visible - the narrowest terminals never
+// lose track of where you are.
+func (m *MenuBar) drawScroll(screen tcell.Screen, width int, views []ViewInfo, currentView ViewType) int {
+	hovered, hasHover := hoveredRect()
+
+	itemWidths := make([]int, len(views))
+	itemStarts := make([]int, len(views))
+	cursor := 0
+	for i, v := range views {
+		var text string
+		if ViewType(i) == currentView {
+			text = fmt.Sprintf("[%s]", v.Name)
+		} else {
+			text = fmt.Sprintf(" %s ", v.Name)
+		}
+		itemStarts[i] = cursor
+		itemWidths[i] = len([]rune(text))
+		cursor += itemWidths[i] + 1 // +1 separator space
+	}
+
+	// Keep currentView in view: if it falls before the window, scroll
+	// left to it; if it (or its right edge) falls past the window,
+	// scroll right just enough to bring it fully on screen.
+	innerWidth := width - 2 // reserve one column on each edge for ◀/▶
+	if innerWidth < 1 {
+		innerWidth = 1
+	}
+	cv := int(currentView)
+	if itemStarts[cv] < m.scrollOffset {
+		m.scrollOffset = itemStarts[cv]
+	}
+	if itemStarts[cv]+itemWidths[cv] > m.scrollOffset+innerWidth {
+		m.scrollOffset = itemStarts[cv] + itemWidths[cv] - innerWidth
+	}
+	if m.scrollOffset < 0 {
+		m.scrollOffset = 0
+	}
+
+	for x := 0; x < width; x++ {
+		screen.SetContent(x, 0, ' ', nil, tcell.StyleDefault.Background(tcell.ColorDarkBlue))
+	}
+
+	arrowStyle := tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorYellow)
+	if m.scrollOffset > 0 {
+		screen.SetContent(0, 0, '◀', nil, arrowStyle)
+	}
+
+	rects := make([]MenuItemRect, 0, len(views))
+	x := 1
+	for i, view := range views {
+		start, w := itemStarts[i], itemWidths[i]
+		if start+w <= m.scrollOffset || start >= m.scrollOffset+innerWidth {
+			continue // entirely scrolled off
+		}
+
+		isCurrent := ViewType(i) == currentView
+		isHovered := hasHover && hovered == ViewType(i) && !isCurrent
+
+		var text string
+		if isCurrent {
+			text = fmt.Sprintf("[%s]", view.Name)
+		} else {
+			text = fmt.Sprintf(" %s ", view.Name)
+		}
+
+		style := tcell.StyleDefault.Background(tcell.ColorDarkBlue).Foreground(tcell.ColorGray)
+		switch {
+		case isCurrent:
+			style = tcell.StyleDefault.Background(tcell.ColorTeal).Foreground(tcell.ColorBlack).Bold(true)
+		case isHovered:
+			style = tcell.StyleDefault.Background(tcell.ColorSteelBlue).Foreground(tcell.ColorWhite)
+		}
+
+		itemScreenStart := x
+		for j, ch := range []rune(text) {
+			col := start + j
+			if col < m.scrollOffset || col >= m.scrollOffset+innerWidth {
+				continue
+			}
+			screen.SetContent(x, 0, ch, nil, style)
+			x++
+		}
+		if x > itemScreenStart {
+			rects = append(rects, MenuItemRect{View: ViewType(i), Y: 0, X: itemScreenStart, Width: x - itemScreenStart})
+		}
+	}
+
+	if m.scrollOffset+innerWidth < cursor-1 {
+		screen.SetContent(width-1, 0, '▶', nil, arrowStyle)
+	}
+
+	setMenuRects(rects)
+	return 1
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// tcellSimScreen returns an initialized SimulationScreen sized to a
+// typical narrow terminal, for tests that need a real tcell.Screen to
+// draw into rather than just exercising HandleMouse/resolveAutoMode's
+// pure logic.
+func tcellSimScreen(t *testing.T) tcell.SimulationScreen {
+	t.Helper()
+	screen := tcell.NewSimulationScreen("")
+	if err := screen.Init(); err != nil {
+		t.Fatalf("tcell.SimulationScreen.Init() failed: %v", err)
+	}
+	screen.SetSize(80, 24)
+	return screen
+}
+
+func TestResolveAutoModeFallsBackAsWidthShrinks(t *testing.T) {
+	m := NewMenuBar()
+	views := GetViewInfo()
+
+	full := fullMenuBarWidth(views)
+	compact := compactMenuBarWidth(views)
+	icons := iconsOnlyMenuBarWidth(views)
+
+	if got := m.resolveAutoMode(views, full+10); got != ModeFull {
+		t.Errorf("resolveAutoMode(width=%d) = %v, want ModeFull", full+10, got)
+	}
+	if got := m.resolveAutoMode(views, compact+1); compact < full && got != ModeCompact {
+		t.Errorf("resolveAutoMode(width=%d) = %v, want ModeCompact", compact+1, got)
+	}
+	if got := m.resolveAutoMode(views, icons+1); icons < compact && got != ModeIconsOnly {
+		t.Errorf("resolveAutoMode(width=%d) = %v, want ModeIconsOnly", icons+1, got)
+	}
+	if got := m.resolveAutoMode(views, 1); got != ModeScroll {
+		t.Errorf("resolveAutoMode(width=1) = %v, want ModeScroll", got)
+	}
+}
+
+func TestDrawScrollKeepsCurrentViewInWindow(t *testing.T) {
+	m := NewMenuBar()
+	screen := tcellSimScreen(t)
+	defer screen.Fini()
+
+	views := GetViewInfo()
+	// A width far too small to show every tab forces the scroll window to
+	// track whichever view is current.
+	m.drawScroll(screen, 20, views, ViewBattery)
+
+	found := false
+	for _, r := range menuRects {
+		if r.View == ViewBattery {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("drawScroll(width=20, currentView=ViewBattery) did not keep ViewBattery's rect on screen; rects=%v", menuRects)
+	}
+}
@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"sync"
+
+	"powermetrics-tui/internal/procfind"
+)
+
+// activeFinder is the procfind.Finder DrawProcessesViewWithStartY consults
+// (via finder) to verify whether a RecentlyExited PID is truly dead,
+// instead of forking `ps -p <pid>` once per displayed row, every redraw.
+// Defaults to procfind.New("")'s gopsutil-backed Finder; guarded like
+// activeScheme/activeCatalog for the same reason (see scheme.go).
+var (
+	finderMu     sync.RWMutex
+	activeFinder = procfind.New("")
+)
+
+// SetFinder makes f the active Finder.
+func SetFinder(f procfind.Finder) {
+	finderMu.Lock()
+	defer finderMu.Unlock()
+	activeFinder = f
+}
+
+func finder() procfind.Finder {
+	finderMu.RLock()
+	defer finderMu.RUnlock()
+	return activeFinder
+}
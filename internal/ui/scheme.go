@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"sync"
+
+	"powermetrics-tui/internal/colorschemes"
+)
+
+// activeScheme is the Colorscheme consulted (via GetColorForValue, or
+// scheme() directly) by the draw code that colors by scheme, so
+// SetScheme/CycleScheme can change that part of the TUI's palette at
+// runtime without threading a Colorscheme argument through every Draw*
+// call. Draw code that names tcell colors directly is not affected by it. Guarded by schemeMu since main's input-handling
+// goroutine writes it while the render loop reads it each frame.
+var (
+	schemeMu     sync.RWMutex
+	activeScheme = colorschemes.Default
+)
+
+// SetScheme makes cs the active Colorscheme.
+func SetScheme(cs colorschemes.Colorscheme) {
+	schemeMu.Lock()
+	defer schemeMu.Unlock()
+	activeScheme = cs
+}
+
+// ActiveSchemeName returns the active Colorscheme's name, for a status
+// indicator.
+func ActiveSchemeName() string {
+	schemeMu.RLock()
+	defer schemeMu.RUnlock()
+	return activeScheme.Name
+}
+
+// CycleScheme advances to the next built-in scheme after the active one (by
+// name, wrapping around), for a runtime keybinding. Schemes loaded via
+// colorschemes.LoadFromFile aren't part of this cycle since they aren't
+// named in colorschemes.Names.
+func CycleScheme() {
+	names := colorschemes.Names()
+	schemeMu.Lock()
+	defer schemeMu.Unlock()
+
+	next := names[0]
+	for i, name := range names {
+		if name == activeScheme.Name {
+			next = names[(i+1)%len(names)]
+			break
+		}
+	}
+	if cs, ok := colorschemes.Get(next); ok {
+		activeScheme = cs
+	}
+}
+
+func scheme() colorschemes.Colorscheme {
+	schemeMu.RLock()
+	defer schemeMu.RUnlock()
+	return activeScheme
+}
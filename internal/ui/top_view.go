@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"powermetrics-tui/internal/proctrack"
+)
+
+// DrawTopPane renders a sortable, filterable rollup of the top pids/coalitions
+// by metric over window, sourced from a proctrack.Tracker. Like
+// DrawAlertsPane, it's a bordered overlay composed alongside a view rather
+// than a ViewType of its own.
+func DrawTopPane(screen tcell.Screen, x, y, width, height int, entries []proctrack.TopEntry, metric proctrack.Metric, window string, filter string) {
+	DrawBox(screen, x, y, width, height, tcell.StyleDefault.Foreground(tcell.ColorGray))
+
+	title := fmt.Sprintf(" Top by %s (%s) ", metric, window)
+	DrawText(screen, x+2, y, title, tcell.StyleDefault.Bold(true).Foreground(tcell.ColorTeal))
+
+	filterLine := fmt.Sprintf("Filter: %s_", filter)
+	DrawText(screen, x+width-len(filterLine)-3, y, filterLine, tcell.StyleDefault.Foreground(tcell.ColorGray))
+
+	header := fmt.Sprintf("%-8s %-28s %10s %10s", "ID", "Name", "Avg", "Max")
+	DrawText(screen, x+2, y+1, header, tcell.StyleDefault.Bold(true))
+
+	row := y + 2
+	shown := 0
+	for _, e := range entries {
+		if filter != "" && !strings.Contains(strings.ToLower(e.Name), strings.ToLower(filter)) {
+			continue
+		}
+		if row >= y+height-1 {
+			break
+		}
+
+		name := e.Name
+		if e.Pinned {
+			name = "* " + name
+		}
+		if len(name) > 28 {
+			name = name[:25] + "..."
+		}
+		kind := "proc"
+		if e.Kind == proctrack.KindCoalition {
+			kind = "coal"
+		}
+		line := fmt.Sprintf("%-8d %-28s %10.2f %10.2f", e.ID, name, e.Avg, e.Max)
+		color := tcell.ColorWhite
+		if kind == "coal" {
+			color = tcell.ColorTeal
+		}
+		if e.Pinned {
+			color = tcell.ColorYellow
+		}
+		DrawText(screen, x+2, row, line, tcell.StyleDefault.Foreground(color))
+		row++
+		shown++
+	}
+
+	if shown == 0 {
+		DrawText(screen, x+2, y+2, "No tracked entries match.", tcell.StyleDefault.Foreground(tcell.ColorGray).Italic(true))
+	}
+}
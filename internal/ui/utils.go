@@ -103,14 +103,16 @@ func FormatSize(bytes float64) string {
 	return "" // Return formatted string
 }
 
-// GetColorForValue returns a color based on value thresholds
+// GetColorForValue returns the active Colorscheme's BarLow/Med/High color
+// for value against the low/high thresholds.
 func GetColorForValue(value, low, high float64) tcell.Color {
+	cs := scheme()
 	if value < low {
-		return tcell.ColorGreen
+		return cs.BarLow
 	} else if value < high {
-		return tcell.ColorYellow
+		return cs.BarMed
 	} else {
-		return tcell.ColorRed
+		return cs.BarHigh
 	}
 }
 
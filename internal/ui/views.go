@@ -1,13 +1,27 @@
+// Package ui draws the TUI directly onto a tcell.Screen: every DrawXXXView
+// function in this package positions runs of styled text by hand rather
+// than composing tview/cview widgets, and MenuBar/HandleMouse in menu.go
+// and menubar.go layer hit-testing and layout on top of that same
+// hand-rolled model.
+//
+// Migrating this package onto tview/cview (and a richer per-widget
+// styleset format on top of it) has been requested and explicitly
+// descoped rather than attempted piecemeal: it would touch every Draw
+// function in the package and add a third-party UI dependency, so it
+// doesn't fit inside a single incremental commit. --styleset (see main.go's loadColorScheme) only
+// aliases the existing internal/colorschemes mechanism and is not that
+// migration - flagging that here, not just in loadColorScheme's doc
+// comment, so it's visible to whoever next touches this package.
 package ui
 
 import (
 	"fmt"
-	"os/exec"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"powermetrics-tui/internal/i18n"
 	"powermetrics-tui/internal/models"
 )
 
@@ -18,6 +32,7 @@ const (
 	ViewInterrupts ViewType = iota
 	ViewPower
 	ViewFrequency
+	ViewIdleResidency
 	ViewProcesses
 	ViewNetwork
 	ViewDisk
@@ -25,6 +40,8 @@ const (
 	ViewBattery
 	ViewSystem
 	ViewCombined
+	ViewCoreMap
+	ViewLayout
 	ViewCount
 )
 
@@ -101,9 +118,12 @@ func DrawInterruptsViewWithHelp(screen tcell.Screen, state *models.MetricsState,
 			timer := state.PerCPUTimers[cpu]
 			total := state.PerCPUInterrupts[cpu]
 
-			// Format: CPU0: Total: 2057/s (IPI: 1197/s, Timer: 713/s)
+			// Format: CPU0: Total: 2057/s  IPI: 1197/s  Timer: 713/s  Busy: 12.3%
 			text := fmt.Sprintf("%-5s Total:%5.0f/s  IPI:%5.0f/s  Timer:%5.0f/s",
 				cpu+":", total, ipi, timer)
+			if util, ok := state.PerCPUUtilization[cpu]; ok {
+				text += fmt.Sprintf("  Busy:%5.1f%%", util)
+			}
 
 			// Color based on activity level
 			color := tcell.ColorWhite
@@ -150,22 +170,43 @@ func DrawInterruptsViewWithHelp(screen tcell.Screen, state *models.MetricsState,
 	}
 }
 
-
 // DrawPowerViewWithHelp draws the power consumption view with optional help and custom start Y
 func DrawPowerViewWithHelp(screen tcell.Screen, state *models.MetricsState, width, height int, showHelp bool, startY int) {
+	DrawPowerViewWithSmoothing(screen, state, width, height, showHelp, startY, false)
+}
+
+// DrawPowerViewWithSmoothing is DrawPowerViewWithHelp with a smoothed
+// toggle: when smoothed is true, the displayed/bar value for each power
+// scalar is its EWMAState.Value instead of the raw instantaneous sample
+// (see models.EWMAState), so a jittery sample-to-sample spike doesn't read
+// as a sustained change.
+func DrawPowerViewWithSmoothing(screen tcell.Screen, state *models.MetricsState, width, height int, showHelp bool, startY int, smoothed bool) {
 	state.Mu.RLock()
 	defer state.Mu.RUnlock()
 
+	cpuPower, gpuPower, anePower, dramPower, systemPower := state.CPUPower, state.GPUPower, state.ANEPower, state.DRAMPower, state.SystemPower
+	if smoothed {
+		cpuPower = state.CPUPowerSmoothed.Value
+		gpuPower = state.GPUPowerSmoothed.Value
+		anePower = state.ANEPowerSmoothed.Value
+		dramPower = state.DRAMPowerSmoothed.Value
+		systemPower = state.SystemPowerSmoothed.Value
+	}
+
 	y := startY
-	DrawText(screen, 2, y, "POWER CONSUMPTION", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorYellow))
+	title := "POWER CONSUMPTION"
+	if smoothed {
+		title = "POWER CONSUMPTION (smoothed)"
+	}
+	DrawText(screen, 2, y, title, tcell.StyleDefault.Bold(true).Foreground(tcell.ColorYellow))
 	if showHelp {
 		DrawText(screen, 20, y, "(Energy usage - affects battery life)", tcell.StyleDefault.Foreground(tcell.ColorGray))
 	}
 	y += 2
 
 	// CPU Power with description
-	DrawText(screen, 2, y, fmt.Sprintf("CPU:    %7.1f mW", state.CPUPower), tcell.StyleDefault)
-	DrawBar(screen, 25, y, width-30, state.CPUPower, 10000, tcell.ColorRed)
+	DrawText(screen, 2, y, fmt.Sprintf("CPU:    %7.1f mW", cpuPower), tcell.StyleDefault)
+	DrawBar(screen, 25, y, width-30, cpuPower, 10000, tcell.ColorRed)
 	y++
 	if showHelp {
 		DrawText(screen, 4, y, "Processor power consumption", tcell.StyleDefault.Foreground(tcell.ColorGray).Italic(true))
@@ -178,8 +219,8 @@ func DrawPowerViewWithHelp(screen tcell.Screen, state *models.MetricsState, widt
 	}
 
 	// GPU Power
-	DrawText(screen, 2, y, fmt.Sprintf("GPU:    %7.1f mW", state.GPUPower), tcell.StyleDefault)
-	DrawBar(screen, 25, y, width-30, state.GPUPower, 10000, tcell.ColorGreen)
+	DrawText(screen, 2, y, fmt.Sprintf("GPU:    %7.1f mW", gpuPower), tcell.StyleDefault)
+	DrawBar(screen, 25, y, width-30, gpuPower, 10000, tcell.ColorGreen)
 	y++
 	if showHelp {
 		DrawText(screen, 4, y, "Graphics processor power", tcell.StyleDefault.Foreground(tcell.ColorGray).Italic(true))
@@ -192,8 +233,8 @@ func DrawPowerViewWithHelp(screen tcell.Screen, state *models.MetricsState, widt
 	}
 
 	// ANE Power
-	DrawText(screen, 2, y, fmt.Sprintf("ANE:    %7.1f mW", state.ANEPower), tcell.StyleDefault)
-	DrawBar(screen, 25, y, width-30, state.ANEPower, 5000, tcell.ColorBlue)
+	DrawText(screen, 2, y, fmt.Sprintf("ANE:    %7.1f mW", anePower), tcell.StyleDefault)
+	DrawBar(screen, 25, y, width-30, anePower, 5000, tcell.ColorBlue)
 	y++
 	if showHelp {
 		DrawText(screen, 4, y, "Apple Neural Engine - AI/ML accelerator", tcell.StyleDefault.Foreground(tcell.ColorGray).Italic(true))
@@ -202,8 +243,8 @@ func DrawPowerViewWithHelp(screen tcell.Screen, state *models.MetricsState, widt
 	y++
 
 	// DRAM Power
-	DrawText(screen, 2, y, fmt.Sprintf("DRAM:   %7.1f mW", state.DRAMPower), tcell.StyleDefault)
-	DrawBar(screen, 25, y, width-30, state.DRAMPower, 5000, tcell.ColorPurple)
+	DrawText(screen, 2, y, fmt.Sprintf("DRAM:   %7.1f mW", dramPower), tcell.StyleDefault)
+	DrawBar(screen, 25, y, width-30, dramPower, 5000, tcell.ColorPurple)
 	y++
 	if showHelp {
 		DrawText(screen, 4, y, "Memory (RAM) power consumption", tcell.StyleDefault.Foreground(tcell.ColorGray).Italic(true))
@@ -212,8 +253,8 @@ func DrawPowerViewWithHelp(screen tcell.Screen, state *models.MetricsState, widt
 	y++
 
 	// System Power
-	DrawText(screen, 2, y, fmt.Sprintf("System: %7.1f mW", state.SystemPower), tcell.StyleDefault)
-	DrawBar(screen, 25, y, width-30, state.SystemPower, 30000, tcell.ColorYellow)
+	DrawText(screen, 2, y, fmt.Sprintf("System: %7.1f mW", systemPower), tcell.StyleDefault)
+	DrawBar(screen, 25, y, width-30, systemPower, 30000, tcell.ColorYellow)
 	y++
 	if showHelp {
 		DrawText(screen, 4, y, "Total system power draw", tcell.StyleDefault.Foreground(tcell.ColorGray).Italic(true))
@@ -222,9 +263,20 @@ func DrawPowerViewWithHelp(screen tcell.Screen, state *models.MetricsState, widt
 
 	if len(state.History.SystemHistory) > 0 {
 		DrawSparkline(screen, 25, y, width-30, state.History.SystemHistory, tcell.ColorYellow)
+		y += 2
 	}
-}
 
+	// Power breakdown: each of CPU/GPU/ANE/DRAM as a share of their combined
+	// power in a single row, so the split is visible at a glance.
+	DrawText(screen, 2, y, "Breakdown:", tcell.StyleDefault.Bold(true))
+	y++
+	DrawStackedBar(screen, 2, y, width-4, []StackedComponent{
+		{Label: "CPU", Value: cpuPower, Color: tcell.ColorRed},
+		{Label: "GPU", Value: gpuPower, Color: tcell.ColorGreen},
+		{Label: "ANE", Value: anePower, Color: tcell.ColorBlue},
+		{Label: "DRAM", Value: dramPower, Color: tcell.ColorPurple},
+	})
+}
 
 // DrawFrequencyViewWithStartY draws the CPU/GPU frequency view with custom start Y
 func DrawFrequencyViewWithStartY(screen tcell.Screen, state *models.MetricsState, width, height int, startY int) {
@@ -345,11 +397,282 @@ func DrawFrequencyViewWithStartY(screen tcell.Screen, state *models.MetricsState
 		DrawBar(screen, 25, y, width-30, float64(state.GPUFreq), 2000, tcell.ColorGreen)
 	}
 
-	// Show a note if no frequency data is available
+	// Show a note if no frequency data is available. On the gopsutil
+	// backend (Linux/Windows) frequencies are never populated, so fall
+	// back to per-CPU busy% - the closest equivalent cross-platform view
+	// of what each core is doing.
 	if len(state.ECoreFreq) == 0 && len(state.PCoreFreq) == 0 && state.GPUFreq == 0 {
-		DrawText(screen, 2, y, "No frequency data available.", tcell.StyleDefault.Foreground(tcell.ColorGray))
+		if len(state.PerCPUUtilization) > 0 {
+			DrawText(screen, 2, y, "No frequency data available; per-CPU busy%:", tcell.StyleDefault.Foreground(tcell.ColorGray))
+			y += 2
+
+			var cpuKeys []string
+			for cpu := range state.PerCPUUtilization {
+				cpuKeys = append(cpuKeys, cpu)
+			}
+			sort.Strings(cpuKeys)
+
+			for _, cpuID := range cpuKeys {
+				if y >= height-2 {
+					break
+				}
+				util := state.PerCPUUtilization[cpuID]
+				DrawText(screen, 2, y, fmt.Sprintf("%-5s %5.1f%%", cpuID+":", util), tcell.StyleDefault)
+				DrawBar(screen, 25, y, width-30, util, 100, tcell.ColorGreen)
+				y++
+			}
+		} else {
+			DrawText(screen, 2, y, "No frequency data available.", tcell.StyleDefault.Foreground(tcell.ColorGray))
+			y += 2
+			DrawText(screen, 2, y, "Try running with --samplers cpu_power", tcell.StyleDefault.Foreground(tcell.ColorGray))
+		}
+	}
+}
+
+// DrawIdleResidencyViewWithStartY draws per-core active residency (the
+// complement of idle/down time) and per-cluster down residency and
+// frequency-distribution histograms, populated by
+// models.MetricsState.PerCoreActiveResidency/ClusterDownResidency/
+// ClusterFreqHistogram.
+func DrawIdleResidencyViewWithStartY(screen tcell.Screen, state *models.MetricsState, width, height int, startY int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	y := startY
+	DrawText(screen, 2, y, "IDLE RESIDENCY", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorPurple))
+	y += 2
+
+	if len(state.PerCoreActiveResidency) == 0 {
+		DrawText(screen, 2, y, "No idle residency data available.", tcell.StyleDefault.Foreground(tcell.ColorGray))
 		y += 2
-		DrawText(screen, 2, y, "Try running with --samplers cpu_power", tcell.StyleDefault.Foreground(tcell.ColorGray))
+		DrawText(screen, 2, y, "Try running with --samplers cpu_power.", tcell.StyleDefault.Foreground(tcell.ColorGray))
+		return
+	}
+
+	var cpus []int
+	for cpu := range state.PerCoreActiveResidency {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+
+	DrawText(screen, 2, y, "Per-Core Active Residency:", tcell.StyleDefault.Bold(true))
+	y++
+	for _, cpu := range cpus {
+		if y >= height-2 {
+			break
+		}
+		active := state.PerCoreActiveResidency[cpu]
+		label := fmt.Sprintf("CPU %-2d %5.1f%%", cpu, active)
+		DrawText(screen, 4, y, label, tcell.StyleDefault)
+		DrawBar(screen, 20, y, width-25, active, 100, GetColorForValue(active, 30, 80))
+		y++
+
+		if history, ok := state.PerCoreResidencyHistory[cpu]; ok && len(history) > 0 && y < height-2 {
+			DrawSparkline(screen, 20, y, width-25, history, tcell.ColorDarkCyan)
+			y++
+		}
+	}
+	y++
+
+	if len(state.ClusterDownResidency) > 0 && y < height-2 {
+		DrawText(screen, 2, y, "Cluster Down Residency:", tcell.StyleDefault.Bold(true))
+		y++
+
+		var clusters []string
+		for cluster := range state.ClusterDownResidency {
+			clusters = append(clusters, cluster)
+		}
+		sort.Strings(clusters)
+
+		for _, cluster := range clusters {
+			if y >= height-2 {
+				break
+			}
+			down := state.ClusterDownResidency[cluster]
+			label := fmt.Sprintf("%-12s %5.1f%%", cluster, down)
+			DrawText(screen, 4, y, label, tcell.StyleDefault)
+			DrawBar(screen, 20, y, width-25, down, 100, tcell.ColorDarkOrange)
+			y++
+		}
+		y++
+	}
+
+	if y < height-2 {
+		var clusters []string
+		for cluster := range state.ClusterFreqHistogram {
+			clusters = append(clusters, cluster)
+		}
+		sort.Strings(clusters)
+
+		for _, cluster := range clusters {
+			if y >= height-2 {
+				break
+			}
+			bins := state.ClusterFreqHistogram[cluster]
+			if len(bins) == 0 {
+				continue
+			}
+
+			DrawText(screen, 2, y, fmt.Sprintf("%s Frequency Distribution:", cluster), tcell.StyleDefault.Bold(true))
+			y++
+
+			maxCount := 1
+			for _, c := range bins {
+				if c > maxCount {
+					maxCount = c
+				}
+			}
+			for i, c := range bins {
+				if y >= height-2 {
+					break
+				}
+				DrawText(screen, 4, y, fmt.Sprintf("bin %d", i), tcell.StyleDefault.Foreground(tcell.ColorGray))
+				DrawBar(screen, 12, y, width-17, float64(c), float64(maxCount), tcell.ColorBlue)
+				y++
+			}
+			y++
+		}
+	}
+}
+
+// coreMapPalette colors the top coalitions in DrawCoreMapViewWithStartY's
+// grid; coalitions beyond len(coreMapPalette) fall into the shared "other"
+// bucket/color rather than cycling, so the legend never runs out of rows
+// to name a swatch.
+var coreMapPalette = []tcell.Color{
+	tcell.ColorGreen, tcell.ColorYellow, tcell.ColorBlue, tcell.ColorPurple,
+	tcell.ColorOrange, tcell.ColorTeal, tcell.ColorRed, tcell.ColorAqua,
+}
+
+// coreMapOtherColor is the swatch for every coalition outside the top 8 by
+// aggregate on-core time, grouped into a single "Other" legend entry.
+const coreMapOtherColor = tcell.ColorGray
+
+// DrawCoreMapViewWithStartY renders models.MetricsState.CoreOccupancyHistory
+// as a grid: one row per core (sorted by CPU index), one column per recent
+// sample interval, each cell colored by whichever coalition the sample's
+// bin-packing heuristic assigned that core to. selectedRow/selectedCol are
+// clamped in place to the drawn grid's bounds and highlighted, so the
+// caller's arrow-key handling can move them without knowing the grid's
+// dimensions up front; the selected cell's exact ms/frequency breakdown is
+// shown below the legend.
+func DrawCoreMapViewWithStartY(screen tcell.Screen, state *models.MetricsState, width, height, startY int, selectedRow, selectedCol *int) {
+	state.Mu.RLock()
+	defer state.Mu.RUnlock()
+
+	y := startY
+	DrawText(screen, 2, y, "CORE MAP", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorPurple))
+	y += 2
+
+	if len(state.CoreOccupancyHistory) == 0 {
+		DrawText(screen, 2, y, "No core occupancy data available.", tcell.StyleDefault.Foreground(tcell.ColorGray))
+		y += 2
+		DrawText(screen, 2, y, "Try running with --samplers tasks,cpu_power.", tcell.StyleDefault.Foreground(tcell.ColorGray))
+		return
+	}
+
+	var cpus []int
+	for cpu := range state.CoreOccupancyHistory {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+
+	// Aggregate each coalition's total ms across every core and interval
+	// currently in history, to pick the top 8 for the legend/palette.
+	totalMs := make(map[int]float64)
+	names := make(map[int]string)
+	maxCols := 0
+	for _, cpu := range cpus {
+		history := state.CoreOccupancyHistory[cpu]
+		if len(history) > maxCols {
+			maxCols = len(history)
+		}
+		for _, sample := range history {
+			totalMs[sample.CoalitionID] += sample.Ms
+			names[sample.CoalitionID] = sample.CoalitionName
+		}
+	}
+	type coalitionTotal struct {
+		id   int
+		name string
+		ms   float64
+	}
+	totals := make([]coalitionTotal, 0, len(totalMs))
+	for id, ms := range totalMs {
+		totals = append(totals, coalitionTotal{id, names[id], ms})
+	}
+	sort.Slice(totals, func(i, j int) bool { return totals[i].ms > totals[j].ms })
+	if len(totals) > len(coreMapPalette) {
+		totals = totals[:len(coreMapPalette)]
+	}
+	colorByCoalition := make(map[int]tcell.Color, len(totals))
+	for i, t := range totals {
+		colorByCoalition[t.id] = coreMapPalette[i]
+	}
+
+	if *selectedRow < 0 {
+		*selectedRow = 0
+	}
+	if *selectedRow > len(cpus)-1 {
+		*selectedRow = len(cpus) - 1
+	}
+	if *selectedCol < 0 {
+		*selectedCol = 0
+	}
+	if maxCols > 0 && *selectedCol > maxCols-1 {
+		*selectedCol = maxCols - 1
+	}
+
+	const gridX = 9
+	for row, cpu := range cpus {
+		if y >= height-12 {
+			break
+		}
+		DrawText(screen, 2, y, fmt.Sprintf("CPU %-2d", cpu), tcell.StyleDefault)
+		history := state.CoreOccupancyHistory[cpu]
+		for col, sample := range history {
+			color := coreMapOtherColor
+			if c, ok := colorByCoalition[sample.CoalitionID]; ok {
+				color = c
+			}
+			style := tcell.StyleDefault.Background(color)
+			if row == *selectedRow && col == *selectedCol {
+				style = style.Reverse(true)
+			}
+			screen.SetContent(gridX+col, y, ' ', nil, style)
+		}
+		y++
+	}
+	y++
+
+	if y < height-6 {
+		DrawText(screen, 2, y, "Legend (top 8 by on-core ms, arrows move selection):", tcell.StyleDefault.Bold(true))
+		y++
+		for _, t := range totals {
+			if y >= height-4 {
+				break
+			}
+			screen.SetContent(2, y, ' ', nil, tcell.StyleDefault.Background(colorByCoalition[t.id]))
+			DrawText(screen, 4, y, fmt.Sprintf("%s (%.0f ms)", t.name, t.ms), tcell.StyleDefault)
+			y++
+		}
+		if y < height-4 {
+			screen.SetContent(2, y, ' ', nil, tcell.StyleDefault.Background(coreMapOtherColor))
+			DrawText(screen, 4, y, "Other", tcell.StyleDefault)
+			y++
+		}
+	}
+	y++
+
+	if *selectedRow >= 0 && *selectedRow < len(cpus) && y < height-2 {
+		cpu := cpus[*selectedRow]
+		history := state.CoreOccupancyHistory[cpu]
+		if *selectedCol >= 0 && *selectedCol < len(history) {
+			sample := history[*selectedCol]
+			detail := fmt.Sprintf("CPU %d, interval -%d: %s %.1fms / %.1fms total @ %dMHz",
+				cpu, len(history)-1-*selectedCol, sample.CoalitionName, sample.Ms, sample.TotalMs, sample.FreqMHz)
+			DrawText(screen, 2, y, detail, tcell.StyleDefault.Foreground(tcell.ColorTeal).Bold(true))
+		}
 	}
 }
 
@@ -361,8 +684,26 @@ func min(a, b int) int {
 	return b
 }
 
+// ProcessSortField selects which column DrawProcessesViewWithStartY sorts the
+// process table by, cycled with 's' and reversed with 'S' in the main event
+// loop - mirrors the role CoalitionSortBy plays for the coalition tree view.
+type ProcessSortField int
+
+const (
+	ProcessSortCPU ProcessSortField = iota
+	ProcessSortMem
+	ProcessSortDisk
+	ProcessSortNet
+	ProcessSortPID
+	ProcessSortName
+	ProcessSortFieldCount
+)
+
+// ProcessSortFieldNames labels each ProcessSortField for the view's title bar.
+var ProcessSortFieldNames = []string{"CPU", "MEM", "DISK", "NET", "PID", "NAME"}
+
 // DrawProcessesViewWithStartY draws the top processes view with custom start Y
-func DrawProcessesViewWithStartY(screen tcell.Screen, state *models.MetricsState, width, height int, startY int, showOnlyCoalitions bool) {
+func DrawProcessesViewWithStartY(screen tcell.Screen, state *models.MetricsState, width, height int, startY int, showOnlyCoalitions bool, sortBy ProcessSortField, sortReverse bool, filter string, scrollOffset, selected *int) {
 	state.Mu.RLock()
 	defer state.Mu.RUnlock()
 
@@ -380,6 +721,21 @@ func DrawProcessesViewWithStartY(screen tcell.Screen, state *models.MetricsState
 	} else {
 		title = fmt.Sprintf("TOP PROCESSES (%d active, %d exited)", len(state.Processes), len(state.RecentlyExited))
 	}
+	title += fmt.Sprintf(" [sort:%s", ProcessSortFieldNames[sortBy])
+	// PID and NAME sort ascending by default, every other field descending.
+	desc := sortBy != ProcessSortPID && sortBy != ProcessSortName
+	if sortReverse {
+		desc = !desc
+	}
+	if desc {
+		title += " desc"
+	} else {
+		title += " asc"
+	}
+	title += "]"
+	if filter != "" {
+		title += fmt.Sprintf(" [filter:%s]", filter)
+	}
 	DrawText(screen, 2, y, title, tcell.StyleDefault.Bold(true).Foreground(tcell.ColorTeal))
 	y += 2
 
@@ -414,20 +770,78 @@ func DrawProcessesViewWithStartY(screen tcell.Screen, state *models.MetricsState
 		copy(processes, state.Processes)
 	}
 
+	if filter != "" {
+		lowerFilter := strings.ToLower(filter)
+		filtered := processes[:0]
+		for _, proc := range processes {
+			if strings.Contains(strings.ToLower(proc.Name), lowerFilter) {
+				filtered = append(filtered, proc)
+			}
+		}
+		processes = filtered
+	}
+
+	less := func(i, j int) bool {
+		switch sortBy {
+		case ProcessSortMem:
+			return processes[i].MemoryMB > processes[j].MemoryMB
+		case ProcessSortDisk:
+			return processes[i].DiskMB > processes[j].DiskMB
+		case ProcessSortNet:
+			return processes[i].NetworkMB > processes[j].NetworkMB
+		case ProcessSortPID:
+			return processes[i].PID < processes[j].PID
+		case ProcessSortName:
+			return processes[i].Name < processes[j].Name
+		default:
+			return processes[i].CPUPercent > processes[j].CPUPercent
+		}
+	}
 	sort.Slice(processes, func(i, j int) bool {
-		return processes[i].CPUPercent > processes[j].CPUPercent
+		if sortReverse {
+			return less(j, i)
+		}
+		return less(i, j)
 	})
 
-	// Display as many processes as can fit on screen
+	// Display as many processes as fit on screen, scrolling through the
+	// rest rather than silently truncating the list (see processScroll and
+	// processSelected in main.go for the keyboard handling that drives
+	// scrollOffset/selected).
 	maxProcesses := height - y - 2 // Leave 2 lines for bottom border
-	if maxProcesses > 30 {
-		maxProcesses = 30 // Cap at 30 to keep it readable
+	if maxProcesses < 0 {
+		maxProcesses = 0
+	}
+
+	if len(processes) == 0 {
+		*selected = 0
+	} else if *selected >= len(processes) {
+		*selected = len(processes) - 1
+	} else if *selected < 0 {
+		*selected = 0
+	}
+	if *selected < *scrollOffset {
+		*scrollOffset = *selected
+	} else if maxProcesses > 0 && *selected >= *scrollOffset+maxProcesses {
+		*scrollOffset = *selected - maxProcesses + 1
+	}
+	maxScroll := len(processes) - maxProcesses
+	if maxScroll < 0 {
+		maxScroll = 0
+	}
+	if *scrollOffset > maxScroll {
+		*scrollOffset = maxScroll
+	}
+	if *scrollOffset < 0 {
+		*scrollOffset = 0
 	}
 
-	for i, proc := range processes {
-		if i >= maxProcesses || y >= height-2 {
+	for row := 0; row < maxProcesses; row++ {
+		i := *scrollOffset + row
+		if i >= len(processes) || y >= height-2 {
 			break
 		}
+		proc := processes[i]
 
 		// Check if this is a coalition parent process (process name matches coalition name)
 		isCoalition := proc.Name == proc.CoalitionName
@@ -472,11 +886,15 @@ func DrawProcessesViewWithStartY(screen tcell.Screen, state *models.MetricsState
 			}
 		}
 
-		DrawText(screen, 2, y, line, tcell.StyleDefault.Foreground(color))
+		style := tcell.StyleDefault.Foreground(color)
+		if i == *selected {
+			style = style.Reverse(true)
+		}
+		DrawText(screen, 2, y, line, style)
 
 		// Draw sparkline for CPU history (starts at column 88)
 		if len(proc.CPUHistory) > 0 {
-			DrawCPUSparkline(screen, 88, y, 10, proc.CPUHistory, sparkColor)
+			DrawSparkline(screen, 88, y, 10, proc.CPUHistory, sparkColor)
 		}
 
 		// Draw sparkline for Memory history (starts at column 99)
@@ -577,8 +995,7 @@ func DrawProcessesViewWithStartY(screen tcell.Screen, state *models.MetricsState
 			verificationColor := tcell.ColorGreen
 
 			for _, pid := range proc.PIDs {
-				psCmd := exec.Command("ps", "-p", fmt.Sprintf("%d", pid), "-o", "pid=")
-				if err := psCmd.Run(); err == nil {
+				if finder().Exists(pid) {
 					// Process is still alive!
 					verificationStatus = "✗" // X for some alive
 					verificationColor = tcell.ColorRed
@@ -635,6 +1052,12 @@ func DrawNetworkViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 	// Total throughput
 	total := state.NetworkIn + state.NetworkOut
 	DrawText(screen, 2, y, fmt.Sprintf("Total: %7.2f MB/s", total), tcell.StyleDefault.Bold(true))
+	y++
+
+	// Cumulative totals since the parser started, alongside this sample's delta
+	DrawText(screen, 2, y, fmt.Sprintf("In total:  %8.1f MB (+%.2f MB/s)", state.NetworkInTotal, state.NetworkInDelta), tcell.StyleDefault.Foreground(tcell.ColorGray))
+	y++
+	DrawText(screen, 2, y, fmt.Sprintf("Out total: %8.1f MB (+%.2f MB/s)", state.NetworkOutTotal, state.NetworkOutDelta), tcell.StyleDefault.Foreground(tcell.ColorGray))
 }
 
 // DrawDiskViewWithStartY draws the disk I/O view with custom start Y
@@ -669,6 +1092,12 @@ func DrawDiskViewWithStartY(screen tcell.Screen, state *models.MetricsState, wid
 	// Total throughput
 	total := state.DiskRead + state.DiskWrite
 	DrawText(screen, 2, y, fmt.Sprintf("Total: %7.2f MB/s", total), tcell.StyleDefault.Bold(true))
+	y++
+
+	// Cumulative totals since the parser started, alongside this sample's delta
+	DrawText(screen, 2, y, fmt.Sprintf("Read total:  %8.1f MB (+%.2f MB/s)", state.DiskReadTotal, state.DiskReadDelta), tcell.StyleDefault.Foreground(tcell.ColorGray))
+	y++
+	DrawText(screen, 2, y, fmt.Sprintf("Write total: %8.1f MB (+%.2f MB/s)", state.DiskWriteTotal, state.DiskWriteDelta), tcell.StyleDefault.Foreground(tcell.ColorGray))
 }
 
 // DrawThermalViewWithStartY draws the thermal monitoring view with custom start Y
@@ -681,11 +1110,12 @@ func DrawThermalViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 	y += 2
 
 	// Thermal pressure
-	pressureColor := tcell.ColorGreen
+	cs := scheme()
+	pressureColor := cs.ThermalNormal
 	if state.ThermalPressure == "Heavy" || state.ThermalPressure == "Critical" {
-		pressureColor = tcell.ColorRed
+		pressureColor = cs.ThermalHot
 	} else if state.ThermalPressure == "Moderate" {
-		pressureColor = tcell.ColorYellow
+		pressureColor = cs.ThermalWarm
 	}
 
 	DrawText(screen, 2, y, fmt.Sprintf("Thermal Pressure: %s", state.ThermalPressure),
@@ -726,7 +1156,7 @@ func DrawThermalViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 		y++
 		DrawText(screen, 2, y, "Average Temperature History:", tcell.StyleDefault.Bold(true))
 		y++
-		DrawSparkline(screen, 4, y, width-10, state.History.TempHistory, tcell.ColorYellow)
+		DrawSparkline(screen, 4, y, width-10, state.History.TempHistory, cs.SparklineColor)
 	}
 }
 
@@ -735,16 +1165,17 @@ func DrawBatteryViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 	state.Mu.RLock()
 	defer state.Mu.RUnlock()
 
+	cs := scheme()
 	y := startY
 	DrawText(screen, 2, y, "BATTERY STATUS", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorGreen))
 	y += 2
 
 	// Battery charge
-	chargeColor := tcell.ColorGreen
+	chargeColor := cs.BarLow
 	if state.BatteryCharge < 20 {
-		chargeColor = tcell.ColorRed
+		chargeColor = cs.BarHigh
 	} else if state.BatteryCharge < 50 {
-		chargeColor = tcell.ColorYellow
+		chargeColor = cs.BarMed
 	}
 
 	DrawText(screen, 2, y, fmt.Sprintf("Charge: %.1f%%", state.BatteryCharge),
@@ -758,9 +1189,9 @@ func DrawBatteryViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 	if state.BatteryState != "" {
 		stateColor := tcell.ColorWhite
 		if state.BatteryState == "charging" {
-			stateColor = tcell.ColorGreen
+			stateColor = cs.BatteryCharging
 		} else if state.BatteryState == "discharging" {
-			stateColor = tcell.ColorYellow
+			stateColor = cs.BatteryDischarging
 		}
 
 		DrawText(screen, 2, y, fmt.Sprintf("State: %s", state.BatteryState),
@@ -768,12 +1199,24 @@ func DrawBatteryViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 		y += 2
 	}
 
-	// Battery history
+	// Battery history, plus a discharge-rate/ETA readout fit from
+	// BatterySampleHistory's richer {timestamp, charge, state, backlight}
+	// entries - History.BatteryHistory only keeps the bare charge values,
+	// not enough to fit a trend line through.
 	if len(state.History.BatteryHistory) > 0 {
 		DrawText(screen, 2, y, "Charge History:", tcell.StyleDefault.Bold(true))
 		y++
 		DrawSparkline(screen, 4, y, width-10, state.History.BatteryHistory, chargeColor)
 		y += 2
+
+		if rate, ok := models.EstimateDischargeRate(state.BatterySampleHistory); ok {
+			readout := fmt.Sprintf("Rate: %+.1f%%/hour", rate)
+			if eta := models.BatteryETA(state.BatteryCharge, rate); eta != "" {
+				readout += "  (" + eta + ")"
+			}
+			DrawText(screen, 2, y, readout, tcell.StyleDefault.Foreground(tcell.ColorGray))
+			y += 2
+		}
 	}
 
 	// System power
@@ -782,7 +1225,7 @@ func DrawBatteryViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 	y++
 
 	if len(state.History.SystemHistory) > 0 {
-		DrawSparkline(screen, 4, y, width-10, state.History.SystemHistory, tcell.ColorYellow)
+		DrawSparkline(screen, 4, y, width-10, state.History.SystemHistory, cs.SparklineColor)
 	}
 }
 
@@ -791,8 +1234,9 @@ func DrawSystemViewWithStartY(screen tcell.Screen, state *models.MetricsState, w
 	state.Mu.RLock()
 	defer state.Mu.RUnlock()
 
+	cat := catalog()
 	y := startY
-	DrawText(screen, 2, y, "SYSTEM OVERVIEW", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorTeal))
+	DrawText(screen, 2, y, tr("ui.system.title"), tcell.StyleDefault.Bold(true).Foreground(scheme().HeaderColor))
 	y += 2
 
 	// Memory usage
@@ -800,8 +1244,8 @@ func DrawSystemViewWithStartY(screen tcell.Screen, state *models.MetricsState, w
 		total := state.MemoryUsed + state.MemoryAvailable
 		usagePercent := (state.MemoryUsed / total) * 100
 
-		DrawText(screen, 2, y, fmt.Sprintf("Memory: %.1f GB / %.1f GB (%.1f%%)",
-			state.MemoryUsed/1024, total/1024, usagePercent), tcell.StyleDefault)
+		DrawText(screen, 2, y, fmt.Sprintf("%s: %s GB / %s GB (%s%%)", tr("ui.system.memory"),
+			cat.FormatFloat(state.MemoryUsed/1024, 1), cat.FormatFloat(total/1024, 1), cat.FormatFloat(usagePercent, 1)), tcell.StyleDefault)
 
 		memColor := GetColorForValue(usagePercent, 60, 80)
 		DrawBar(screen, 4, y+1, width-10, usagePercent, 100, memColor)
@@ -815,21 +1259,21 @@ func DrawSystemViewWithStartY(screen tcell.Screen, state *models.MetricsState, w
 
 	// Swap usage
 	if state.SwapUsed > 0 {
-		DrawText(screen, 2, y, fmt.Sprintf("Swap: %.1f GB", state.SwapUsed/1024), tcell.StyleDefault)
+		DrawText(screen, 2, y, fmt.Sprintf("%s: %s GB", tr("ui.system.swap"), cat.FormatFloat(state.SwapUsed/1024, 1)), tcell.StyleDefault)
 		y += 2
 	}
 
 	// Quick stats
-	DrawText(screen, 2, y, "Quick Stats:", tcell.StyleDefault.Bold(true))
+	DrawText(screen, 2, y, tr("ui.system.quick_stats")+":", tcell.StyleDefault.Bold(true))
 	y++
 
 	stats := []string{
-		fmt.Sprintf("CPU Power:  %.1f W", state.CPUPower/1000),
-		fmt.Sprintf("GPU Power:  %.1f W", state.GPUPower/1000),
-		fmt.Sprintf("Network:    ↓%.1f ↑%.1f MB/s", state.NetworkIn, state.NetworkOut),
-		fmt.Sprintf("Disk:       ↓%.1f ↑%.1f MB/s", state.DiskRead, state.DiskWrite),
-		fmt.Sprintf("Battery:    %.0f%%", state.BatteryCharge),
-		fmt.Sprintf("Thermal:    %s", state.ThermalPressure),
+		fmt.Sprintf("%s:  %s W", tr("ui.system.cpu_power"), cat.FormatFloat(state.CPUPower/1000, 1)),
+		fmt.Sprintf("%s:  %s W", tr("ui.system.gpu_power"), cat.FormatFloat(state.GPUPower/1000, 1)),
+		fmt.Sprintf("%s:    ↓%s ↑%s", tr("ui.system.network"), cat.FormatRate(state.NetworkIn, 1), cat.FormatRate(state.NetworkOut, 1)),
+		fmt.Sprintf("%s:       ↓%s ↑%s", tr("ui.system.disk"), cat.FormatRate(state.DiskRead, 1), cat.FormatRate(state.DiskWrite, 1)),
+		fmt.Sprintf("%s:    %s%%", tr("ui.system.battery"), cat.FormatFloat(state.BatteryCharge, 0)),
+		fmt.Sprintf("%s:    %s", tr("ui.system.thermal"), state.ThermalPressure),
 	}
 
 	for _, stat := range stats {
@@ -842,19 +1286,44 @@ func DrawSystemViewWithStartY(screen tcell.Screen, state *models.MetricsState, w
 
 	// Last update time
 	if !state.LastUpdate.IsZero() {
-		updateText := fmt.Sprintf("Last update: %s", state.LastUpdate.Format(time.Kitchen))
+		updateText := fmt.Sprintf("%s: %s", tr("ui.system.last_update"), state.LastUpdate.Format(time.Kitchen))
 		DrawText(screen, width-len(updateText)-2, height-2, updateText,
 			tcell.StyleDefault.Foreground(tcell.ColorGray))
 	}
 }
 
+// combinedSystemLines builds the Combined view's system section, adding a
+// battery discharge-rate readout (fit from BatterySampleHistory, the same
+// estimator the Battery view itself uses) after the existing power/
+// battery/thermal summary line whenever there's enough history for it.
+// Callers already hold state.Mu (DrawCombinedViewWithStartY takes it for
+// the whole draw), so this reads BatterySampleHistory directly rather
+// than through GetBatteryHistory, which would re-lock it.
+func combinedSystemLines(state *models.MetricsState, cat i18n.Catalog) []string {
+	lines := []string{
+		fmt.Sprintf("Power: %sW  Battery: %s%%  Thermal: %s",
+			cat.FormatFloat(state.SystemPower/1000, 1), cat.FormatFloat(state.BatteryCharge, 0), state.ThermalPressure),
+	}
+
+	if rate, ok := models.EstimateDischargeRate(state.BatterySampleHistory); ok {
+		line := fmt.Sprintf("Battery rate: %+.1f%%/hour", rate)
+		if eta := models.BatteryETA(state.BatteryCharge, rate); eta != "" {
+			line += "  (" + eta + ")"
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
 // DrawCombinedView draws a combined view of all metrics
 func DrawCombinedViewWithStartY(screen tcell.Screen, state *models.MetricsState, width, height int, startY int) {
 	state.Mu.RLock()
 	defer state.Mu.RUnlock()
 
+	cat := catalog()
 	y := startY
-	DrawText(screen, 2, y, "SYSTEM METRICS", tcell.StyleDefault.Bold(true).Foreground(tcell.ColorTeal))
+	DrawText(screen, 2, y, tr("ui.combined.title"), tcell.StyleDefault.Bold(true).Foreground(scheme().HeaderColor))
 	y += 2
 
 	// Compact display of all metrics
@@ -863,42 +1332,39 @@ func DrawCombinedViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 		lines []string
 	}{
 		{
-			"CPU",
+			tr("ui.combined.cpu"),
 			[]string{
 				fmt.Sprintf("IPI: %d  Timer: %d  Total: %d", state.IPICount, state.TimerCount, state.TotalInterrupts),
-				fmt.Sprintf("Power: %.1fW", state.CPUPower/1000),
+				fmt.Sprintf("Power: %sW", cat.FormatFloat(state.CPUPower/1000, 1)),
 			},
 		},
 		{
-			"GPU",
+			tr("ui.combined.gpu"),
 			[]string{
-				fmt.Sprintf("Power: %.1fW  Freq: %dMHz", state.GPUPower/1000, state.GPUFreq),
+				fmt.Sprintf("Power: %sW  Freq: %dMHz", cat.FormatFloat(state.GPUPower/1000, 1), state.GPUFreq),
 			},
 		},
 		{
-			"Memory",
+			tr("ui.combined.memory"),
 			[]string{
-				fmt.Sprintf("Used: %.1fGB  Swap: %.1fGB", state.MemoryUsed/1024, state.SwapUsed/1024),
+				fmt.Sprintf("Used: %sGB  Swap: %sGB", cat.FormatFloat(state.MemoryUsed/1024, 1), cat.FormatFloat(state.SwapUsed/1024, 1)),
 			},
 		},
 		{
-			"Network",
+			tr("ui.combined.network"),
 			[]string{
-				fmt.Sprintf("In: %.1fMB/s  Out: %.1fMB/s", state.NetworkIn, state.NetworkOut),
+				fmt.Sprintf("In: %s  Out: %s", cat.FormatRate(state.NetworkIn, 1), cat.FormatRate(state.NetworkOut, 1)),
 			},
 		},
 		{
-			"Disk",
+			tr("ui.combined.disk"),
 			[]string{
-				fmt.Sprintf("Read: %.1fMB/s  Write: %.1fMB/s", state.DiskRead, state.DiskWrite),
+				fmt.Sprintf("Read: %s  Write: %s", cat.FormatRate(state.DiskRead, 1), cat.FormatRate(state.DiskWrite, 1)),
 			},
 		},
 		{
-			"System",
-			[]string{
-				fmt.Sprintf("Power: %.1fW  Battery: %.0f%%  Thermal: %s",
-					state.SystemPower/1000, state.BatteryCharge, state.ThermalPressure),
-			},
+			tr("ui.combined.system"),
+			combinedSystemLines(state, cat),
 		},
 	}
 
@@ -919,4 +1385,4 @@ func DrawCombinedViewWithStartY(screen tcell.Screen, state *models.MetricsState,
 		}
 		y++
 	}
-}
\ No newline at end of file
+}
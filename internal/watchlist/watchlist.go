@@ -0,0 +1,120 @@
+// Package watchlist lets users pin specific processes so they're always
+// tracked regardless of CPU rank, and get a much longer history buffer for
+// retroactively inspecting memory-leak curves. The idea, and the two
+// matcher kinds, are borrowed from telegraf's procstat input: match by
+// exe-name regex or by a pidfile a service writes its PID to.
+package watchlist
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"powermetrics-tui/internal/models"
+)
+
+// Entry describes one watchlist rule. Exactly one of Pattern or PIDFile is
+// expected to be set; if both are, a process matching either pins it.
+type Entry struct {
+	// Name labels this entry in logs and config errors.
+	Name string `yaml:"name"`
+	// Pattern is a regular expression matched against ProcessInfo.Name.
+	Pattern string `yaml:"pattern"`
+	// PIDFile is a path containing a single PID, e.g. a service's
+	// /var/run/foo.pid, re-read on every Match call so a restarted
+	// service with a new PID is still pinned.
+	PIDFile string `yaml:"pidfile"`
+}
+
+// Config is the top-level shape of the watchlist YAML file.
+type Config struct {
+	Entries []Entry `yaml:"watchlist"`
+}
+
+// LoadConfig reads and parses a YAML watchlist file from disk.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+type compiledEntry struct {
+	name    string
+	re      *regexp.Regexp
+	pidFile string
+}
+
+// Watchlist is a compiled, ready-to-match Config. Construct one with New.
+type Watchlist struct {
+	entries []compiledEntry
+}
+
+// New compiles cfg's entries. It returns an error if a Pattern fails to
+// compile as a regexp; a missing or unreadable PIDFile is not a compile
+// error since the service it names may not be running yet.
+func New(cfg *Config) (*Watchlist, error) {
+	w := &Watchlist{entries: make([]compiledEntry, 0, len(cfg.Entries))}
+	for _, e := range cfg.Entries {
+		ce := compiledEntry{name: e.Name, pidFile: e.PIDFile}
+		if e.Pattern != "" {
+			re, err := regexp.Compile(e.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("watchlist entry %q: %w", e.Name, err)
+			}
+			ce.re = re
+		}
+		w.entries = append(w.entries, ce)
+	}
+	return w, nil
+}
+
+// Match reports whether p is pinned by any watchlist entry, either by its
+// name matching a Pattern or its PID matching the contents of a PIDFile.
+func (w *Watchlist) Match(p models.ProcessInfo) bool {
+	for _, e := range w.entries {
+		if e.re != nil && e.re.MatchString(p.Name) {
+			return true
+		}
+		if e.pidFile != "" && pidFromFile(e.pidFile) == p.PID {
+			return true
+		}
+	}
+	return false
+}
+
+// PinnedPIDs returns the set of PIDs in processes matched by w, ready to
+// assign to models.MetricsState.PinnedPIDs.
+func (w *Watchlist) PinnedPIDs(processes []models.ProcessInfo) map[int]bool {
+	pinned := make(map[int]bool)
+	for _, p := range processes {
+		if w.Match(p) {
+			pinned[p.PID] = true
+		}
+	}
+	return pinned
+}
+
+// pidFromFile reads a single PID out of a pidfile, returning -1 if it can't
+// be read or parsed so it never accidentally matches PID 0.
+func pidFromFile(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return -1
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return -1
+	}
+	return pid
+}
@@ -0,0 +1,64 @@
+package watchlist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"powermetrics-tui/internal/models"
+)
+
+func TestMatchByPattern(t *testing.T) {
+	w, err := New(&Config{Entries: []Entry{{Name: "nginx", Pattern: "^nginx$"}}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if !w.Match(models.ProcessInfo{PID: 1, Name: "nginx"}) {
+		t.Error("expected nginx to match")
+	}
+	if w.Match(models.ProcessInfo{PID: 2, Name: "nginx-helper"}) {
+		t.Error("expected nginx-helper not to match an anchored pattern")
+	}
+}
+
+func TestMatchByPIDFile(t *testing.T) {
+	dir := t.TempDir()
+	pidFile := filepath.Join(dir, "app.pid")
+	if err := os.WriteFile(pidFile, []byte("4242\n"), 0o644); err != nil {
+		t.Fatalf("writing pidfile: %v", err)
+	}
+
+	w, err := New(&Config{Entries: []Entry{{Name: "app", PIDFile: pidFile}}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if !w.Match(models.ProcessInfo{PID: 4242, Name: "app"}) {
+		t.Error("expected pid 4242 to match the pidfile entry")
+	}
+	if w.Match(models.ProcessInfo{PID: 1, Name: "app"}) {
+		t.Error("expected a different pid not to match")
+	}
+}
+
+func TestPinnedPIDs(t *testing.T) {
+	w, err := New(&Config{Entries: []Entry{{Name: "db", Pattern: "postgres"}}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	pinned := w.PinnedPIDs([]models.ProcessInfo{
+		{PID: 10, Name: "postgres"},
+		{PID: 11, Name: "bash"},
+	})
+	if !pinned[10] || pinned[11] {
+		t.Errorf("unexpected pinned set: %+v", pinned)
+	}
+}
+
+func TestNewInvalidPattern(t *testing.T) {
+	if _, err := New(&Config{Entries: []Entry{{Name: "bad", Pattern: "("}}}); err == nil {
+		t.Error("expected an error compiling an invalid regexp")
+	}
+}
@@ -2,36 +2,340 @@ package main
 
 import (
 	"bufio"
+	"crypto/tls"
+	"crypto/x509"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"powermetrics-tui/internal/alerts"
+	"powermetrics-tui/internal/collector"
+	"powermetrics-tui/internal/colorschemes"
+	"powermetrics-tui/internal/config"
+	"powermetrics-tui/internal/i18n"
+	"powermetrics-tui/internal/jsonexport"
+	"powermetrics-tui/internal/layout"
 	"powermetrics-tui/internal/models"
 	"powermetrics-tui/internal/parser"
+	"powermetrics-tui/internal/plugins"
+	"powermetrics-tui/internal/proccache"
+	"powermetrics-tui/internal/procenrich"
+	"powermetrics-tui/internal/procfind"
+	"powermetrics-tui/internal/proctrack"
+	"powermetrics-tui/internal/promexport"
+	"powermetrics-tui/internal/record"
+	"powermetrics-tui/internal/remote"
+	"powermetrics-tui/internal/selector"
+	"powermetrics-tui/internal/sessionrec"
+	"powermetrics-tui/internal/sink"
 	"powermetrics-tui/internal/ui"
+	"powermetrics-tui/internal/watchlist"
 )
 
 var (
-	samplers     = flag.String("samplers", "default", "Comma-separated list of samplers (interrupts,cpu_power,gpu_power,thermal,battery,tasks,all,default)")
-	interval     = flag.Int("interval", 1000, "Sampling interval in milliseconds")
-	combined     = flag.Bool("combined", false, "Show all metrics in combined view")
-	debug        = flag.Bool("debug", false, "Enable debug output")
-	currentView  ui.ViewType
-	metricsState *models.MetricsState
-	showHelp     bool = true // Show descriptions by default for casual users
+	samplers            = flag.String("samplers", "default", "Comma-separated list of samplers (interrupts,cpu_power,gpu_power,thermal,battery,tasks,all,default)")
+	interval            = flag.Int("interval", 1000, "Sampling interval in milliseconds")
+	combined            = flag.Bool("combined", false, "Show all metrics in combined view")
+	debug               = flag.Bool("debug", false, "Enable debug output")
+	backend             = flag.String("backend", "", "Metrics backend to use (powermetrics,gopsutil); defaults based on GOOS")
+	alertsConfig        = flag.String("alerts-config", "", "Path to a YAML alert rules file; enables the Alerts pane")
+	recordPath          = flag.String("record", "", "Tee raw powermetrics output to this file for later replay")
+	replayPath          = flag.String("replay", "", "Replay a recording made with --record instead of running powermetrics")
+	replaySpeed         = flag.Float64("replay-speed", 1, "Replay speed multiplier, e.g. 4 for --replay-speed=4x")
+	metricsListen       = flag.String("metrics-listen", "", "Address to serve a Prometheus /metrics endpoint on, e.g. :9101")
+	exporterAddr        = flag.String("exporter", "", "Alias for --metrics-listen, for parity with cc-metric-collector/telegraf/node-exporter flag naming")
+	exportInterval      = flag.Duration("export-interval", 0, "Minimum time between Prometheus /metrics refreshes, e.g. 5s; 0 refreshes on every --interval sample")
+	exportTarget        = flag.String("export", "", "Stream NDJSON snapshots to a file path, \"-\" for stdout, or an http(s):// collector URL")
+	noTUI               = flag.Bool("no-tui", false, "Run headless (no screen), e.g. alongside --export/--metrics-listen")
+	watchlistPath       = flag.String("watchlist-config", "", "Path to a YAML watchlist file; pins matched processes to the Top pane with a longer history buffer")
+	filterName          = flag.String("filter-name", "", "Only track processes/coalitions whose name matches this glob, e.g. \"com.apple.*\"")
+	filterPIDFile       = flag.String("filter-pid-file", "", "Only track processes whose PID is listed in this file, one per line")
+	filterRegex         = flag.String("filter-regex", "", "Only track processes/coalitions whose name matches this regular expression")
+	filterUser          = flag.String("filter-user", "", "Only track processes running as this effective user")
+	filterExe           = flag.String("filter-exe", "", "Only track processes whose resolved executable path matches this glob, e.g. \"*/Chromium.app/*\"")
+	filterBundleID      = flag.String("filter-bundle-id", "", "Only track processes launched from a macOS .app bundle with this CFBundleIdentifier, e.g. com.google.Chrome")
+	filterTopNCPU       = flag.Int("filter-top-n-cpu", 0, "Only track the N processes with the highest CPU% this sample (0 = no cap); combines with the other --filter-* flags")
+	exportTopN          = flag.Int("export-top-n", 0, "With --export, only include the N processes/coalitions with the highest energy impact (0 = all)")
+	metricsCardinality  = flag.Int("metrics-cardinality-cap", 0, "With --metrics-listen/--exporter, only give the N highest-CPU% processes/coalitions their own label series; fold the rest into an \"other\" series (0 = no cap)")
+	textFormat          = flag.Bool("powermetrics-text-format", false, "Use powermetrics' legacy text output instead of its plist output (debugging fallback)")
+	configPath          = flag.String("config", "", "Path to a YAML/JSON config file to disable metric subsystems, resize history, or filter CPU core types")
+	outputTargets       = flag.String("output", "", "Comma-separated structured-output sinks: stdout-jsonl, influx-lp://host:port/db, influx-udp://host:port, statsd://host:port")
+	serveAddr           = flag.String("serve", "", "Address to serve a remote-monitoring stream on, e.g. :7777; any number of --connect clients may subscribe")
+	connectAddr         = flag.String("connect", "", "host:port of a --serve'd instance to mirror instead of running powermetrics locally")
+	tlsCertPath         = flag.String("tls-cert", "", "With --serve, this host's TLS certificate (PEM) to terminate TLS; with --connect, a CA/server certificate (PEM) to trust")
+	tlsKeyPath          = flag.String("tls-key", "", "TLS private key (PEM), paired with --tls-cert when using --serve")
+	authToken           = flag.String("auth-token", "", "Shared secret required of --connect clients by --serve, and sent by --connect to the server")
+	layoutPath          = flag.String("layout", "", "Path to a layout DSL spec file composing a custom dashboard (see internal/layout); defaults to $XDG_CONFIG_HOME/powermetrics-tui/layout if present")
+	colorScheme         = flag.String("color", "default", "Colorscheme to draw with: default, monokai, nord, solarized, or a name from $XDG_CONFIG_HOME/powermetrics-tui/colorschemes/NAME.json")
+	colorSchemeC        = flag.String("c", "", "Alias for --color")
+	styleset            = flag.String("styleset", "", "Alias for --color (aerc-style name for the same colorscheme mechanism, not a tview/cview styleset); takes precedence over both if set")
+	menuBarMode         = flag.String("menu-bar-mode", "auto", "Menu bar layout: auto, full, compact, icons-only, or scroll; auto picks the most detailed one that fits the terminal width")
+	pluginDir           = flag.String("plugin-dir", "", "Directory of Go plugin .so panels to load; defaults to $XDG_CONFIG_HOME/powermetrics-tui/plugins if present")
+	langCode            = flag.String("lang", "", "Locale for TUI labels, e.g. de_DE; defaults to LC_ALL/LANG, falling back to en_US")
+	pidFinderFlag       = flag.String("pid-finder", "", "Backend for PID liveness/name lookups: gopsutil (default, no forking) or ps (pgrep/ps fallback)")
+	procCachePath       = flag.String("proc-cache", "", "Path to a persisted process-metadata cache used to resolve ghost PIDs across --replay restarts; defaults to ~/.cache/powermetrics-tui/procs.json")
+	followChildren      = flag.Bool("follow-children", false, "With --replay, roll each coalition's un-reported descendant processes' CPU/memory into its totals")
+	includeDeadChildren = flag.Bool("include-dead-children", false, "With --replay, add each coalition leader's reaped-child CPU delta (proc_pid_rusage) to its CPU history")
+	noEnrich            = flag.Bool("no-enrich", false, "Disable the process enrichment pass (RSS/VMS/threads/FDs/cmdline/user/start time); show the raw powermetrics-only fields instead")
+	currentView         ui.ViewType
+	menuBar             = ui.NewMenuBar()
+	metricsState        *models.MetricsState
+	metrics             collector.MetricsCollector
+	alertEngine         *alerts.Engine
+	exporter            *promexport.Exporter
+	jsonExporter        *jsonexport.Exporter
+	outputSink          sink.Sink
+	remoteServer        *remote.Server
+	remoteClient        *collector.RemoteCollector // set when --connect is used, for the header status indicator
+	tracker             *proctrack.Tracker
+	procCache           *proccache.Cache
+	pidFinder           procfind.Finder // set by loadPIDFinder; also handed to selector.FromFlags for --filter-user/-exe/-bundle-id
+	watchlistObj        *watchlist.Watchlist
+	metricFilter        parser.MetricFlag                  // compiled --config exclude_metrics, applied to --replay's StateMachine
+	replayControl       = &record.PlaybackControl{}        // toggled with Space, seeked with ←/→, while --replay is active
+	replayDuration      time.Duration                      // total length of a --replay'd recording, 0 if unknown (e.g. raw-text replays)
+	showHelp            bool                        = true // Show descriptions by default for casual users
+	showSmoothed        bool                               // Toggle instant vs EWMA-smoothed values on the power view ('m')
+	showAlerts          bool
+	alertScroll         int
+	showTop             bool
+	topMetricIdx        int
+	topWindowIdx        int
+	topFilter           string
+	showTree            bool
+	treeCollapsed       = make(map[int]bool)
+	treeCursor          int
+	lastTreeNodes       []ui.CoalitionTreeNode
+
+	// treeExpandedSubprocs tracks which coalitions currently show their
+	// subprocess list beneath them in the tree view (toggled with 's').
+	treeExpandedSubprocs = make(map[int]bool)
+	treeSortBy           = models.SortByCPU
+	treeShowZeroCPU      bool
+
+	// Processes view scroll/sort/filter state (persists across view
+	// switches and coalition toggling, like treeSortBy above).
+	processScroll      int
+	processSelected    int
+	processSortBy      ui.ProcessSortField
+	processSortReverse bool
+	processFilterMode  bool // true while '/' is capturing filter text
+	processFilter      string
+
+	// Core Map view's selected cell, moved with arrow keys while that view
+	// is focused (see ui.DrawCoreMapViewWithStartY); clamped to the drawn
+	// grid's bounds by the draw call itself.
+	coreMapSelectedRow int
+	coreMapSelectedCol int
+
+	// sessionRecorder is non-nil while 'R' is actively capturing decoded
+	// MetricsState snapshots to sessionRecordPath for the sessionrec HTML
+	// timeline report; both are cleared once the recording is stopped and
+	// its report has been written.
+	sessionRecorder   *sessionrec.Recorder
+	sessionRecordPath string
+
+	// layoutRows is the parsed --layout spec, nil if none was given/found;
+	// ui.ViewLayout falls back to a "no layout configured" message when nil.
+	layoutRows     []layout.Row
+	layoutRenderer = layout.NewRenderer()
+
+	// loadedPanels are the plugins.Panel values loaded from --plugin-dir (or
+	// its default XDG path), registered into layoutRenderer under their own
+	// Name() so a layout spec can reference them like any built-in widget.
+	// Updated once per finalized sample in the main loop, mirroring how
+	// built-in widgets read metricsState directly in their DrawFunc.
+	loadedPanels []plugins.Panel
 )
 
 func main() {
 	flag.Parse()
+	if *replayPath == "" && *connectAddr == "" {
+		checkPrivileges()
+	}
 
 	// Initialize state
 	metricsState = models.NewMetricsState()
 
+	if *configPath != "" {
+		cfg, err := config.LoadConfig(*configPath)
+		if err != nil {
+			log.Fatalf("Error loading config: %v", err)
+		}
+		for _, name := range cfg.Disable {
+			metricsState.DisabledSubsystems[name] = true
+		}
+		if cfg.HistoryLen > 0 {
+			metricsState.History.MaxHistory = cfg.HistoryLen
+			metricsState.PerCPUHistoryLen = cfg.HistoryLen
+		}
+		if cfg.BatteryHistoryLen > 0 {
+			metricsState.MaxBatterySampleHistory = cfg.BatteryHistoryLen
+		}
+		metricsState.CPUFilterECores = cfg.CPUFilter.ECores
+		metricsState.CPUFilterPCores = cfg.CPUFilter.PCores
+		for _, name := range cfg.ExcludeMetrics {
+			metricsState.ExcludedMetrics[name] = true
+		}
+		metricFilter = parser.CompileMetricFilter(cfg.ExcludeMetrics)
+	}
+
+	loadLayoutSpec()
+	loadColorScheme()
+	loadMenuBarMode()
+	loadPlugins()
+	loadLocale()
+	loadPIDFinder()
+	loadProcCache()
+	loadEnricher()
+
+	// Determine which samplers to use
+	samplerList := determineSamplers()
+
+	tracker = proctrack.NewTracker()
+
+	if *replayPath != "" {
+		sm := parser.NewStateMachine(metricsState)
+		sm.SetTracker(tracker)
+		sm.SetMetricFilter(metricFilter)
+		sm.SetProcCache(procCache)
+		sm.SetFollowChildren(*followChildren)
+		sm.SetIncludeDeadChildren(*includeDeadChildren)
+		sel, err := selector.FromFlags(pidFinder, *filterName, *filterPIDFile, *filterRegex, *filterUser, *filterExe, *filterBundleID, *filterTopNCPU)
+		if err != nil {
+			log.Fatalf("Error building process filter: %v", err)
+		}
+		sm.SetSelector(sel)
+		// A --record'd file parses as a JSON-framed recording; anything
+		// else (e.g. a raw `sudo powermetrics | tee capture.txt`) is
+		// replayed as plain powermetrics text instead. Only the JSON-framed
+		// format supports seeking, since replayDuration/seekTo need its
+		// per-sample offsets.
+		replay := record.Replay
+		if _, err := record.Open(*replayPath); err != nil {
+			replay = record.ReplayRawText
+		} else if d, err := record.Duration(*replayPath); err == nil {
+			replayDuration = d
+		}
+		go func() {
+			if err := replay(*replayPath, sm, *replaySpeed, replayControl); err != nil {
+				log.Printf("Replay stopped: %v", err)
+			}
+		}()
+	} else if *connectAddr != "" {
+		clientTLSConfig, err := clientTLSConfigFromFlags()
+		if err != nil {
+			log.Fatalf("Error configuring TLS for --connect: %v", err)
+		}
+		remoteClient = collector.NewRemoteCollector(*connectAddr, *authToken, clientTLSConfig)
+		metrics = remoteClient
+		if err := metrics.Start(metricsState, *interval); err != nil {
+			log.Fatalf("Error starting %s collector: %v", metrics.Name(), err)
+		}
+		defer metrics.Stop()
+	} else {
+		// Start metrics collection via the pluggable backend
+		metrics = collector.New(*backend)
+		if pmc, ok := metrics.(*collector.PowermetricsCollector); ok {
+			pmc.Samplers = samplerList
+			pmc.Debug = *debug
+			if *textFormat {
+				pmc.Format = collector.FormatText
+			}
+			if *recordPath != "" {
+				rec, err := record.NewRecorder(*recordPath, record.Header{
+					Host:        hostname(),
+					SamplerArgs: samplerList,
+					StartTime:   time.Now(),
+				})
+				if err != nil {
+					log.Fatalf("Error creating recording at %s: %v", *recordPath, err)
+				}
+				pmc.Recorder = rec
+				defer rec.Close()
+			}
+		}
+		if err := metrics.Start(metricsState, *interval); err != nil {
+			log.Fatalf("Error starting %s collector: %v", metrics.Name(), err)
+		}
+		defer metrics.Stop()
+	}
+
+	listenAddr := *metricsListen
+	if listenAddr == "" {
+		listenAddr = *exporterAddr
+	}
+	if listenAddr != "" {
+		exporter = promexport.NewExporter(listenAddr, *exportInterval)
+		exporter.CardinalityCap = *metricsCardinality
+		if err := exporter.Start(); err != nil {
+			log.Fatalf("Error starting metrics exporter on %s: %v", listenAddr, err)
+		}
+	}
+
+	if *serveAddr != "" {
+		serverTLSConfig, err := serverTLSConfigFromFlags()
+		if err != nil {
+			log.Fatalf("Error configuring TLS for --serve: %v", err)
+		}
+		remoteServer = remote.NewServer(*serveAddr, *authToken, serverTLSConfig, samplerList)
+		if err := remoteServer.Start(); err != nil {
+			log.Fatalf("Error starting --serve listener on %s: %v", *serveAddr, err)
+		}
+		defer remoteServer.Close()
+	}
+
+	if *alertsConfig != "" {
+		cfg, err := alerts.LoadConfig(*alertsConfig)
+		if err != nil {
+			log.Fatalf("Error loading alerts config: %v", err)
+		}
+		alertEngine = alerts.NewEngine(cfg)
+		showAlerts = true
+	}
+
+	if *watchlistPath != "" {
+		cfg, err := watchlist.LoadConfig(*watchlistPath)
+		if err != nil {
+			log.Fatalf("Error loading watchlist config: %v", err)
+		}
+		watchlistObj, err = watchlist.New(cfg)
+		if err != nil {
+			log.Fatalf("Error compiling watchlist config: %v", err)
+		}
+	}
+
+	if *exportTarget != "" {
+		exportSink, err := jsonexport.NewSinkForTarget(*exportTarget)
+		if err != nil {
+			log.Fatalf("Error creating export sink for %s: %v", *exportTarget, err)
+		}
+		jsonExporter = jsonexport.NewExporter(exportSink)
+		jsonExporter.TopN = *exportTopN
+	}
+
+	if *outputTargets != "" {
+		var sinkErr error
+		outputSink, sinkErr = sink.NewSinksForTargets(*outputTargets)
+		if sinkErr != nil {
+			log.Fatalf("Error creating output sink(s) for %s: %v", *outputTargets, sinkErr)
+		}
+	}
+
+	if *noTUI {
+		runHeadless()
+		return
+	}
+
 	// Initialize tcell screen
 	screen, err := tcell.NewScreen()
 	if err != nil {
@@ -45,12 +349,6 @@ func main() {
 	screen.EnableMouse()
 	screen.Clear()
 
-	// Determine which samplers to use
-	samplerList := determineSamplers()
-
-	// Start powermetrics monitoring
-	go runPowerMetrics(samplerList)
-
 	// Main event loop
 	eventChan := make(chan tcell.Event)
 	go func() {
@@ -61,24 +359,207 @@ func main() {
 
 	ticker := time.NewTicker(100 * time.Millisecond)
 	defer ticker.Stop()
+	var lastPublish time.Time
 
 	for {
 		select {
 		case ev := <-eventChan:
 			switch ev := ev.(type) {
 			case *tcell.EventKey:
+				if ev.Key() == tcell.KeyCtrlC {
+					return
+				}
+				// While the Top pane is focused it captures the keyboard:
+				// typing edits the name filter, so view-switch shortcuts
+				// and quit-on-'q' are suspended until Escape closes it.
+				if showTop {
+					switch {
+					case ev.Key() == tcell.KeyEscape:
+						showTop = false
+						topFilter = ""
+					case ev.Key() == tcell.KeyUp:
+						topMetricIdx = (topMetricIdx - 1 + len(proctrack.Metrics)) % len(proctrack.Metrics)
+					case ev.Key() == tcell.KeyDown:
+						topMetricIdx = (topMetricIdx + 1) % len(proctrack.Metrics)
+					case ev.Key() == tcell.KeyLeft:
+						topWindowIdx = (topWindowIdx - 1 + len(proctrack.Windows)) % len(proctrack.Windows)
+					case ev.Key() == tcell.KeyRight:
+						topWindowIdx = (topWindowIdx + 1) % len(proctrack.Windows)
+					case ev.Key() == tcell.KeyBackspace || ev.Key() == tcell.KeyBackspace2:
+						if len(topFilter) > 0 {
+							topFilter = topFilter[:len(topFilter)-1]
+						}
+					case ev.Rune() != 0:
+						topFilter += string(ev.Rune())
+					}
+					break
+				}
+
+				// While typing a process-name filter, '/' has already put us
+				// in capture mode: typing edits processFilter and Escape or
+				// Enter leaves capture mode (clearing the filter on Escape),
+				// the same capture-then-release shape showTop uses above.
+				if processFilterMode {
+					switch {
+					case ev.Key() == tcell.KeyEscape:
+						processFilterMode = false
+						processFilter = ""
+					case ev.Key() == tcell.KeyEnter:
+						processFilterMode = false
+					case ev.Key() == tcell.KeyBackspace || ev.Key() == tcell.KeyBackspace2:
+						if len(processFilter) > 0 {
+							processFilter = processFilter[:len(processFilter)-1]
+						}
+					case ev.Rune() != 0:
+						processFilter += string(ev.Rune())
+					}
+					break
+				}
+
+				// The coalition tree pane also captures the keyboard while
+				// focused, so Up/Down move the cursor and Enter expands or
+				// collapses the selected coalition's children instead of
+				// those keys doing nothing or leaking to view switching.
+				if showTree {
+					switch {
+					case ev.Key() == tcell.KeyEscape:
+						showTree = false
+					case ev.Key() == tcell.KeyUp:
+						if treeCursor > 0 {
+							treeCursor--
+						}
+					case ev.Key() == tcell.KeyDown:
+						if treeCursor < len(lastTreeNodes)-1 {
+							treeCursor++
+						}
+					case ev.Key() == tcell.KeyEnter:
+						if treeCursor >= 0 && treeCursor < len(lastTreeNodes) {
+							id := lastTreeNodes[treeCursor].Coalition.CoalitionID
+							treeCollapsed[id] = !treeCollapsed[id]
+						}
+					case ev.Rune() == 's' || ev.Rune() == 'S':
+						if treeCursor >= 0 && treeCursor < len(lastTreeNodes) {
+							id := lastTreeNodes[treeCursor].Coalition.CoalitionID
+							treeExpandedSubprocs[id] = !treeExpandedSubprocs[id]
+						}
+					case ev.Rune() == 'z' || ev.Rune() == 'Z':
+						treeShowZeroCPU = !treeShowZeroCPU
+					case ev.Rune() == 'o' || ev.Rune() == 'O':
+						treeSortBy = (treeSortBy + 1) % 3
+					case ev.Rune() == 'p' || ev.Rune() == 'P':
+						if treeCursor >= 0 && treeCursor < len(lastTreeNodes) {
+							parentID := lastTreeNodes[treeCursor].Coalition.ParentID
+							for i, n := range lastTreeNodes {
+								if n.Coalition.CoalitionID == parentID {
+									treeCursor = i
+									break
+								}
+							}
+						}
+					}
+					break
+				}
+
 				if ev.Key() == tcell.KeyEscape || ev.Rune() == 'q' || ev.Rune() == 'Q' {
 					return
 				}
 				if ev.Key() == tcell.KeyTab {
 					currentView = (currentView + 1) % ui.ViewCount
 				}
-				if ev.Key() == tcell.KeyCtrlC {
-					return
-				}
 				if ev.Rune() == 'h' || ev.Rune() == 'H' || ev.Rune() == '?' {
 					showHelp = !showHelp // Toggle help descriptions
 				}
+				if (ev.Rune() == 'a' || ev.Rune() == 'A') && alertEngine != nil {
+					showAlerts = !showAlerts
+				}
+				if showAlerts && ev.Key() == tcell.KeyPgUp {
+					alertScroll++
+				}
+				if showAlerts && ev.Key() == tcell.KeyPgDn {
+					alertScroll--
+				}
+				if showAlerts && (ev.Rune() == 'x' || ev.Rune() == 'X') && alertEngine != nil {
+					alertEngine.ToggleMute()
+				}
+				if ev.Rune() == 't' || ev.Rune() == 'T' {
+					showTop = !showTop
+				}
+				// Scroll/sort/filter keys only apply while the processes
+				// view itself is focused, so they don't steal Up/Down/s from
+				// other views. Sort and filter persist across view switches
+				// since they live in these package vars rather than being
+				// reset here.
+				if currentView == ui.ViewProcesses {
+					_, screenHeight := screen.Size()
+					page := screenHeight / 2
+					if page < 1 {
+						page = 1
+					}
+					switch {
+					case ev.Key() == tcell.KeyUp:
+						processSelected--
+					case ev.Key() == tcell.KeyDown:
+						processSelected++
+					case ev.Key() == tcell.KeyPgUp:
+						processSelected -= page
+					case ev.Key() == tcell.KeyPgDn:
+						processSelected += page
+					case ev.Key() == tcell.KeyHome:
+						processSelected = 0
+						processScroll = 0
+					case ev.Key() == tcell.KeyEnd:
+						processSelected = 1 << 30 // clamped to the last row when drawn
+					case ev.Rune() == '/':
+						processFilterMode = true
+					case ev.Rune() == 's':
+						processSortBy = (processSortBy + 1) % ui.ProcessSortFieldCount
+					case ev.Rune() == 'S':
+						processSortReverse = !processSortReverse
+					}
+					if processSelected < 0 {
+						processSelected = 0
+					}
+				}
+				// Arrow keys move the Core Map's selected cell while that
+				// view is focused, the same gating ViewProcesses uses above.
+				if currentView == ui.ViewCoreMap {
+					switch ev.Key() {
+					case tcell.KeyUp:
+						coreMapSelectedRow--
+					case tcell.KeyDown:
+						coreMapSelectedRow++
+					case tcell.KeyLeft:
+						coreMapSelectedCol--
+					case tcell.KeyRight:
+						coreMapSelectedCol++
+					}
+				}
+				if ev.Rune() == 'c' || ev.Rune() == 'C' {
+					showTree = !showTree
+				}
+				if ev.Rune() == 'r' || ev.Rune() == 'R' {
+					toggleSessionRecording()
+				}
+				if ev.Rune() == 'm' || ev.Rune() == 'M' {
+					showSmoothed = !showSmoothed
+				}
+				if ev.Rune() == 'k' || ev.Rune() == 'K' {
+					ui.CycleScheme()
+				}
+				if ev.Rune() == ' ' && *replayPath != "" {
+					replayControl.SetPaused(!replayControl.Paused())
+				}
+				// Seek ±10s through a --replay'd recording, except while the
+				// Processes or Core Map view is focused, since both take the
+				// arrow keys in their own handling above.
+				if *replayPath != "" && currentView != ui.ViewProcesses && currentView != ui.ViewCoreMap {
+					switch ev.Key() {
+					case tcell.KeyLeft:
+						replayControl.RequestSeek(-10 * time.Second)
+					case tcell.KeyRight:
+						replayControl.RequestSeek(10 * time.Second)
+					}
+				}
 				// Number key shortcuts for quick view switching
 				if ev.Rune() >= '1' && ev.Rune() <= '9' {
 					currentView = ui.ViewType(ev.Rune() - '1')
@@ -86,16 +567,170 @@ func main() {
 				if ev.Rune() == '0' {
 					currentView = ui.ViewCombined
 				}
+			case *tcell.EventMouse:
+				if view, switched := ui.HandleMouse(ev, currentView); switched {
+					currentView = view
+				}
 			case *tcell.EventResize:
 				screen.Clear()
 			}
 
 		case <-ticker.C:
+			metricsState.Mu.RLock()
+			updated := metricsState.LastUpdate.After(lastPublish)
+			metricsState.Mu.RUnlock()
+			if updated {
+				updateWatchlistPins(metricsState)
+				metricsState.Mu.RLock()
+				tracker.Observe(metricsState.Processes, metricsState.Coalitions, time.Now())
+				metricsState.Mu.RUnlock()
+				if alertEngine != nil {
+					alertEngine.Evaluate(metricsState)
+				}
+				for _, p := range loadedPanels {
+					p.Update(metricsState)
+				}
+				if exporter != nil {
+					exporter.Publish(metricsState)
+				}
+				if jsonExporter != nil {
+					if err := jsonExporter.Publish(metricsState); err != nil {
+						log.Printf("Error publishing JSON export: %v", err)
+					}
+				}
+				if outputSink != nil {
+					if err := outputSink.Publish(metricsState); err != nil {
+						log.Printf("Error publishing to --output sink(s): %v", err)
+					}
+				}
+				if remoteServer != nil {
+					if err := remoteServer.Publish(metricsState); err != nil {
+						log.Printf("Error publishing to --serve subscribers: %v", err)
+					}
+				}
+				if sessionRecorder != nil {
+					metricsState.Mu.RLock()
+					snap := sessionrec.SnapshotFrom(metricsState, 10)
+					metricsState.Mu.RUnlock()
+					if err := sessionRecorder.Write(snap); err != nil {
+						log.Printf("Error writing session recording: %v", err)
+					}
+				}
+				lastPublish = time.Now()
+			}
 			drawUI(screen)
 		}
 	}
 }
 
+// runHeadless drives the same sample/alert/export loop as main's event loop
+// but without a tcell screen, for running as a pure Prometheus/JSON
+// exporter (--no-tui). It blocks until interrupted.
+func runHeadless() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	var lastPublish time.Time
+
+	for {
+		select {
+		case <-sigChan:
+			return
+		case <-ticker.C:
+			metricsState.Mu.RLock()
+			updated := metricsState.LastUpdate.After(lastPublish)
+			metricsState.Mu.RUnlock()
+			if updated {
+				updateWatchlistPins(metricsState)
+				metricsState.Mu.RLock()
+				tracker.Observe(metricsState.Processes, metricsState.Coalitions, time.Now())
+				metricsState.Mu.RUnlock()
+				if alertEngine != nil {
+					alertEngine.Evaluate(metricsState)
+				}
+				if exporter != nil {
+					exporter.Publish(metricsState)
+				}
+				if jsonExporter != nil {
+					if err := jsonExporter.Publish(metricsState); err != nil {
+						log.Printf("Error publishing JSON export: %v", err)
+					}
+				}
+				if outputSink != nil {
+					if err := outputSink.Publish(metricsState); err != nil {
+						log.Printf("Error publishing to --output sink(s): %v", err)
+					}
+				}
+				if remoteServer != nil {
+					if err := remoteServer.Publish(metricsState); err != nil {
+						log.Printf("Error publishing to --serve subscribers: %v", err)
+					}
+				}
+				lastPublish = time.Now()
+			}
+		}
+	}
+}
+
+// updateWatchlistPins re-evaluates the configured watchlist against the
+// latest sample's processes, refreshing both MetricsState.PinnedPIDs (which
+// HistorySamples consults) and each ProcessInfo's Pinned flag. A no-op when
+// no --watchlist-config was given.
+func updateWatchlistPins(state *models.MetricsState) {
+	if watchlistObj == nil {
+		return
+	}
+	state.Mu.Lock()
+	defer state.Mu.Unlock()
+
+	pinned := watchlistObj.PinnedPIDs(state.Processes)
+	state.PinnedPIDs = pinned
+	for i := range state.Processes {
+		state.Processes[i].Pinned = pinned[state.Processes[i].PID]
+	}
+}
+
+// buildCoalitionTree flattens state.Coalitions into a depth-first list for
+// DrawCoalitionTreePane, starting from the roots (ParentID ==
+// models.RootCoalitionID) and skipping the children of any coalition whose
+// ID is in collapsed.
+func buildCoalitionTree(state *models.MetricsState, collapsed, expandedSubprocs map[int]bool, sortBy models.CoalitionSortBy, showZeroCPU bool) []ui.CoalitionTreeNode {
+	childrenOf := make(map[int][]models.ProcessCoalition)
+	for _, c := range state.Coalitions {
+		childrenOf[c.ParentID] = append(childrenOf[c.ParentID], c)
+	}
+
+	var nodes []ui.CoalitionTreeNode
+	var walk func(c models.ProcessCoalition, depth int)
+	walk = func(c models.ProcessCoalition, depth int) {
+		children := childrenOf[c.CoalitionID]
+		node := ui.CoalitionTreeNode{
+			Coalition:    c,
+			Depth:        depth,
+			HasChildren:  len(children) > 0,
+			RollupCPU:    state.RollupCPU(c.CoalitionID),
+			RollupMemory: state.RollupMemory(c.CoalitionID),
+		}
+		if expandedSubprocs[c.CoalitionID] {
+			node.Subprocesses = c.SubprocessRows(sortBy, showZeroCPU)
+		}
+		nodes = append(nodes, node)
+		if collapsed[c.CoalitionID] {
+			return
+		}
+		for _, child := range children {
+			walk(child, depth+1)
+		}
+	}
+
+	for _, root := range childrenOf[models.RootCoalitionID] {
+		walk(root, 0)
+	}
+	return nodes
+}
+
 func determineSamplers() string {
 	if *combined {
 		return "all"
@@ -134,7 +769,7 @@ func determineSamplers() string {
 
 	// Determine initial view based on samplers
 	if result[0] == "all" || result[0] == "default" || *combined {
-		currentView = ui.ViewInterrupts  // Start with interrupts view when all samplers are enabled
+		currentView = ui.ViewInterrupts // Start with interrupts view when all samplers are enabled
 	} else if strings.Contains(result[0], "interrupts") {
 		currentView = ui.ViewInterrupts
 	} else if strings.Contains(result[0], "cpu_power") || strings.Contains(result[0], "gpu_power") {
@@ -148,61 +783,32 @@ func determineSamplers() string {
 	return strings.Join(result, ",")
 }
 
-func runPowerMetrics(samplerList string) {
-	for {
-		args := []string{
-			"powermetrics",
-			"--samplers", samplerList,
-			"-i", fmt.Sprintf("%d", *interval),
-			"-n", "1",
-		}
-
-		cmd := exec.Command("sudo", args...)
-		output, err := cmd.CombinedOutput()
-		if err != nil {
-			metricsState.Mu.Lock()
-			metricsState.UpdateErrors++
-			// Store error message for debugging
-			if *debug && len(output) > 0 {
-				fmt.Fprintf(os.Stderr, "powermetrics error: %s\n", string(output))
-			}
-			metricsState.Mu.Unlock()
-			time.Sleep(time.Duration(*interval) * time.Millisecond)
-			continue
-		}
-
-		if *debug {
-			fmt.Fprintf(os.Stderr, "powermetrics output (%d bytes)\n", len(output))
-			// Save to file for inspection
-			os.WriteFile("/tmp/powermetrics_debug.txt", output, 0644)
-		}
-
-		parser.ParsePowerMetricsOutput(string(output), metricsState)
-		metricsState.Mu.Lock()
-		metricsState.LastUpdate = time.Now()
-		metricsState.Mu.Unlock()
-
-		time.Sleep(time.Duration(*interval) * time.Millisecond)
-	}
-}
-
 func drawUI(screen tcell.Screen) {
 	screen.Clear()
 	width, height := screen.Size()
 
-	// Draw the menu bar at the top and get the next Y position
-	startY := ui.DrawCompactMenuBar(screen, width, currentView)
+	// Draw the menu bar at the top and get the next Y position.
+	// menuBar picks the most detailed layout that fits the terminal width
+	// (see MenuBarMode), falling back to a scrollable strip on the
+	// narrowest terminals instead of silently clipping tabs.
+	startY := menuBar.Draw(screen, width, currentView)
 
 	// Draw view based on current selection, starting from the correct Y position
 	switch currentView {
 	case ui.ViewInterrupts:
 		ui.DrawInterruptsViewWithHelp(screen, metricsState, width, height, showHelp, startY)
 	case ui.ViewPower:
-		ui.DrawPowerViewWithHelp(screen, metricsState, width, height, showHelp, startY)
+		ui.DrawPowerViewWithSmoothing(screen, metricsState, width, height, showHelp, startY, showSmoothed)
 	case ui.ViewFrequency:
 		ui.DrawFrequencyViewWithStartY(screen, metricsState, width, height, startY)
+	case ui.ViewIdleResidency:
+		ui.DrawIdleResidencyViewWithStartY(screen, metricsState, width, height, startY)
 	case ui.ViewProcesses:
-		ui.DrawProcessesViewWithStartY(screen, metricsState, width, height, startY)
+		displayFilter := processFilter
+		if processFilterMode {
+			displayFilter += "_" // cursor, so it's visible the filter is still being typed
+		}
+		ui.DrawProcessesViewWithStartY(screen, metricsState, width, height, startY, false, processSortBy, processSortReverse, displayFilter, &processScroll, &processSelected)
 	case ui.ViewNetwork:
 		ui.DrawNetworkViewWithStartY(screen, metricsState, width, height, startY)
 	case ui.ViewDisk:
@@ -215,6 +821,47 @@ func drawUI(screen tcell.Screen) {
 		ui.DrawSystemViewWithStartY(screen, metricsState, width, height, startY)
 	case ui.ViewCombined:
 		ui.DrawCombinedViewWithStartY(screen, metricsState, width, height, startY)
+	case ui.ViewCoreMap:
+		ui.DrawCoreMapViewWithStartY(screen, metricsState, width, height, startY, &coreMapSelectedRow, &coreMapSelectedCol)
+	case ui.ViewLayout:
+		if len(layoutRows) == 0 {
+			ui.DrawText(screen, 2, startY, "No layout configured.", tcell.StyleDefault.Foreground(tcell.ColorGray))
+			ui.DrawText(screen, 2, startY+2, "Pass --layout=FILE or create $XDG_CONFIG_HOME/powermetrics-tui/layout.", tcell.StyleDefault.Foreground(tcell.ColorGray))
+		} else {
+			layoutRenderer.Render(screen, metricsState, 0, startY, width, height-startY, layoutRows)
+		}
+	}
+
+	paneY := height - 1
+	if showTop {
+		topHeight := 12
+		paneY -= topHeight
+		metric := proctrack.Metrics[topMetricIdx]
+		window := proctrack.Windows[topWindowIdx]
+		metricsState.Mu.RLock()
+		pinned := metricsState.PinnedPIDs
+		metricsState.Mu.RUnlock()
+		entries := tracker.GetTopNPinned(proctrack.KindProcess, metric, window, 50, pinned)
+		ui.DrawTopPane(screen, 0, paneY, width, topHeight, entries, metric, windowLabel(window), topFilter)
+	}
+	if showTree {
+		treeHeight := 14
+		paneY -= treeHeight
+		metricsState.Mu.RLock()
+		lastTreeNodes = buildCoalitionTree(metricsState, treeCollapsed, treeExpandedSubprocs, treeSortBy, treeShowZeroCPU)
+		metricsState.Mu.RUnlock()
+		if treeCursor >= len(lastTreeNodes) {
+			treeCursor = len(lastTreeNodes) - 1
+		}
+		if treeCursor < 0 {
+			treeCursor = 0
+		}
+		ui.DrawCoalitionTreePane(screen, 0, paneY, width, treeHeight, lastTreeNodes, treeCursor, treeCollapsed)
+	}
+	if showAlerts && alertEngine != nil {
+		alertHeight := 8
+		paneY -= alertHeight
+		alertScroll = ui.DrawAlertsPane(screen, 0, paneY, width, alertHeight, alertEngine.Events(), alertScroll, alertEngine.Muted())
 	}
 
 	// Draw footer
@@ -223,13 +870,249 @@ func drawUI(screen tcell.Screen) {
 	screen.Show()
 }
 
+// toggleSessionRecording starts or stops a sessionrec capture, bound to the
+// 'R' key. Starting creates a timestamped .jsonl trace file; stopping
+// closes it and writes a sibling .html timeline report next to it (see
+// internal/sessionrec.WriteHTMLReport) for post-hoc analysis of the run.
+func toggleSessionRecording() {
+	if sessionRecorder != nil {
+		if err := sessionRecorder.Close(); err != nil {
+			log.Printf("Error closing session recording: %v", err)
+		}
+		htmlPath := strings.TrimSuffix(sessionRecordPath, ".jsonl") + ".html"
+		if err := sessionrec.WriteHTMLReport(sessionRecordPath, htmlPath); err != nil {
+			log.Printf("Error writing session report: %v", err)
+		} else {
+			log.Printf("Session recording saved to %s and %s", sessionRecordPath, htmlPath)
+		}
+		sessionRecorder = nil
+		sessionRecordPath = ""
+		return
+	}
+
+	path := fmt.Sprintf("session-%s.jsonl", time.Now().Format("20060102-150405"))
+	rec, err := sessionrec.NewRecorder(path)
+	if err != nil {
+		log.Printf("Error starting session recording: %v", err)
+		return
+	}
+	sessionRecorder = rec
+	sessionRecordPath = path
+}
+
+// loadLayoutSpec parses --layout (or, absent that flag, internal/layout's
+// default XDG path) into layoutRows, enabling ui.ViewLayout. Neither source
+// being present is not an error - the view just shows its "no layout
+// configured" message instead.
+func loadLayoutSpec() {
+	path := *layoutPath
+	if path == "" {
+		path = layout.DefaultPath()
+	}
+	if path == "" {
+		return
+	}
+
+	rows, err := layout.LoadSpec(path)
+	if err != nil {
+		if *layoutPath != "" {
+			log.Fatalf("Error loading layout spec %s: %v", path, err)
+		}
+		return // default path missing/invalid is not fatal
+	}
+	layoutRows = rows
+}
+
+// loadPlugins resolves --plugin-dir (or, absent that flag, plugins.DefaultDir's
+// default XDG path) and loads every .so panel it finds, registering each
+// into layoutRenderer under its own Name() and into loadedPanels so the main
+// loop can drive its Update. Neither source being present is not an error -
+// a per-plugin load failure is skipped rather than fatal, so one broken .so
+// doesn't keep the rest of the dashboard from starting; it is logged only
+// when --plugin-dir was given explicitly.
+func loadPlugins() {
+	dir := *pluginDir
+	if dir == "" {
+		dir = plugins.DefaultDir()
+	}
+	if dir == "" {
+		return
+	}
+
+	panels, errs := plugins.LoadDir(dir)
+	for _, err := range errs {
+		if *pluginDir != "" {
+			log.Printf("Error loading plugin: %v", err)
+		}
+	}
+	for _, p := range panels {
+		p := p
+		layoutRenderer.Register(p.Name(), func(screen tcell.Screen, state *models.MetricsState, x, y, w, h int) {
+			p.Draw(screen, x, y, w, h)
+		})
+		loadedPanels = append(loadedPanels, p)
+	}
+}
+
+// loadLocale resolves --lang (or, absent that flag, i18n.DetectLocale's
+// LC_ALL/LANG-derived guess) to a translation catalog and makes it active.
+// An unknown --lang is fatal, the same treatment --color gives an unknown
+// scheme name; a bad LC_ALL/LANG guess just falls back to en_US since the
+// user never asked for it explicitly.
+func loadLocale() {
+	locale := *langCode
+	if locale == "" {
+		locale = i18n.DetectLocale()
+	}
+
+	cat, err := i18n.Load(locale)
+	if err != nil {
+		if *langCode != "" {
+			log.Fatalf("Error loading locale %q: %v", locale, err)
+		}
+		return
+	}
+	ui.SetCatalog(cat)
+}
+
+// loadPIDFinder resolves --pid-finder to a procfind.Finder and makes it
+// active for both RunningTasksHandler's dead-PID name enrichment and the
+// Processes view's exited-PID verification, in place of each forking
+// ps/pgrep per PID. An unrecognized value falls through to procfind.New's
+// gopsutil default rather than failing fatally, since picking the wrong
+// backend here only costs a slower lookup, not a broken run.
+func loadPIDFinder() {
+	f := procfind.New(*pidFinderFlag)
+	parser.SetPIDFinder(f)
+	ui.SetFinder(f)
+	pidFinder = f
+}
+
+// loadProcCache loads --proc-cache (or proccache.DefaultPath's
+// ~/.cache/powermetrics-tui/procs.json) so --replay's StateMachine can
+// backfill ghost PIDs left over from a previous run. A missing file is the
+// common first-run case, not an error; a bad explicit --proc-cache path is
+// fatal, the same treatment --alerts-config and --config give a bad path.
+func loadProcCache() {
+	path := *procCachePath
+	if path == "" {
+		path = proccache.DefaultPath()
+	}
+	if path == "" {
+		return
+	}
+
+	cache, err := proccache.Load(path)
+	if err != nil {
+		log.Fatalf("Error loading process cache %q: %v", path, err)
+	}
+	procCache = cache
+}
+
+// loadEnricher makes parser.SetEnricher's default TTL-cached `ps`/libproc
+// enrichment active unless --no-enrich, in which case a
+// procenrich.NoopEnricher leaves every process with the raw
+// powermetrics-only fields.
+func loadEnricher() {
+	if *noEnrich {
+		parser.SetEnricher(procenrich.NoopEnricher{})
+	}
+}
+
+// loadColorScheme resolves --color/-c/--styleset (or its "default" flag
+// default) to a built-in colorschemes.Colorscheme, or a NAME.json file
+// under colorschemes.DefaultDir(), and makes it active. An unknown name is
+// fatal, the same treatment --alerts-config and --config give a bad path.
+//
+// --styleset is this package's name for the same mechanism, aliasing
+// --color/-c rather than implementing the tview/cview-backed styleset
+// format that name usually implies; see the package doc comment at the
+// top of internal/ui/views.go for why that migration is explicitly
+// descoped rather than attempted here.
+func loadColorScheme() {
+	name := *colorScheme
+	if *colorSchemeC != "" {
+		name = *colorSchemeC
+	}
+	if *styleset != "" {
+		name = *styleset
+	}
+
+	if cs, ok := colorschemes.Get(name); ok {
+		ui.SetScheme(cs)
+		return
+	}
+
+	path := filepath.Join(colorschemes.DefaultDir(), name+".json")
+	cs, err := colorschemes.LoadFromFile(path)
+	if err != nil {
+		log.Fatalf("Error loading colorscheme %q: %v", name, err)
+	}
+	ui.SetScheme(cs)
+}
+
+// loadMenuBarMode resolves --menu-bar-mode to a ui.MenuBarMode and sets it
+// on menuBar. An unknown mode is fatal, the same treatment --color gives
+// an unknown scheme name.
+func loadMenuBarMode() {
+	switch *menuBarMode {
+	case "auto":
+		menuBar.Mode = ui.ModeAuto
+	case "full":
+		menuBar.Mode = ui.ModeFull
+	case "compact":
+		menuBar.Mode = ui.ModeCompact
+	case "icons-only":
+		menuBar.Mode = ui.ModeIconsOnly
+	case "scroll":
+		menuBar.Mode = ui.ModeScroll
+	default:
+		log.Fatalf("Unknown --menu-bar-mode %q (want auto, full, compact, icons-only, or scroll)", *menuBarMode)
+	}
+}
+
+// windowLabel renders a rollup window as a short duration string for the
+// Top pane's title, e.g. "60s" or "5m".
+func windowLabel(d time.Duration) string {
+	switch d {
+	case proctrack.Window60s:
+		return "60s"
+	case proctrack.Window5m:
+		return "5m"
+	case proctrack.Window15m:
+		return "15m"
+	default:
+		return d.String()
+	}
+}
+
+// formatDuration renders d as "MM:SS" (or "H:MM:SS" past an hour) for the
+// --replay status line's position/duration.
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	total := int(d.Round(time.Second).Seconds())
+	h, m, s := total/3600, (total/60)%60, total%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
 func drawFooter(screen tcell.Screen, width, height int) {
-	footer := " 1-9,0: Jump to View | Tab: Next | H: Help | Q: Quit "
+	footer := " 1-9,0: Jump to View | Tab: Next | H: Help | T: Top | C: Tree | K: Scheme | R: Record | Q: Quit "
+	if alertEngine != nil {
+		footer = " 1-9,0: Jump to View | Tab: Next | H: Help | A: Alerts | T: Top | C: Tree | K: Scheme | R: Record | Q: Quit "
+	}
+	if sessionRecorder != nil {
+		footer += fmt.Sprintf("[● REC %s] ", sessionRecordPath)
+	}
 
 	// Show current view name
 	viewNames := []string{
-		"Interrupts", "Power", "Frequency", "Processes", "Network",
-		"Disk", "Thermal", "Battery", "System", "Combined",
+		"Interrupts", "Power", "Frequency", "Idle Residency", "Processes", "Network",
+		"Disk", "Thermal", "Battery", "System", "Combined", "Core Map", "Layout",
 	}
 
 	if int(currentView) < len(viewNames) {
@@ -242,6 +1125,45 @@ func drawFooter(screen tcell.Screen, width, height int) {
 		}
 	}
 
+	// Connection-status indicator for --connect, so a stalled or
+	// reconnecting upstream --serve is visible without digging into logs.
+	if remoteClient != nil {
+		status := remoteClient.Status()
+		var indicator string
+		style := tcell.StyleDefault.Foreground(tcell.ColorGreen)
+		if status.Connected {
+			indicator = fmt.Sprintf(" %s: %dms", *connectAddr, status.Latency.Milliseconds())
+			if status.Dropped > 0 {
+				indicator += fmt.Sprintf(" drop:%d", status.Dropped)
+			}
+		} else {
+			style = tcell.StyleDefault.Foreground(tcell.ColorRed)
+			indicator = fmt.Sprintf(" %s: reconnecting in %ds", *connectAddr, int(status.ReconnectIn.Seconds()))
+		}
+		ui.DrawText(screen, width-len(indicator)-2, 1, indicator, style)
+	}
+
+	// Playback position/duration for --replay, with ←/→/Space hinted since
+	// they aren't in the main footer (they only do anything in this mode).
+	if *replayPath != "" {
+		pos := replayControl.Position()
+		indicator := fmt.Sprintf(" %s: %s/%s (←/→: seek 10s, Space: pause) ", filepath.Base(*replayPath), formatDuration(pos), formatDuration(replayDuration))
+		if replayControl.Paused() {
+			indicator = " [PAUSED] " + indicator
+		}
+		ui.DrawText(screen, width-len(indicator)-2, 1, indicator, tcell.StyleDefault.Foreground(tcell.ColorAqua))
+	}
+
+	// Banner for any currently-armed alert, so a firing rule is visible
+	// even when the (toggleable, press A) Alerts pane is closed.
+	if alertEngine != nil {
+		if n := alertEngine.ActiveCount(); n > 0 {
+			banner := fmt.Sprintf(" ALERT: %d active (press A) ", n)
+			ui.DrawText(screen, width/2-len(banner)/2, height-1, banner,
+				tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true))
+		}
+	}
+
 	// Draw controls on the right
 	ui.DrawText(screen, width-len(footer)-2, height-1, footer,
 		tcell.StyleDefault.Foreground(tcell.ColorGray))
@@ -258,8 +1180,23 @@ func drawFooter(screen tcell.Screen, width, height int) {
 	}
 }
 
-func init() {
-	// Check if we have sudo access
+// hostname returns the local host name for a recording's header, falling
+// back to "unknown" if it can't be determined.
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return "unknown"
+	}
+	return name
+}
+
+// checkPrivileges verifies sudo access when the powermetrics backend will be
+// used. The gopsutil backend needs no elevated privileges.
+func checkPrivileges() {
+	if *backend == "gopsutil" || (*backend == "" && runtime.GOOS != "darwin") {
+		return
+	}
+
 	cmd := exec.Command("sudo", "-n", "true")
 	if err := cmd.Run(); err != nil {
 		scanner := bufio.NewScanner(os.Stdin)
@@ -270,4 +1207,40 @@ func init() {
 		scanner.Scan()
 		os.Exit(1)
 	}
-}
\ No newline at end of file
+}
+
+// serverTLSConfigFromFlags builds the TLS config --serve terminates
+// connections with, from --tls-cert/--tls-key. Both empty means plaintext;
+// either alone is an error, since a cert without its key (or vice versa)
+// can't be loaded.
+func serverTLSConfigFromFlags() (*tls.Config, error) {
+	if *tlsCertPath == "" && *tlsKeyPath == "" {
+		return nil, nil
+	}
+	if *tlsCertPath == "" || *tlsKeyPath == "" {
+		return nil, fmt.Errorf("--tls-cert and --tls-key must be given together")
+	}
+	cert, err := tls.LoadX509KeyPair(*tlsCertPath, *tlsKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS cert/key: %w", err)
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}}, nil
+}
+
+// clientTLSConfigFromFlags builds the TLS config --connect dials with.
+// --tls-cert here is a CA/server certificate to trust rather than this
+// host's own identity, so --tls-key is not used on the client side.
+func clientTLSConfigFromFlags() (*tls.Config, error) {
+	if *tlsCertPath == "" {
+		return nil, nil
+	}
+	pem, err := os.ReadFile(*tlsCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading --tls-cert: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in --tls-cert %s", *tlsCertPath)
+	}
+	return &tls.Config{RootCAs: pool}, nil
+}